@@ -0,0 +1,11 @@
+package audit
+
+import "errors"
+
+var (
+	// ErrRecordNotFound 審計記錄未找到（該秒沒有價格或已超過保存期限）
+	ErrRecordNotFound = errors.New("audit record not found")
+
+	// ErrDisabled 價格審計未啟用
+	ErrDisabled = errors.New("price audit is disabled")
+)
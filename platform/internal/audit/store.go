@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/model"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// keyPrefix 審計記錄 key 前綴，完整格式為 audit:price:{SYMBOL}:{YYYYMMDD}（UTC）
+	keyPrefix = "audit:price"
+
+	// writeTimeout 單次寫入審計記錄的逾時
+	writeTimeout = 2 * time.Second
+)
+
+// Store 聚合窗口價格審計記錄存儲
+// 每個商品每天一個 Redis Hash，field 為窗口開始時間（Unix 毫秒），value 為該窗口的候選價格、策略和選中價格
+// 舊版以 Unix 秒為 field 的記錄仍可讀取
+type Store struct {
+	client    *redis.Client
+	retention time.Duration
+}
+
+// NewStore 創建審計記錄存儲
+func NewStore(cfg *config.Config) (*Store, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+
+	// 測試連接
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", cfg.RedisAddr, err)
+	}
+
+	return &Store{
+		client:    client,
+		retention: cfg.AuditRetention,
+	}, nil
+}
+
+// Record 寫入一個窗口的審計記錄
+func (s *Store) Record(record *model.PriceAudit) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	day := time.UnixMilli(record.WindowStart).UTC()
+	key := dayKey(record.Symbol, day)
+
+	// 保存期限從該日結束開始計算，同一天的記錄一起過期
+	dayEnd := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, strconv.FormatInt(record.WindowStart, 10), data)
+	pipe.ExpireAt(ctx, key, dayEnd.Add(s.retention))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write audit record for %s at %d: %w", record.Symbol, record.WindowStart, err)
+	}
+
+	return nil
+}
+
+// Get 讀取指定商品在某個窗口（開始時間 Unix 毫秒）的審計記錄
+func (s *Store) Get(ctx context.Context, symbol string, windowStart int64) (*model.PriceAudit, error) {
+	key := dayKey(symbol, time.UnixMilli(windowStart).UTC())
+
+	fields := []string{strconv.FormatInt(windowStart, 10)}
+	if windowStart%1000 == 0 {
+		// 舊版記錄以 Unix 秒為 field
+		fields = append(fields, strconv.FormatInt(windowStart/1000, 10))
+	}
+
+	values, err := s.client.HMGet(ctx, key, fields...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit record for %s at %d: %w", symbol, windowStart, err)
+	}
+
+	var data []byte
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			data = []byte(str)
+			break
+		}
+	}
+	if data == nil {
+		return nil, ErrRecordNotFound
+	}
+
+	var record model.PriceAudit
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal audit record: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Close 關閉 Redis 連接
+func (s *Store) Close() error {
+	return s.client.Close()
+}
+
+// dayKey 某個商品某一天（UTC）的審計記錄 key
+func dayKey(symbol string, day time.Time) string {
+	return fmt.Sprintf("%s:%s:%s", keyPrefix, symbol, day.Format("20060102"))
+}
@@ -0,0 +1,14 @@
+package auth
+
+import "errors"
+
+var (
+	// ErrInvalidToken token 格式錯誤、簽章不符或缺少用戶 ID
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrTokenExpired token 已過期
+	ErrTokenExpired = errors.New("token expired")
+
+	// ErrDisabled 未設定 AUTH_TOKEN_SECRET，無法驗證 token
+	ErrDisabled = errors.New("token authentication is disabled")
+)
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+)
+
+// tokenQueryParam 瀏覽器的 WebSocket 無法設定 header，改以查詢參數帶入 token
+const tokenQueryParam = "access_token"
+
+// header JWT header
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// claims JWT 中使用的欄位
+type claims struct {
+	Subject   string `json:"sub"` // 用戶 ID
+	ExpiresAt int64  `json:"exp"` // 過期時間（Unix 秒）
+}
+
+// Verifier 驗證登入服務簽發的 token，取得已認證的用戶 ID
+//
+// token 為以 AUTH_TOKEN_SECRET 簽章的 HS256 JWT，sub 為用戶 ID，必須帶有 exp。
+// 只接受 HS256，避免 alg=none 等降級。
+type Verifier struct {
+	secret []byte
+}
+
+// NewVerifier 創建 token 驗證器
+func NewVerifier(cfg *config.Config) *Verifier {
+	return &Verifier{
+		secret: []byte(cfg.AuthTokenSecret),
+	}
+}
+
+// Verify 驗證 token 的簽章和期限，返回用戶 ID
+func (v *Verifier) Verify(token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return "", err
+	}
+	if h.Alg != "HS256" {
+		return "", fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, h.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if !hmac.Equal(signature, v.sign(parts[0]+"."+parts[1])) {
+		return "", fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return "", err
+	}
+	if c.Subject == "" || c.ExpiresAt == 0 {
+		return "", fmt.Errorf("%w: missing sub or exp", ErrInvalidToken)
+	}
+	if now.Unix() >= c.ExpiresAt {
+		return "", ErrTokenExpired
+	}
+
+	return c.Subject, nil
+}
+
+// Issue 簽發用戶的 token，用於測試和本機開發（正式環境由登入服務以相同的 secret 簽發）
+func (v *Verifier) Issue(userID string, expiresAt time.Time) string {
+	h, _ := json.Marshal(header{Alg: "HS256", Typ: "JWT"})
+	c, _ := json.Marshal(claims{Subject: userID, ExpiresAt: expiresAt.Unix()})
+
+	signing := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	return signing + "." + base64.RawURLEncoding.EncodeToString(v.sign(signing))
+}
+
+func (v *Verifier) sign(signing string) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(signing))
+	return mac.Sum(nil)
+}
+
+// TokenFromRequest 從 Authorization: Bearer header 或 access_token 查詢參數取得 token，沒有時為空字串
+func TokenFromRequest(r *http.Request) string {
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(bearer)
+	}
+	return r.URL.Query().Get(tokenQueryParam)
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return nil
+}
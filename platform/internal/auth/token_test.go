@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	v := NewVerifier(&config.Config{AuthTokenSecret: "secret"})
+	other := NewVerifier(&config.Config{AuthTokenSecret: "other"})
+
+	valid := v.Issue("test-user-001", now.Add(time.Hour))
+	parts := strings.Split(valid, ".")
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr error
+	}{
+		{name: "valid", token: valid, want: "test-user-001"},
+		{name: "expired", token: v.Issue("test-user-001", now), wantErr: ErrTokenExpired},
+		{name: "signed with another secret", token: other.Issue("test-user-001", now.Add(time.Hour)), wantErr: ErrInvalidToken},
+		{name: "tampered claims", token: parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","exp":1800000000}`)) + "." + parts[2], wantErr: ErrInvalidToken},
+		{name: "alg none", token: unsigned, wantErr: ErrInvalidToken},
+		{name: "missing subject", token: v.Issue("", now.Add(time.Hour)), wantErr: ErrInvalidToken},
+		{name: "malformed", token: "not-a-token", wantErr: ErrInvalidToken},
+		{name: "bad encoding", token: "!!.!!.!!", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Verify(tt.token, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenFromRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		header string
+		want   string
+	}{
+		{name: "none", target: "/ws/prices"},
+		{name: "bearer header", target: "/ws/prices", header: "Bearer abc.def.ghi", want: "abc.def.ghi"},
+		{name: "query parameter", target: "/ws/prices?access_token=abc.def.ghi", want: "abc.def.ghi"},
+		{name: "header takes precedence", target: "/ws/prices?access_token=query", header: "Bearer header", want: "header"},
+		{name: "user_id is not an identity", target: "/ws/prices?user_id=test-user-001"},
+		{name: "non-bearer header", target: "/ws/prices", header: "Basic dXNlcjpwYXNz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.target, nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			if got := TokenFromRequest(r); got != tt.want {
+				t.Errorf("TokenFromRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
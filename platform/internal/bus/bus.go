@@ -0,0 +1,58 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// 支援的訊息匯流排
+const (
+	KindRedis = "redis" // Redis Pub/Sub 或 Stream（REDIS_TRANSPORT）
+	KindNATS  = "nats"  // NATS，可選用 JetStream
+)
+
+// Ack 確認訊息已處理完成，持久化的傳輸（Redis Stream、JetStream）收到確認前會保留訊息
+type Ack func()
+
+// Handler 價格事件處理回調函數
+// ack 不為 nil 時，handler 需在價格所屬的窗口推送後（或價格被丟棄時）調用；
+// 未調用的訊息在重啟或重連後會重新投遞。沒有確認機制的傳輸傳入 nil
+type Handler func(price *model.Price, ack Ack)
+
+// Subscriber 價格事件訂閱者
+type Subscriber interface {
+	// Subscribe 開始接收價格事件並調用 handler，阻塞直到 ctx 取消或發生無法恢復的錯誤
+	Subscribe(ctx context.Context, handler Handler) error
+	// Ping 檢查與訊息匯流排的連線
+	Ping(ctx context.Context) error
+	// Close 關閉連線
+	Close() error
+}
+
+// SymbolFollower 可依客戶端關注的商品按需訂閱的訂閱者
+// Follow 在商品從沒有客戶端關注變為有人關注時調用，Unfollow 在最後一個客戶端取消關注時調用
+type SymbolFollower interface {
+	Follow(symbol string) error
+	Unfollow(symbol string) error
+}
+
+// Publisher 價格事件發布者
+type Publisher interface {
+	Publish(ctx context.Context, price *model.Price) error
+	Close() error
+}
+
+// NewSubscriber 依 MESSAGE_BUS 設定創建訂閱者
+func NewSubscriber(cfg *config.Config) (Subscriber, error) {
+	switch cfg.MessageBus {
+	case KindRedis:
+		return NewRedisSubscriber(cfg)
+	case KindNATS:
+		return NewNATSSubscriber(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported message bus: %s", cfg.MessageBus)
+	}
+}
@@ -0,0 +1,72 @@
+package bus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+	pb "github.com/mike/golden-buy/platform/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// SchemaVersion 支援的價格傳輸格式版本，需與 Price Service 一致
+// 版本 0 為舊版無版本的 JSON；版本 2 新增 heartbeat；較新版本只讀取已知欄位
+const SchemaVersion = 2
+
+// ErrInvalidPayload 無法解析或缺少必要欄位的價格資料
+var ErrInvalidPayload = errors.New("invalid price payload")
+
+// jsonPayload JSON 編碼的價格事件，欄位名稱與 pb.PriceUpdate 的 proto 欄位名稱一致
+type jsonPayload struct {
+	SchemaVersion int32   `json:"schema_version"`
+	SourceID      string  `json:"source_id"`
+	Symbol        string  `json:"symbol"`
+	Price         float64 `json:"price"`
+	Timestamp     int64   `json:"timestamp"` // Unix 毫秒
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	Heartbeat     bool    `json:"heartbeat"`
+}
+
+// DecodePrice 解析價格事件，自動辨識 protobuf 和 JSON（包含舊版無版本的 JSON）
+// protobuf 的 PriceUpdate 不可能以 '{' 開頭（對應未使用的 group 欄位 15）
+func DecodePrice(data []byte) (*model.Price, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidPayload)
+	}
+
+	var update pb.PriceUpdate
+	if data[0] == '{' {
+		var payload jsonPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+
+		update = pb.PriceUpdate{
+			Symbol:        payload.Symbol,
+			Price:         payload.Price,
+			Timestamp:     payload.Timestamp,
+			Change:        payload.Change,
+			ChangePercent: payload.ChangePercent,
+			SchemaVersion: payload.SchemaVersion,
+			SourceId:      payload.SourceID,
+			Heartbeat:     payload.Heartbeat,
+		}
+	} else if err := proto.Unmarshal(data, &update); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	if update.Symbol == "" || update.Timestamp <= 0 {
+		return nil, fmt.Errorf("%w: missing symbol or timestamp", ErrInvalidPayload)
+	}
+
+	return &model.Price{
+		Symbol:        update.Symbol,
+		Price:         update.Price,
+		Timestamp:     update.Timestamp,
+		Change:        update.Change,
+		ChangePercent: update.ChangePercent,
+		Heartbeat:     update.Heartbeat,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package bus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+	pb "github.com/mike/golden-buy/platform/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDecodePrice(t *testing.T) {
+	protoPrice := func(update *pb.PriceUpdate) []byte {
+		data, err := proto.Marshal(update)
+		if err != nil {
+			t.Fatalf("proto.Marshal: %v", err)
+		}
+		return data
+	}
+
+	gold := &model.Price{Symbol: "GOLD", Price: 1850.5, Timestamp: 1700000000000, Change: 1.5, ChangePercent: 0.08}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *model.Price
+		wantErr error
+	}{
+		{
+			name: "legacy json without version",
+			data: []byte(`{"symbol":"GOLD","price":1850.5,"timestamp":1700000000000,"change":1.5,"change_percent":0.08}`),
+			want: gold,
+		},
+		{
+			name: "json v2",
+			data: []byte(`{"schema_version":2,"source_id":"price-1","symbol":"GOLD","price":1850.5,"timestamp":1700000000000,"change":1.5,"change_percent":0.08}`),
+			want: gold,
+		},
+		{
+			name: "json heartbeat",
+			data: []byte(`{"schema_version":2,"symbol":"GOLD","timestamp":1700000000000,"heartbeat":true}`),
+			want: &model.Price{Symbol: "GOLD", Timestamp: 1700000000000, Heartbeat: true},
+		},
+		{
+			name: "json from a newer version keeps known fields",
+			data: []byte(`{"schema_version":3,"symbol":"GOLD","price":1850.5,"timestamp":1700000000000,"change":1.5,"change_percent":0.08,"bid":1850.4}`),
+			want: gold,
+		},
+		{
+			name: "protobuf",
+			data: protoPrice(&pb.PriceUpdate{
+				SchemaVersion: SchemaVersion,
+				SourceId:      "price-1",
+				Symbol:        "GOLD",
+				Price:         1850.5,
+				Timestamp:     1700000000000,
+				Change:        1.5,
+				ChangePercent: 0.08,
+			}),
+			want: gold,
+		},
+		{
+			name: "protobuf heartbeat",
+			data: protoPrice(&pb.PriceUpdate{SchemaVersion: SchemaVersion, Symbol: "GOLD", Timestamp: 1700000000000, Heartbeat: true}),
+			want: &model.Price{Symbol: "GOLD", Timestamp: 1700000000000, Heartbeat: true},
+		},
+		{name: "empty", data: nil, wantErr: ErrInvalidPayload},
+		{name: "malformed json", data: []byte(`{"symbol":`), wantErr: ErrInvalidPayload},
+		{name: "malformed protobuf", data: []byte{0xff, 0xff, 0xff}, wantErr: ErrInvalidPayload},
+		{name: "missing symbol", data: []byte(`{"price":1850.5,"timestamp":1700000000000}`), wantErr: ErrInvalidPayload},
+		{name: "missing timestamp", data: protoPrice(&pb.PriceUpdate{Symbol: "GOLD", Price: 1850.5}), wantErr: ErrInvalidPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePrice(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodePrice() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("DecodePrice() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
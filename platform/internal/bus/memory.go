@@ -0,0 +1,64 @@
+package bus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// Memory 行程內訊息匯流排，同時實作 Publisher 和 Subscriber
+// Publish 會同步調用所有訂閱者的 handler，只用於測試：Price Service 在另一個行程，無法發布到行程內匯流排
+type Memory struct {
+	mu       sync.RWMutex
+	handlers map[int]Handler
+	nextID   int
+}
+
+// NewMemory 創建行程內訊息匯流排
+func NewMemory() *Memory {
+	return &Memory{
+		handlers: make(map[int]Handler),
+	}
+}
+
+// Publish 發布價格事件給所有訂閱者
+func (m *Memory) Publish(ctx context.Context, price *model.Price) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, handler := range m.handlers {
+		p := *price // 每個訂閱者收到獨立的副本
+		handler(&p, nil)
+	}
+
+	return nil
+}
+
+// Subscribe 註冊 handler，阻塞直到 ctx 取消
+func (m *Memory) Subscribe(ctx context.Context, handler Handler) error {
+	m.mu.Lock()
+	id := m.nextID
+	m.nextID++
+	m.handlers[id] = handler
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.handlers, id)
+		m.mu.Unlock()
+	}()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Ping 行程內匯流排永遠可用
+func (m *Memory) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close 行程內匯流排沒有需要關閉的連線
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package bus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+func TestMemory(t *testing.T) {
+	tests := []struct {
+		name        string
+		subscribers int
+	}{
+		{name: "no subscribers", subscribers: 0},
+		{name: "one subscriber", subscribers: 1},
+		{name: "fan out", subscribers: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemory()
+			ctx, cancel := context.WithCancel(context.Background())
+
+			channels := make([]chan received, tt.subscribers)
+			done := make(chan error, tt.subscribers)
+			for i := range channels {
+				ch := make(chan received, 1)
+				channels[i] = ch
+				go func() {
+					done <- m.Subscribe(ctx, func(price *model.Price, ack Ack) {
+						ch <- received{price: price, ack: ack}
+					})
+				}()
+			}
+			waitForHandlers(t, m, tt.subscribers)
+
+			price := &model.Price{Symbol: "GOLD", Price: 2034.5, Timestamp: 1700000000000}
+			if err := m.Publish(ctx, price); err != nil {
+				t.Fatalf("Publish: %v", err)
+			}
+
+			seen := make(map[*model.Price]bool)
+			for _, ch := range channels {
+				r := receive(t, ch)
+				if *r.price != *price {
+					t.Errorf("price = %+v, want %+v", *r.price, *price)
+				}
+				if r.ack != nil {
+					t.Error("memory bus should not pass an ack")
+				}
+				// 每個訂閱者收到獨立的副本
+				if r.price == price || seen[r.price] {
+					t.Error("subscribers share the same price value")
+				}
+				seen[r.price] = true
+			}
+
+			cancel()
+			for range channels {
+				if err := <-done; err != context.Canceled {
+					t.Errorf("Subscribe returned %v, want context.Canceled", err)
+				}
+			}
+			waitForHandlers(t, m, 0)
+		})
+	}
+}
+
+// waitForHandlers 等待註冊的 handler 數量達到 n
+func waitForHandlers(t *testing.T, m *Memory, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		m.mu.RLock()
+		count := len(m.handlers)
+		m.mu.RUnlock()
+
+		if count == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("handlers = %d, want %d", count, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,146 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// natsStreamMaxAge JetStream stream 保留訊息的時間，需與 Price Service 一致
+	natsStreamMaxAge = time.Hour
+
+	// natsMinAckWait JetStream 等待 ack 的最短時間（NATS 預設值），超過即重新投遞
+	natsMinAckWait = 30 * time.Second
+)
+
+// NATSSubscriber 透過 NATS（可選 JetStream）接收價格事件
+type NATSSubscriber struct {
+	conn    *nats.Conn
+	cfg     *config.Config
+	ackWait time.Duration // JetStream 等待 ack 的時間
+}
+
+// NewNATSSubscriber 創建 NATS 訂閱者
+func NewNATSSubscriber(cfg *config.Config) (*NATSSubscriber, error) {
+	conn, err := nats.Connect(cfg.NATSURL,
+		nats.Name("golden-buy-platform"),
+		nats.Timeout(5*time.Second),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats at %s: %w", cfg.NATSURL, err)
+	}
+
+	// 訊息最長在最長的聚合窗口加上允許遲到時間後 ack，等待時間需大於此才不會在推送前重新投遞
+	return &NATSSubscriber{
+		conn:    conn,
+		cfg:     cfg,
+		ackWait: max(natsMinAckWait, 2*(cfg.LongestWindow()+cfg.AggregationLateness)),
+	}, nil
+}
+
+// Subscribe 訂閱價格 subject；啟用 JetStream 時以 durable consumer 讀取並逐筆 ack
+func (n *NATSSubscriber) Subscribe(ctx context.Context, handler Handler) error {
+	if n.cfg.NATSJetStream {
+		return n.consumeJetStream(ctx, handler)
+	}
+
+	sub, err := n.conn.Subscribe(n.cfg.NATSSubject, func(msg *nats.Msg) {
+		price, err := DecodePrice(msg.Data)
+		if err != nil {
+			log.Printf("❌ Failed to decode price: %v", err)
+			return
+		}
+
+		handler(price, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to subject %s: %w", n.cfg.NATSSubject, err)
+	}
+	defer sub.Unsubscribe()
+
+	log.Printf("✅ Subscribed to NATS subject: %s", n.cfg.NATSSubject)
+
+	<-ctx.Done()
+	log.Println("📴 Subscriber context cancelled")
+	return ctx.Err()
+}
+
+// consumeJetStream 以 durable consumer 讀取 JetStream
+// 只讀取建立 consumer 之後的訊息，重啟後從上次 ack 的位置繼續；
+// 訊息在所屬窗口推送後才 ack，推送前中斷的訊息會重新投遞
+func (n *NATSSubscriber) consumeJetStream(ctx context.Context, handler Handler) error {
+	js, err := jetstream.New(n.conn)
+	if err != nil {
+		return fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     n.cfg.NATSStream,
+		Subjects: []string{n.cfg.NATSSubject},
+		MaxAge:   natsStreamMaxAge,
+	}); err != nil {
+		return fmt.Errorf("failed to create stream %s: %w", n.cfg.NATSStream, err)
+	}
+
+	// 未 ack 的訊息數量受窗口長度限制，不另設上限
+	consumer, err := js.CreateOrUpdateConsumer(ctx, n.cfg.NATSStream, jetstream.ConsumerConfig{
+		Durable:       n.cfg.NATSDurable,
+		FilterSubject: n.cfg.NATSSubject,
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       n.ackWait,
+		MaxAckPending: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s on %s: %w", n.cfg.NATSDurable, n.cfg.NATSStream, err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		// 是否遲到由訂閱器的事件時間窗口判斷，重新投遞的訊息同樣交給 handler
+		ack := func() {
+			if err := msg.Ack(); err != nil {
+				log.Printf("❌ Failed to ack message: %v", err)
+			}
+		}
+
+		price, err := DecodePrice(msg.Data())
+		if err != nil {
+			log.Printf("❌ Failed to decode price: %v", err)
+			ack()
+			return
+		}
+
+		handler(price, ack)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume stream %s: %w", n.cfg.NATSStream, err)
+	}
+	defer consumeCtx.Stop()
+
+	log.Printf("✅ Consuming NATS JetStream %s (durable: %s)", n.cfg.NATSStream, n.cfg.NATSDurable)
+
+	<-ctx.Done()
+	log.Println("📴 Subscriber context cancelled")
+	return ctx.Err()
+}
+
+// Ping 檢查 NATS 連接是否正常
+func (n *NATSSubscriber) Ping(ctx context.Context) error {
+	if !n.conn.IsConnected() {
+		return fmt.Errorf("nats not connected: %s", n.conn.Status())
+	}
+	return nil
+}
+
+// Close 關閉 NATS 連接
+func (n *NATSSubscriber) Close() error {
+	n.conn.Close()
+	return nil
+}
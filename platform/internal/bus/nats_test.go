@@ -0,0 +1,289 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/model"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// received handler 收到的價格和對應的 ack
+type received struct {
+	price *model.Price
+	ack   Ack
+}
+
+// runNATSServer 啟動嵌入式 NATS 伺服器，測試結束時關閉
+func runNATSServer(t *testing.T, jetStream bool) *server.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: jetStream,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	return srv
+}
+
+func natsConfig(url string, jetStream bool) *config.Config {
+	return &config.Config{
+		NATSURL:             url,
+		NATSSubject:         "price.updates",
+		NATSJetStream:       jetStream,
+		NATSStream:          "PRICES",
+		NATSDurable:         "platform-test",
+		AggregationWindow:   time.Second,
+		AggregationLateness: 500 * time.Millisecond,
+	}
+}
+
+// subscribe 在背景訂閱，返回收到價格的 channel 和停止訂閱的函數（等待 Subscribe 返回）
+func subscribe(t *testing.T, sub Subscriber) (<-chan received, func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan received, 16)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- sub.Subscribe(ctx, func(price *model.Price, ack Ack) {
+			ch <- received{price: price, ack: ack}
+		})
+	}()
+
+	stop := func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Subscribe did not return after cancel")
+		}
+	}
+	t.Cleanup(cancel)
+
+	return ch, stop
+}
+
+func receive(t *testing.T, ch <-chan received) received {
+	t.Helper()
+
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for price")
+		return received{}
+	}
+}
+
+func expectNone(t *testing.T, ch <-chan received, wait time.Duration) {
+	t.Helper()
+
+	select {
+	case r := <-ch:
+		t.Fatalf("unexpected price: %+v", r.price)
+	case <-time.After(wait):
+	}
+}
+
+func jsonPrice(symbol string, price float64, timestamp int64) []byte {
+	return []byte(fmt.Sprintf(`{"schema_version":2,"symbol":%q,"price":%v,"timestamp":%d}`, symbol, price, timestamp))
+}
+
+func TestNATSSubscriberCore(t *testing.T) {
+	srv := runNATSServer(t, false)
+	cfg := natsConfig(srv.ClientURL(), false)
+
+	sub, err := NewNATSSubscriber(cfg)
+	if err != nil {
+		t.Fatalf("NewNATSSubscriber: %v", err)
+	}
+	defer sub.Close()
+
+	ch, stop := subscribe(t, sub)
+	defer stop()
+
+	// 等待訂閱建立，之前發布的訊息不會收到
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.NumSubscriptions() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	pub, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("connect publisher: %v", err)
+	}
+	defer pub.Close()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		want    *model.Price // nil 表示無法解析，不會交給 handler
+	}{
+		{
+			name:    "json",
+			payload: jsonPrice("GOLD", 2034.5, 1700000000000),
+			want:    &model.Price{Symbol: "GOLD", Price: 2034.5, Timestamp: 1700000000000},
+		},
+		{
+			name:    "invalid payload",
+			payload: []byte("not a price"),
+		},
+		{
+			name:    "heartbeat",
+			payload: []byte(`{"schema_version":2,"symbol":"SILVER","timestamp":1700000000500,"heartbeat":true}`),
+			want:    &model.Price{Symbol: "SILVER", Timestamp: 1700000000500, Heartbeat: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := pub.Publish(cfg.NATSSubject, tt.payload); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+			if err := pub.Flush(); err != nil {
+				t.Fatalf("flush: %v", err)
+			}
+
+			if tt.want == nil {
+				expectNone(t, ch, 200*time.Millisecond)
+				return
+			}
+
+			r := receive(t, ch)
+			if *r.price != *tt.want {
+				t.Errorf("price = %+v, want %+v", *r.price, *tt.want)
+			}
+			if r.ack != nil {
+				t.Error("core NATS should not pass an ack")
+			}
+		})
+	}
+
+	if err := sub.Ping(context.Background()); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+}
+
+func TestNATSSubscriberJetStreamAck(t *testing.T) {
+	srv := runNATSServer(t, true)
+	cfg := natsConfig(srv.ClientURL(), true)
+
+	pub, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("connect publisher: %v", err)
+	}
+	defer pub.Close()
+
+	js, err := jetstream.New(pub)
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	newSubscriber := func() *NATSSubscriber {
+		sub, err := NewNATSSubscriber(cfg)
+		if err != nil {
+			t.Fatalf("NewNATSSubscriber: %v", err)
+		}
+		sub.ackWait = time.Second // 縮短重新投遞的等待時間
+		t.Cleanup(func() { sub.Close() })
+		return sub
+	}
+
+	consumerInfo := func() *jetstream.ConsumerInfo {
+		t.Helper()
+
+		consumer, err := js.Consumer(ctx, cfg.NATSStream, cfg.NATSDurable)
+		if err != nil {
+			t.Fatalf("consumer: %v", err)
+		}
+		info, err := consumer.Info(ctx)
+		if err != nil {
+			t.Fatalf("consumer info: %v", err)
+		}
+		return info
+	}
+
+	// 第一個訂閱者收到價格但在窗口推送前停止，未 ack
+	first, stopFirst := subscribe(t, newSubscriber())
+	waitForConsumer(t, ctx, js, cfg)
+
+	if _, err := js.Publish(ctx, cfg.NATSSubject, jsonPrice("GOLD", 2034.5, 1700000000000)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	r := receive(t, first)
+	if r.ack == nil {
+		t.Fatal("JetStream should pass an ack")
+	}
+	if got := consumerInfo().NumAckPending; got != 1 {
+		t.Errorf("NumAckPending before ack = %d, want 1", got)
+	}
+	stopFirst()
+
+	// 重啟後同一 durable 重新投遞未 ack 的訊息
+	second, stopSecond := subscribe(t, newSubscriber())
+	defer stopSecond()
+
+	r = receive(t, second)
+	if r.price.Symbol != "GOLD" || r.price.Timestamp != 1700000000000 {
+		t.Errorf("redelivered price = %+v", *r.price)
+	}
+	r.ack()
+
+	// ack 後不再重新投遞
+	expectNone(t, second, 2*time.Second)
+	if got := consumerInfo().NumAckPending; got != 0 {
+		t.Errorf("NumAckPending after ack = %d, want 0", got)
+	}
+
+	// 無法解析的訊息直接 ack，不交給 handler
+	if _, err := js.Publish(ctx, cfg.NATSSubject, []byte("not a price")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	expectNone(t, second, 2*time.Second)
+	if info := consumerInfo(); info.NumAckPending != 0 || info.AckFloor.Stream != 2 {
+		t.Errorf("after invalid payload: pending %d, ack floor %d, want 0, 2", info.NumAckPending, info.AckFloor.Stream)
+	}
+}
+
+// waitForConsumer 等待訂閱者建立 durable consumer，之前發布的訊息不會投遞（DeliverNewPolicy）
+func waitForConsumer(t *testing.T, ctx context.Context, js jetstream.JetStream, cfg *config.Config) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := js.Consumer(ctx, cfg.NATSStream, cfg.NATSDurable); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("consumer not created")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,363 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// PriceUpdatesChannel Redis Pub/Sub 彙總頻道名稱（Price Service 需開啟 REDIS_AGGREGATE_CHANNEL）
+	PriceUpdatesChannel = "price:updates"
+
+	// PriceUpdatesChannelPrefix 每個商品的 Pub/Sub 頻道前綴，完整格式為 price:updates:{SYMBOL}
+	PriceUpdatesChannelPrefix = "price:updates:"
+
+	// PriceUpdatesPattern 所有商品頻道的 PSUBSCRIBE pattern
+	PriceUpdatesPattern = PriceUpdatesChannelPrefix + "*"
+
+	// PriceUpdatesStream Redis Stream key（REDIS_TRANSPORT=stream）
+	PriceUpdatesStream = "price:updates:stream"
+
+	// TransportStream 使用 Redis Stream consumer group 傳輸價格更新
+	TransportStream = "stream"
+
+	// SubscribePattern 以 PSUBSCRIBE 接收所有商品頻道
+	SubscribePattern = "pattern"
+
+	// SubscribeOnDemand 只訂閱目前有 WebSocket 客戶端關注的商品頻道
+	SubscribeOnDemand = "on_demand"
+
+	// SubscribeAggregate 訂閱彙總頻道 price:updates
+	SubscribeAggregate = "aggregate"
+
+	// streamReadCount 每次 XREADGROUP / XAUTOCLAIM 讀取的最大訊息數
+	streamReadCount = 100
+
+	// streamBlock XREADGROUP 等待新訊息的時間
+	streamBlock = time.Second
+
+	// healthCheckInterval Pub/Sub 訂閱中檢查 Redis 連線的間隔
+	healthCheckInterval = time.Second
+
+	// healthCheckTimeout 單次連線檢查的逾時
+	healthCheckTimeout = time.Second
+
+	// ackTimeout 單次 XACK 的逾時
+	ackTimeout = time.Second
+
+	// followTimeout on_demand 模式下單次 SUBSCRIBE / UNSUBSCRIBE 的逾時
+	followTimeout = 2 * time.Second
+)
+
+// RedisSubscriber 透過 Redis Pub/Sub 或 Stream 接收價格事件
+type RedisSubscriber struct {
+	client   *redis.Client
+	cfg      *config.Config
+	mu       sync.Mutex
+	pubsub   *redis.PubSub   // Pub/Sub 傳輸訂閱中的連線
+	followed map[string]bool // 有客戶端關注的商品（on_demand 模式）
+}
+
+// NewRedisSubscriber 創建 Redis 訂閱者
+func NewRedisSubscriber(cfg *config.Config) (*RedisSubscriber, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+
+	// 測試連接
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", cfg.RedisAddr, err)
+	}
+
+	return &RedisSubscriber{
+		client:   client,
+		cfg:      cfg,
+		followed: make(map[string]bool),
+	}, nil
+}
+
+// Subscribe 依 REDIS_TRANSPORT 訂閱 Pub/Sub 頻道或以 consumer group 讀取 Stream
+func (r *RedisSubscriber) Subscribe(ctx context.Context, handler Handler) error {
+	if r.cfg.RedisTransport == TransportStream {
+		// 建立 consumer group（已存在則沿用，從上次處理的位置繼續）
+		if err := r.createStreamGroup(ctx); err != nil {
+			return err
+		}
+
+		log.Printf("✅ Consuming Redis stream %s (group: %s, consumer: %s)",
+			PriceUpdatesStream, r.cfg.RedisStreamGroup, r.cfg.RedisStreamConsumer)
+		return r.consumeStream(ctx, handler)
+	}
+
+	pubsub, err := r.subscribeChannels(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		r.mu.Lock()
+		r.pubsub = nil
+		r.mu.Unlock()
+		pubsub.Close()
+	}()
+
+	// 定期檢查連線：Redis 重啟時 go-redis 會在背景重連，但期間的訊息會遺失，
+	// 所以連線中斷時直接返回，由呼叫端切換備援來源並重新訂閱
+	health := time.NewTicker(healthCheckInterval)
+	defer health.Stop()
+
+	// 接收訊息
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("📴 Subscriber context cancelled")
+			return ctx.Err()
+
+		case <-health.C:
+			pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			err := r.client.Ping(pingCtx).Err()
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				return fmt.Errorf("redis connection lost: %w", err)
+			}
+
+		case msg, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("redis channel closed")
+			}
+
+			// 解析價格更新
+			price, err := DecodePrice([]byte(msg.Payload))
+			if err != nil {
+				log.Printf("❌ Failed to decode price: %v", err)
+				continue
+			}
+
+			handler(price, nil)
+		}
+	}
+}
+
+// subscribeChannels 依 REDIS_SUBSCRIBE_MODE 訂閱 Pub/Sub 頻道
+func (r *RedisSubscriber) subscribeChannels(ctx context.Context) (*redis.PubSub, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var channels []string
+	switch r.cfg.RedisSubscribeMode {
+	case SubscribeAggregate:
+		channels = []string{PriceUpdatesChannel}
+		r.pubsub = r.client.Subscribe(ctx, PriceUpdatesChannel)
+	case SubscribeOnDemand:
+		for symbol := range r.followed {
+			channels = append(channels, PriceUpdatesChannelPrefix+symbol)
+		}
+		r.pubsub = r.client.Subscribe(ctx, channels...)
+	default:
+		channels = []string{PriceUpdatesPattern}
+		r.pubsub = r.client.PSubscribe(ctx, PriceUpdatesPattern)
+	}
+
+	// 確認訂閱成功（on_demand 模式尚無關注的商品時沒有需要確認的訂閱）
+	if len(channels) > 0 {
+		if _, err := r.pubsub.Receive(ctx); err != nil {
+			r.pubsub.Close()
+			r.pubsub = nil
+			return nil, fmt.Errorf("failed to subscribe to %v: %w", channels, err)
+		}
+	}
+
+	log.Printf("✅ Subscribed to Redis (mode: %s): %v", r.cfg.RedisSubscribeMode, channels)
+	return r.pubsub, nil
+}
+
+// Follow 商品開始有客戶端關注，on_demand 模式下訂閱該商品頻道
+// 訂閱逾時或失敗時 go-redis 仍會記錄該頻道，連線重建後自動重新訂閱
+func (r *RedisSubscriber) Follow(symbol string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.followed[symbol] {
+		return nil
+	}
+	r.followed[symbol] = true
+
+	if r.cfg.RedisSubscribeMode != SubscribeOnDemand || r.pubsub == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), followTimeout)
+	defer cancel()
+
+	log.Printf("➕ Subscribing to Redis channel %s%s", PriceUpdatesChannelPrefix, symbol)
+	return r.pubsub.Subscribe(ctx, PriceUpdatesChannelPrefix+symbol)
+}
+
+// Unfollow 商品已沒有客戶端關注，on_demand 模式下取消訂閱該商品頻道
+func (r *RedisSubscriber) Unfollow(symbol string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.followed[symbol] {
+		return nil
+	}
+	delete(r.followed, symbol)
+
+	if r.cfg.RedisSubscribeMode != SubscribeOnDemand || r.pubsub == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), followTimeout)
+	defer cancel()
+
+	log.Printf("➖ Unsubscribing from Redis channel %s%s", PriceUpdatesChannelPrefix, symbol)
+	return r.pubsub.Unsubscribe(ctx, PriceUpdatesChannelPrefix+symbol)
+}
+
+// createStreamGroup 建立 consumer group，新建時只讀取之後寫入的訊息
+func (r *RedisSubscriber) createStreamGroup(ctx context.Context) error {
+	err := r.client.XGroupCreateMkStream(ctx, PriceUpdatesStream, r.cfg.RedisStreamGroup, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s on %s: %w", r.cfg.RedisStreamGroup, PriceUpdatesStream, err)
+	}
+	return nil
+}
+
+// consumeStream 以 consumer group 讀取 Stream
+// 先讀取本 consumer 已讀取但尚未 ack 的訊息（上次中斷前未處理完），再讀取新訊息；
+// 並定期接手其他 consumer 閒置過久未 ack 的訊息
+func (r *RedisSubscriber) consumeStream(ctx context.Context, handler Handler) error {
+	claimTicker := time.NewTicker(r.cfg.RedisStreamClaimIdle)
+	defer claimTicker.Stop()
+
+	// "0" 表示從本 consumer 的 pending 清單開始，讀完後切換到 ">"（新訊息）
+	lastID := "0"
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("📴 Subscriber context cancelled")
+			return ctx.Err()
+		case <-claimTicker.C:
+			r.reclaimPending(ctx, handler)
+		default:
+		}
+
+		streams, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    r.cfg.RedisStreamGroup,
+			Consumer: r.cfg.RedisStreamConsumer,
+			Streams:  []string{PriceUpdatesStream, lastID},
+			Count:    streamReadCount,
+			Block:    streamBlock,
+		}).Result()
+		if err == redis.Nil {
+			continue // 等待逾時，沒有新訊息
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read stream %s: %w", PriceUpdatesStream, err)
+		}
+
+		for _, stream := range streams {
+			if lastID != ">" && len(stream.Messages) == 0 {
+				log.Printf("✅ Pending messages replayed, reading new messages")
+				lastID = ">"
+				continue
+			}
+
+			for _, msg := range stream.Messages {
+				r.handleStreamMessage(msg, handler)
+				if lastID != ">" {
+					lastID = msg.ID
+				}
+			}
+		}
+	}
+}
+
+// reclaimPending 接手其他 consumer 閒置超過 RedisStreamClaimIdle 仍未 ack 的訊息
+func (r *RedisSubscriber) reclaimPending(ctx context.Context, handler Handler) {
+	start := "0-0"
+	for {
+		messages, next, err := r.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   PriceUpdatesStream,
+			Group:    r.cfg.RedisStreamGroup,
+			Consumer: r.cfg.RedisStreamConsumer,
+			MinIdle:  r.cfg.RedisStreamClaimIdle,
+			Start:    start,
+			Count:    streamReadCount,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("❌ Failed to reclaim pending messages: %v", err)
+			}
+			return
+		}
+
+		if len(messages) > 0 {
+			log.Printf("♻️  Reclaimed %d pending messages", len(messages))
+		}
+		for _, msg := range messages {
+			r.handleStreamMessage(msg, handler)
+		}
+
+		if next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+// handleStreamMessage 處理一筆 Stream 訊息
+// 是否遲到由訂閱器的事件時間窗口判斷，重新處理的 pending 訊息同樣交給 handler；
+// 訊息在所屬窗口推送後才 ack，窗口推送前中斷的訊息留在 pending 清單，重啟後重新處理。無法解析的訊息直接 ack
+func (r *RedisSubscriber) handleStreamMessage(msg redis.XMessage, handler Handler) {
+	ack := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), ackTimeout)
+		defer cancel()
+
+		if err := r.client.XAck(ctx, PriceUpdatesStream, r.cfg.RedisStreamGroup, msg.ID).Err(); err != nil {
+			log.Printf("❌ Failed to ack message %s: %v", msg.ID, err)
+		}
+	}
+
+	data, ok := msg.Values["data"].(string)
+	if !ok {
+		log.Printf("❌ Invalid stream message %s: missing data field", msg.ID)
+		ack()
+		return
+	}
+
+	price, err := DecodePrice([]byte(data))
+	if err != nil {
+		log.Printf("❌ Failed to decode price: %v", err)
+		ack()
+		return
+	}
+
+	handler(price, ack)
+}
+
+// Ping 檢查 Redis 連接是否正常
+func (r *RedisSubscriber) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Close 關閉 Redis 連接
+func (r *RedisSubscriber) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,163 @@
+package candle
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// retainCandles 每個商品、每個週期保留的 K 線數
+// 除了未收盤的 K 線，也保留剛收盤、可能尚未寫入 InfluxDB 的 K 線
+const retainCandles = 3
+
+// Store 依推送的價格維護每個商品、每個固定長度週期的滾動 K 線
+// 價格依聚合窗口的開始時間分桶，與 WebSocket 客戶端收到的價格一致
+type Store struct {
+	intervals map[string]time.Duration
+	mu        sync.RWMutex
+	series    map[seriesKey][]*liveCandle // 依時間升序
+}
+
+type seriesKey struct {
+	symbol   string
+	interval string
+}
+
+// liveCandle 由推送的價格組成的 K 線
+type liveCandle struct {
+	kline    model.Kline
+	complete bool // 從 K 線開頭的窗口就開始收到價格，不需要歷史資料補齊開盤價
+}
+
+// NewStore 創建 K 線存儲
+func NewStore() *Store {
+	return &Store{
+		intervals: model.FixedKlineIntervals(),
+		series:    make(map[seriesKey][]*liveCandle),
+	}
+}
+
+// Update 以推送的價格更新商品各週期的 K 線
+// windowStart 為價格所屬聚合窗口的開始時間（Unix 毫秒），比保留的 K 線更早的價格略過
+func (s *Store) Update(price *model.Price, windowStart int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for interval, step := range s.intervals {
+		ms := step.Milliseconds()
+		start := windowStart - windowStart%ms
+
+		key := seriesKey{symbol: price.Symbol, interval: interval}
+		candles := s.series[key]
+
+		var candle *liveCandle
+		for _, c := range candles {
+			if c.kline.Timestamp == start {
+				candle = c
+				break
+			}
+		}
+
+		if candle == nil {
+			if len(candles) >= retainCandles && start < candles[0].kline.Timestamp {
+				continue
+			}
+
+			candle = &liveCandle{
+				kline: model.Kline{
+					Timestamp: start,
+					Open:      price.Price,
+					High:      price.Price,
+					Low:       price.Price,
+				},
+				complete: windowStart == start,
+			}
+			candles = append(candles, candle)
+			sort.Slice(candles, func(i, j int) bool {
+				return candles[i].kline.Timestamp < candles[j].kline.Timestamp
+			})
+			if len(candles) > retainCandles {
+				candles = candles[len(candles)-retainCandles:]
+			}
+			s.series[key] = candles
+		}
+
+		candle.kline.High = max(candle.kline.High, price.Price)
+		candle.kline.Low = min(candle.kline.Low, price.Price)
+		candle.kline.Close = price.Price
+	}
+}
+
+// Remove 清除商品的所有 K 線（不再收到該商品的價格時）
+func (s *Store) Remove(symbol string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.series {
+		if key.symbol == symbol {
+			delete(s.series, key)
+		}
+	}
+}
+
+// Merge 將記憶體中的 K 線併入歷史查詢結果，返回新的結果，不修改傳入的 page
+//
+// 與歷史 K 線時間相同時以推送的價格為準；從 K 線中途才開始收到價格時，開盤價沿用歷史資料，
+// 最高、最低價合併兩者。比歷史資料更新的 K 線附加在最後，頁面超過 query.Limit 時捨棄最舊的 K 線。
+// 只有查詢範圍的最新一端（沒有游標，且為 latest 方向或已是最後一頁）才會合併。
+func (s *Store) Merge(query model.KlineQuery, page *model.KlinePage) *model.KlinePage {
+	step, ok := query.Step()
+	if !ok || query.Cursor != "" || (query.Direction == "oldest" && page.NextCursor != "") {
+		return page
+	}
+
+	s.mu.RLock()
+	candles := s.series[seriesKey{symbol: query.Symbol, interval: query.Interval}]
+	live := make([]liveCandle, 0, len(candles))
+	for _, c := range candles {
+		// K 線與查詢範圍重疊
+		if c.kline.Timestamp+step.Milliseconds() > query.StartTime && c.kline.Timestamp < query.EndTime {
+			live = append(live, *c)
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(live) == 0 {
+		return page
+	}
+
+	merged := *page
+	merged.Klines = append([]*model.Kline(nil), page.Klines...)
+
+	for _, c := range live {
+		kline := c.kline
+
+		i := sort.Search(len(merged.Klines), func(i int) bool {
+			return merged.Klines[i].Timestamp >= kline.Timestamp
+		})
+
+		switch {
+		case i < len(merged.Klines) && merged.Klines[i].Timestamp == kline.Timestamp:
+			history := merged.Klines[i]
+			if !c.complete && !history.Empty {
+				kline.Open = history.Open
+				kline.High = max(kline.High, history.High)
+				kline.Low = min(kline.Low, history.Low)
+			}
+			kline.Volume = history.Volume
+			merged.Klines[i] = &kline
+		case i == len(merged.Klines):
+			merged.Klines = append(merged.Klines, &kline)
+			merged.Total++
+		}
+	}
+
+	// Total 仍為範圍內的 K 線總數，只有這一頁維持在 query.Limit 根
+	if limit := int(query.Limit); limit > 0 && len(merged.Klines) > limit {
+		merged.Klines = merged.Klines[len(merged.Klines)-limit:]
+	}
+
+	return &merged
+}
@@ -0,0 +1,179 @@
+package candle
+
+import (
+	"testing"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// update 以窗口開始時間 windowStart 的價格更新 GOLD 的 K 線
+func update(s *Store, windowStart int64, price float64) {
+	s.Update(&model.Price{Symbol: "GOLD", Price: price, Timestamp: windowStart}, windowStart)
+}
+
+// minuteQuery 查詢 GOLD 在 [0, 3m) 的 1m K 線
+func minuteQuery() model.KlineQuery {
+	return model.KlineQuery{Symbol: "GOLD", Interval: "1m", StartTime: 0, EndTime: 180000, Direction: "latest"}
+}
+
+func TestUpdate(t *testing.T) {
+	s := NewStore()
+	update(s, 60000, 10)
+	update(s, 61000, 12)
+	update(s, 62000, 9)
+
+	page := s.Merge(minuteQuery(), &model.KlinePage{})
+	if len(page.Klines) != 1 {
+		t.Fatalf("Merge() returned %d klines, want 1", len(page.Klines))
+	}
+
+	want := model.Kline{Timestamp: 60000, Open: 10, High: 12, Low: 9, Close: 9}
+	if got := *page.Klines[0]; got != want {
+		t.Errorf("kline = %+v, want %+v", got, want)
+	}
+
+	// 只保留最近 retainCandles 根，更早的價格略過
+	for minute := int64(2); minute <= 4; minute++ {
+		update(s, minute*60000, float64(minute))
+	}
+	update(s, 60000, 100)
+
+	query := minuteQuery()
+	query.EndTime = 300000
+	page = s.Merge(query, &model.KlinePage{})
+	if len(page.Klines) != retainCandles || page.Klines[0].Timestamp != 120000 {
+		t.Errorf("Merge() after rollover = %d klines from %d, want %d from 120000",
+			len(page.Klines), page.Klines[0].Timestamp, retainCandles)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	history := func() []*model.Kline {
+		return []*model.Kline{
+			{Timestamp: 0, Open: 1, High: 2, Low: 1, Close: 2, Volume: 3},
+			{Timestamp: 60000, Open: 5, High: 15, Low: 4, Close: 6, Volume: 7},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		query     func(q *model.KlineQuery)
+		page      model.KlinePage
+		updates   [][2]float64 // {窗口開始時間, 價格}
+		want      []model.Kline
+		wantTotal int
+		wantSame  bool // 返回原本的 page
+	}{
+		{
+			name:      "complete live kline replaces history",
+			page:      model.KlinePage{Klines: history(), Total: 2},
+			updates:   [][2]float64{{60000, 10}, {61000, 12}},
+			want:      []model.Kline{{Timestamp: 0, Open: 1, High: 2, Low: 1, Close: 2, Volume: 3}, {Timestamp: 60000, Open: 10, High: 12, Low: 10, Close: 12, Volume: 7}},
+			wantTotal: 2,
+		},
+		{
+			name:      "partial live kline keeps the historical open",
+			page:      model.KlinePage{Klines: history(), Total: 2},
+			updates:   [][2]float64{{90000, 10}},
+			want:      []model.Kline{{Timestamp: 0, Open: 1, High: 2, Low: 1, Close: 2, Volume: 3}, {Timestamp: 60000, Open: 5, High: 15, Low: 4, Close: 10, Volume: 7}},
+			wantTotal: 2,
+		},
+		{
+			name:      "partial live kline over an empty filled kline",
+			page:      model.KlinePage{Klines: []*model.Kline{{Timestamp: 60000, Filled: true, Empty: true}}, Total: 1},
+			updates:   [][2]float64{{90000, 10}},
+			want:      []model.Kline{{Timestamp: 60000, Open: 10, High: 10, Low: 10, Close: 10}},
+			wantTotal: 1,
+		},
+		{
+			name:      "newer live kline is appended",
+			page:      model.KlinePage{Klines: history(), Total: 2},
+			updates:   [][2]float64{{120000, 20}},
+			want:      []model.Kline{{Timestamp: 0, Open: 1, High: 2, Low: 1, Close: 2, Volume: 3}, {Timestamp: 60000, Open: 5, High: 15, Low: 4, Close: 6, Volume: 7}, {Timestamp: 120000, Open: 20, High: 20, Low: 20, Close: 20}},
+			wantTotal: 3,
+		},
+		{
+			name:      "newer live kline on a full page drops the oldest",
+			query:     func(q *model.KlineQuery) { q.Limit = 2 },
+			page:      model.KlinePage{Klines: history(), Total: 2},
+			updates:   [][2]float64{{120000, 20}},
+			want:      []model.Kline{{Timestamp: 60000, Open: 5, High: 15, Low: 4, Close: 6, Volume: 7}, {Timestamp: 120000, Open: 20, High: 20, Low: 20, Close: 20}},
+			wantTotal: 3,
+		},
+		{
+			name:     "live kline outside the range",
+			query:    func(q *model.KlineQuery) { q.EndTime = 120000 },
+			page:     model.KlinePage{Klines: history(), Total: 2},
+			updates:  [][2]float64{{120000, 20}},
+			wantSame: true,
+		},
+		{
+			name:     "page with a cursor",
+			query:    func(q *model.KlineQuery) { q.Cursor = "cursor" },
+			page:     model.KlinePage{Klines: history(), Total: 2},
+			updates:  [][2]float64{{120000, 20}},
+			wantSame: true,
+		},
+		{
+			name:     "oldest page before the last",
+			query:    func(q *model.KlineQuery) { q.Direction = "oldest" },
+			page:     model.KlinePage{Klines: history(), Total: 5, NextCursor: "next"},
+			updates:  [][2]float64{{120000, 20}},
+			wantSame: true,
+		},
+		{
+			name:     "calendar interval",
+			query:    func(q *model.KlineQuery) { q.Interval = "1w" },
+			page:     model.KlinePage{Klines: history(), Total: 2},
+			updates:  [][2]float64{{120000, 20}},
+			wantSame: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStore()
+			for _, u := range tt.updates {
+				update(s, int64(u[0]), u[1])
+			}
+
+			query := minuteQuery()
+			if tt.query != nil {
+				tt.query(&query)
+			}
+
+			page := tt.page
+			before := make([]model.Kline, len(page.Klines))
+			for i, k := range page.Klines {
+				before[i] = *k
+			}
+
+			got := s.Merge(query, &page)
+			if tt.wantSame {
+				if got != &page {
+					t.Fatalf("Merge() returned a new page, want the original")
+				}
+				return
+			}
+
+			if got.Total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", got.Total, tt.wantTotal)
+			}
+			if len(got.Klines) != len(tt.want) {
+				t.Fatalf("Merge() returned %d klines, want %d", len(got.Klines), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if *got.Klines[i] != want {
+					t.Errorf("kline %d = %+v, want %+v", i, *got.Klines[i], want)
+				}
+			}
+
+			// 傳入的 page 不被修改
+			for i, k := range page.Klines {
+				if *k != before[i] {
+					t.Errorf("input kline %d modified: %+v, was %+v", i, *k, before[i])
+				}
+			}
+		})
+	}
+}
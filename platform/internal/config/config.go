@@ -4,27 +4,94 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 // Config 平台服務配置
 type Config struct {
 	// gRPC 客戶端配置
-	PriceServiceAddr string
-	GRPCTimeout      time.Duration
+	PriceServiceAddr        string        // 單一位址、dns:/// 目標，或以逗號分隔的多個位址（輪詢）
+	GRPCTimeout             time.Duration // 每個請求的逾時（含重試）
+	GRPCRetryMaxAttempts    int           // 查詢 RPC 的最多嘗試次數（含第一次），1 表示不重試
+	GRPCRetryInitialBackoff time.Duration // 第一次重試前的等待時間，之後每次加倍
+	GRPCRetryMaxBackoff     time.Duration // 重試等待時間上限
+	GRPCBreakerFailures     int           // 斷路器開啟所需的連續失敗次數，0 表示停用
+	GRPCBreakerOpenTimeout  time.Duration // 斷路器開啟後經過此時間放行一個試探請求
+
+	// 訊息匯流排配置
+	MessageBus string // "redis" 或 "nats"，需與 Price Service 一致
 
 	// Redis 配置
 	RedisAddr     string
 	RedisPassword string
 	RedisDB       int
 
+	// Redis 傳輸配置
+	RedisTransport       string        // "pubsub" 或 "stream"，需與 Price Service 一致
+	RedisSubscribeMode   string        // pubsub 傳輸的訂閱方式："pattern"、"on_demand" 或 "aggregate"
+	RedisStreamGroup     string        // stream 傳輸的 consumer group 名稱，每個實例需使用自己的 group 才能收到所有價格
+	RedisStreamConsumer  string        // consumer 名稱，每個實例需唯一，重啟後沿用才能續讀自己未 ack 的訊息
+	RedisStreamClaimIdle time.Duration // 同一 group 中其他 consumer 未 ack 超過此時間的訊息會被接手處理
+
+	// NATS 配置（MESSAGE_BUS=nats）
+	NATSURL       string // NATS 伺服器位址，多個以逗號分隔
+	NATSSubject   string // 價格更新 subject
+	NATSJetStream bool   // 是否使用 JetStream 持久化訂閱
+	NATSStream    string // JetStream stream 名稱
+	NATSDurable   string // JetStream durable consumer 名稱，每個實例需使用自己的 durable 才能收到所有價格
+
+	// 訊息匯流排中斷處理
+	BusReconnectMinBackoff time.Duration // 重連的初始等待時間，每次失敗加倍
+	BusReconnectMaxBackoff time.Duration // 重連等待時間上限
+	GRPCFailover           bool          // 訊息匯流排中斷期間改從 Price Service gRPC 串流接收價格
+
+	// 價格來源狀態配置
+	StaleThreshold time.Duration // 商品超過此時間沒有收到價格或心跳即標記為 stale
+
+	// 價格檢查配置
+	ValidationEnabled       bool          // 推送前檢查價格，偏離參考價格過多的價格會被丟棄
+	ValidationMaxSigma      float64       // 相對上一筆接受價格的報酬超過參考期間報酬標準差的倍數即拒絕，0 表示不檢查
+	ValidationMaxChange     float64       // 偏離參考價格（最近接受價格的平均）超過此百分比即拒絕，0 表示不檢查
+	ValidationReferenceSize int           // 參考價格使用的最近接受價格筆數
+	HaltWindow              time.Duration // 判斷是否暫停商品的時間範圍
+	HaltMaxMove             float64       // HaltWindow 內接受價格的最高和最低相差超過此百分比即暫停商品，0 表示不檢查
+	HaltMaxRejections       int           // HaltWindow 內被拒絕的價格達到此筆數即暫停商品，0 表示不檢查
+	HaltResumeAfter         time.Duration // 自動暫停的商品經過此時間後自動恢復，0 表示需手動恢復
+
+	// 價格聚合配置
+	AggregationWindow   time.Duration            // 預設聚合窗口長度，每個窗口推送一筆價格
+	SymbolWindows       map[string]time.Duration // 個別商品的聚合窗口長度（symbol -> 窗口），覆蓋 AggregationWindow
+	AggregationLateness time.Duration            // 事件時間窗口的允許遲到時間，watermark 超過窗口結束時間才關閉窗口
+
 	// 價格策略配置
-	PriceStrategy string // "best" 或 "worst"，用於選擇每秒內的最佳或最差價格
+	PriceStrategy      string            // 預設的每秒選價策略名稱，例如 best、worst、median、twap
+	SymbolStrategies   map[string]string // 個別商品的選價策略（symbol -> 策略名稱），覆蓋 PriceStrategy
+	SpreadAdjustFactor float64           // spread 策略的價差係數，-1（最低價）~ 1（最高價）
+	UserStrategies     map[string]string // 個別用戶的選價策略（user ID -> 策略名稱）
+	RoleStrategies     map[string]string // 用戶角色的選價策略（role -> 策略名稱），用戶未個別設定時使用
+
+	// K 線緩存配置
+	KlineCacheEnabled     bool          // 是否緩存 K 線查詢結果
+	KlineCacheSize        int           // 最多緩存的查詢結果數，超過時淘汰最久未使用的
+	KlineCacheOpenTTL     time.Duration // 包含未收盤 K 線的查詢結果保存時間
+	KlineCacheSettleDelay time.Duration // K 線收盤後經過此時間才視為不再變動（等待遲到價格寫入）
+
+	// 價格審計配置
+	AuditEnabled   bool          // 是否記錄每秒候選價格和選中價格
+	AuditRetention time.Duration // 審計記錄保存期限
 
 	// WebSocket 配置（未來使用）
 	HTTPPort string
 	WSPath   string
 
+	// 管理 API 配置
+	AdminHTTPAddr string // 管理用 HTTP 服務器的監聽位址（暫停、恢復商品），空字串表示停用
+
+	// 認證配置
+	AuthTokenSecret string // 驗證登入服務簽發的 HS256 token 的 secret，空字串時 WebSocket 連線一律為匿名
+
 	// 日誌配置
 	LogLevel string
 }
@@ -33,25 +100,93 @@ type Config struct {
 func Load() (*Config, error) {
 	cfg := &Config{
 		// gRPC 預設值
-		PriceServiceAddr: getEnv("PRICE_SERVICE_ADDR", "localhost:50051"),
-		GRPCTimeout:      getDurationEnv("GRPC_TIMEOUT", 10*time.Second),
+		PriceServiceAddr:        getEnv("PRICE_SERVICE_ADDR", "localhost:50051"),
+		GRPCTimeout:             getDurationEnv("GRPC_TIMEOUT", 10*time.Second),
+		GRPCRetryMaxAttempts:    getIntEnv("GRPC_RETRY_MAX_ATTEMPTS", 3),
+		GRPCRetryInitialBackoff: getDurationEnv("GRPC_RETRY_INITIAL_BACKOFF", 100*time.Millisecond),
+		GRPCRetryMaxBackoff:     getDurationEnv("GRPC_RETRY_MAX_BACKOFF", time.Second),
+		GRPCBreakerFailures:     getIntEnv("GRPC_BREAKER_FAILURES", 5),
+		GRPCBreakerOpenTimeout:  getDurationEnv("GRPC_BREAKER_OPEN_TIMEOUT", 10*time.Second),
+
+		// 訊息匯流排預設值（Redis）
+		MessageBus: getEnv("MESSAGE_BUS", "redis"),
 
 		// Redis 預設值
 		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
 		RedisDB:       getIntEnv("REDIS_DB", 0),
 
+		// Redis 傳輸預設值（Pub/Sub）
+		RedisTransport:       getEnv("REDIS_TRANSPORT", "pubsub"),
+		RedisSubscribeMode:   getEnv("REDIS_SUBSCRIBE_MODE", "pattern"),
+		RedisStreamConsumer:  getEnv("REDIS_STREAM_CONSUMER", defaultConsumerName()),
+		RedisStreamClaimIdle: getDurationEnv("REDIS_STREAM_CLAIM_IDLE", 30*time.Second),
+
+		// NATS 預設值
+		NATSURL:       getEnv("NATS_URL", "nats://localhost:4222"),
+		NATSSubject:   getEnv("NATS_SUBJECT", "price.updates"),
+		NATSJetStream: getBoolEnv("NATS_JETSTREAM", false),
+		NATSStream:    getEnv("NATS_STREAM", "PRICES"),
+		NATSDurable:   getEnv("NATS_DURABLE", defaultDurableName()),
+
+		// 訊息匯流排中斷處理（預設 500ms 起加倍重連，最多 30 秒，期間改用 gRPC 串流）
+		BusReconnectMinBackoff: getDurationEnv("BUS_RECONNECT_MIN_BACKOFF", 500*time.Millisecond),
+		BusReconnectMaxBackoff: getDurationEnv("BUS_RECONNECT_MAX_BACKOFF", 30*time.Second),
+		GRPCFailover:           getBoolEnv("GRPC_FAILOVER_ENABLED", true),
+
+		// 價格來源狀態（Price Service 預設每秒發布心跳，5 秒沒有收到視為中斷）
+		StaleThreshold: getDurationEnv("PRICE_STALE_THRESHOLD", 5*time.Second),
+
+		// 價格檢查（預設參考最近 30 筆價格，1 分鐘內波動超過 60% 或拒絕 5 筆即暫停 5 分鐘）
+		ValidationEnabled:       getBoolEnv("PRICE_VALIDATION_ENABLED", true),
+		ValidationMaxSigma:      getFloatEnv("PRICE_VALIDATION_MAX_SIGMA", 6),
+		ValidationMaxChange:     getFloatEnv("PRICE_VALIDATION_MAX_CHANGE_PERCENT", 30),
+		ValidationReferenceSize: getIntEnv("PRICE_VALIDATION_REFERENCE_SIZE", 30),
+		HaltWindow:              getDurationEnv("HALT_WINDOW", time.Minute),
+		HaltMaxMove:             getFloatEnv("HALT_MAX_MOVE_PERCENT", 60),
+		HaltMaxRejections:       getIntEnv("HALT_MAX_REJECTIONS", 5),
+		HaltResumeAfter:         getDurationEnv("HALT_RESUME_AFTER", 5*time.Minute),
+
+		// 價格聚合（預設每秒一個窗口，允許 500ms 遲到）
+		AggregationWindow:   getDurationEnv("AGGREGATION_WINDOW", time.Second),
+		SymbolWindows:       getDurationMapEnv("AGGREGATION_WINDOW_SYMBOLS"),
+		AggregationLateness: getDurationEnv("AGGREGATION_ALLOWED_LATENESS", 500*time.Millisecond),
+
 		// 價格策略（預設最佳價格）
-		PriceStrategy: getEnv("PRICE_STRATEGY", "best"),
+		PriceStrategy:      getEnv("PRICE_STRATEGY", "best"),
+		SymbolStrategies:   getMapEnv("PRICE_STRATEGY_SYMBOLS", true),
+		SpreadAdjustFactor: getFloatEnv("SPREAD_ADJUST_FACTOR", 0),
+		UserStrategies:     getMapEnv("PRICE_STRATEGY_USERS", false),
+		RoleStrategies:     getMapEnv("PRICE_STRATEGY_ROLES", false),
+
+		// K 線緩存（預設 1000 筆，未收盤 K 線保存 2 秒）
+		KlineCacheEnabled:     getBoolEnv("KLINE_CACHE_ENABLED", true),
+		KlineCacheSize:        getIntEnv("KLINE_CACHE_SIZE", 1000),
+		KlineCacheOpenTTL:     getDurationEnv("KLINE_CACHE_OPEN_TTL", 2*time.Second),
+		KlineCacheSettleDelay: getDurationEnv("KLINE_CACHE_SETTLE_DELAY", 5*time.Second),
+
+		// 價格審計（預設保存 7 天）
+		AuditEnabled:   getBoolEnv("AUDIT_ENABLED", true),
+		AuditRetention: getDurationEnv("AUDIT_RETENTION", 7*24*time.Hour),
 
 		// HTTP/WebSocket 預設值
 		HTTPPort: getEnv("HTTP_PORT", "8080"),
 		WSPath:   getEnv("WS_PATH", "/ws/prices"),
 
+		// 管理 API 預設只接受本機連線
+		AdminHTTPAddr: getEnv("ADMIN_HTTP_ADDR", "127.0.0.1:8081"),
+
+		// 認證（預設停用）
+		AuthTokenSecret: getEnv("AUTH_TOKEN_SECRET", ""),
+
 		// 日誌配置
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 	}
 
+	// 每個實例預設使用自己的 consumer group，各自收到所有價格；
+	// 同一實例的多個 consumer 需明確設定相同的 REDIS_STREAM_GROUP（互為備援）
+	cfg.RedisStreamGroup = getEnv("REDIS_STREAM_GROUP", "platform-"+cfg.RedisStreamConsumer)
+
 	// 驗證配置
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -66,17 +201,176 @@ func (c *Config) validate() error {
 		return fmt.Errorf("PRICE_SERVICE_ADDR is required")
 	}
 
+	// gRPC 重試最多 5 次嘗試
+	if c.GRPCRetryMaxAttempts < 1 || c.GRPCRetryMaxAttempts > 5 {
+		return fmt.Errorf("GRPC_RETRY_MAX_ATTEMPTS must be between 1 and 5, got: %d", c.GRPCRetryMaxAttempts)
+	}
+
+	if c.GRPCRetryInitialBackoff <= 0 || c.GRPCRetryMaxBackoff < c.GRPCRetryInitialBackoff {
+		return fmt.Errorf("GRPC_RETRY_INITIAL_BACKOFF must be positive and not greater than GRPC_RETRY_MAX_BACKOFF, got: %s, %s",
+			c.GRPCRetryInitialBackoff, c.GRPCRetryMaxBackoff)
+	}
+
+	if c.GRPCBreakerFailures < 0 {
+		return fmt.Errorf("GRPC_BREAKER_FAILURES must not be negative, got: %d", c.GRPCBreakerFailures)
+	}
+
+	if c.GRPCBreakerFailures > 0 && c.GRPCBreakerOpenTimeout <= 0 {
+		return fmt.Errorf("GRPC_BREAKER_OPEN_TIMEOUT must be positive, got: %s", c.GRPCBreakerOpenTimeout)
+	}
+
+	// 行程內匯流排只用於測試：Price Service 是另一個行程，訂閱不會收到任何價格
+	if c.MessageBus != "redis" && c.MessageBus != "nats" {
+		return fmt.Errorf("MESSAGE_BUS must be 'redis' or 'nats', got: %s", c.MessageBus)
+	}
+
 	if c.RedisAddr == "" {
 		return fmt.Errorf("REDIS_ADDR is required")
 	}
 
-	if c.PriceStrategy != "best" && c.PriceStrategy != "worst" {
-		return fmt.Errorf("PRICE_STRATEGY must be 'best' or 'worst', got: %s", c.PriceStrategy)
+	if c.MessageBus == "nats" && (c.NATSURL == "" || c.NATSSubject == "") {
+		return fmt.Errorf("NATS_URL and NATS_SUBJECT are required when MESSAGE_BUS is 'nats'")
+	}
+
+	if c.MessageBus == "nats" && c.NATSJetStream && (c.NATSStream == "" || c.NATSDurable == "") {
+		return fmt.Errorf("NATS_STREAM and NATS_DURABLE are required when NATS_JETSTREAM is enabled")
+	}
+
+	if c.RedisTransport != "pubsub" && c.RedisTransport != "stream" {
+		return fmt.Errorf("REDIS_TRANSPORT must be 'pubsub' or 'stream', got: %s", c.RedisTransport)
+	}
+
+	if c.RedisSubscribeMode != "pattern" && c.RedisSubscribeMode != "on_demand" && c.RedisSubscribeMode != "aggregate" {
+		return fmt.Errorf("REDIS_SUBSCRIBE_MODE must be 'pattern', 'on_demand' or 'aggregate', got: %s", c.RedisSubscribeMode)
+	}
+
+	// 訊息在所屬窗口推送後才 ack，等待時間不能被視為閒置而被接手
+	if c.RedisTransport == "stream" && c.RedisStreamClaimIdle <= c.LongestWindow()+c.AggregationLateness {
+		return fmt.Errorf("REDIS_STREAM_CLAIM_IDLE must be greater than the longest aggregation window plus AGGREGATION_ALLOWED_LATENESS (%s), got: %s",
+			c.LongestWindow()+c.AggregationLateness, c.RedisStreamClaimIdle)
+	}
+
+	if c.BusReconnectMinBackoff <= 0 || c.BusReconnectMaxBackoff < c.BusReconnectMinBackoff {
+		return fmt.Errorf("BUS_RECONNECT_MIN_BACKOFF must be positive and not greater than BUS_RECONNECT_MAX_BACKOFF, got: %s, %s",
+			c.BusReconnectMinBackoff, c.BusReconnectMaxBackoff)
+	}
+
+	// 策略名稱在創建訂閱器時對照已註冊的策略檢查
+	if c.PriceStrategy == "" {
+		return fmt.Errorf("PRICE_STRATEGY is required")
+	}
+
+	if c.StaleThreshold <= 0 {
+		return fmt.Errorf("PRICE_STALE_THRESHOLD must be positive, got: %s", c.StaleThreshold)
+	}
+
+	if c.ValidationEnabled {
+		if err := c.validateGuard(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateWindow("AGGREGATION_WINDOW", c.AggregationWindow); err != nil {
+		return err
+	}
+
+	for symbol, window := range c.SymbolWindows {
+		if err := validateWindow("AGGREGATION_WINDOW_SYMBOLS for "+symbol, window); err != nil {
+			return err
+		}
+	}
+
+	if c.AggregationLateness < 0 {
+		return fmt.Errorf("AGGREGATION_ALLOWED_LATENESS must not be negative, got: %s", c.AggregationLateness)
+	}
+
+	if c.SpreadAdjustFactor < -1 || c.SpreadAdjustFactor > 1 {
+		return fmt.Errorf("SPREAD_ADJUST_FACTOR must be between -1 and 1, got: %v", c.SpreadAdjustFactor)
+	}
+
+	if c.KlineCacheEnabled && (c.KlineCacheSize <= 0 || c.KlineCacheOpenTTL <= 0 || c.KlineCacheSettleDelay < 0) {
+		return fmt.Errorf("KLINE_CACHE_SIZE and KLINE_CACHE_OPEN_TTL must be positive and KLINE_CACHE_SETTLE_DELAY must not be negative, got: %d, %s, %s",
+			c.KlineCacheSize, c.KlineCacheOpenTTL, c.KlineCacheSettleDelay)
+	}
+
+	if c.AuditEnabled && c.AuditRetention <= 0 {
+		return fmt.Errorf("AUDIT_RETENTION must be positive, got: %s", c.AuditRetention)
 	}
 
 	return nil
 }
 
+// validateGuard 驗證價格檢查和暫停配置
+func (c *Config) validateGuard() error {
+	if c.ValidationMaxSigma < 0 {
+		return fmt.Errorf("PRICE_VALIDATION_MAX_SIGMA must not be negative, got: %v", c.ValidationMaxSigma)
+	}
+
+	if c.ValidationMaxChange < 0 {
+		return fmt.Errorf("PRICE_VALIDATION_MAX_CHANGE_PERCENT must not be negative, got: %v", c.ValidationMaxChange)
+	}
+
+	// 報酬標準差至少需要 20 筆參考價格
+	if c.ValidationReferenceSize < 1 || (c.ValidationMaxSigma > 0 && c.ValidationReferenceSize < 20) {
+		return fmt.Errorf("PRICE_VALIDATION_REFERENCE_SIZE must be positive and at least 20 when PRICE_VALIDATION_MAX_SIGMA is set, got: %d",
+			c.ValidationReferenceSize)
+	}
+
+	if c.HaltWindow <= 0 {
+		return fmt.Errorf("HALT_WINDOW must be positive, got: %s", c.HaltWindow)
+	}
+
+	if c.HaltMaxMove < 0 {
+		return fmt.Errorf("HALT_MAX_MOVE_PERCENT must not be negative, got: %v", c.HaltMaxMove)
+	}
+
+	if c.HaltMaxRejections < 0 {
+		return fmt.Errorf("HALT_MAX_REJECTIONS must not be negative, got: %d", c.HaltMaxRejections)
+	}
+
+	if c.HaltResumeAfter < 0 {
+		return fmt.Errorf("HALT_RESUME_AFTER must not be negative, got: %s", c.HaltResumeAfter)
+	}
+
+	return nil
+}
+
+// LongestWindow 所有商品中最長的聚合窗口
+func (c *Config) LongestWindow() time.Duration {
+	longest := c.AggregationWindow
+	for _, window := range c.SymbolWindows {
+		longest = max(longest, window)
+	}
+	return longest
+}
+
+// validateWindow 聚合窗口至少 100ms、以毫秒為單位，且能整除 1 分鐘，讓窗口和分鐘 K 線對齊
+func validateWindow(name string, window time.Duration) error {
+	if window < 100*time.Millisecond || window%time.Millisecond != 0 || time.Minute%window != 0 {
+		return fmt.Errorf("%s must be at least 100ms, in whole milliseconds and divide 1m evenly, got: %s", name, window)
+	}
+	return nil
+}
+
+// defaultConsumerName 預設 consumer 名稱，使用主機名稱（容器重啟後不變）
+func defaultConsumerName() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "platform"
+}
+
+// defaultDurableName 預設 JetStream durable 名稱，每個實例以主機名稱區分
+// durable 名稱不可包含 .、*、>、路徑分隔符號和空白，這些字元以 _ 取代
+func defaultDurableName() string {
+	return "platform-" + strings.Map(func(r rune) rune {
+		if r == '.' || r == '*' || r == '>' || r == '/' || r == '\\' || unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, defaultConsumerName())
+}
+
 // getEnv 獲取環境變數，若不存在則返回預設值
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -85,6 +379,44 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getFloatEnv 獲取浮點數類型環境變數
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getMapEnv 獲取 "KEY=value,KEY=value" 格式的環境變數，upperKeys 為 true 時 key 轉為大寫，格式錯誤的項目會被忽略
+func getMapEnv(key string, upperKeys bool) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || k == "" || v == "" {
+			continue
+		}
+		k = strings.TrimSpace(k)
+		if upperKeys {
+			k = strings.ToUpper(k)
+		}
+		result[k] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+// getDurationMapEnv 獲取 "KEY=duration,KEY=duration" 格式的環境變數，key 轉為大寫，無法解析的項目會被忽略
+func getDurationMapEnv(key string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for k, v := range getMapEnv(key, true) {
+		if duration, err := time.ParseDuration(v); err == nil {
+			result[k] = duration
+		}
+	}
+	return result
+}
+
 // getIntEnv 獲取整數類型環境變數
 func getIntEnv(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
@@ -95,6 +427,16 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getBoolEnv 獲取布林類型環境變數
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 // getDurationEnv 獲取時間類型環境變數
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
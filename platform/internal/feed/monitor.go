@@ -0,0 +1,195 @@
+package feed
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// Listener 商品價格來源狀態變化的回調（live <-> stale）
+type Listener func(status model.FeedStatus)
+
+// Monitor 追蹤每個商品最後收到價格或心跳的時間，超過門檻時間標記為 stale
+// 時間以本機收到訊息的時間計算，不受 Price Service 時鐘偏差影響
+type Monitor struct {
+	threshold time.Duration
+	onChange  Listener // 為 nil 時不通知
+	mu        sync.RWMutex
+	last      map[string]time.Time // symbol -> 最後收到價格或心跳的時間
+	since     map[string]time.Time // symbol -> 開始追蹤的時間，在收到第一則訊息前作為門檻時間的起點
+	stale     map[string]bool      // symbol -> 目前是否為 stale
+}
+
+// NewMonitor 創建價格來源監控
+func NewMonitor(threshold time.Duration, onChange Listener) *Monitor {
+	return &Monitor{
+		threshold: threshold,
+		onChange:  onChange,
+		last:      make(map[string]time.Time),
+		since:     make(map[string]time.Time),
+		stale:     make(map[string]bool),
+	}
+}
+
+// Watch 從 at 起追蹤商品，門檻時間從 at 或之後收到的訊息起算
+// on_demand 模式下商品開始有客戶端關注時調用：訂閱前沒有收到的心跳不會讓剛關注的商品被標記為 stale
+func (m *Monitor) Watch(symbol string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.since[symbol] = at
+	m.stale[symbol] = false
+}
+
+// Forget 停止追蹤商品，之後查詢時視為尚未收到過價格或心跳
+// on_demand 模式下商品已沒有客戶端關注時調用，取消訂閱後不再收到心跳，不應標記為 stale
+func (m *Monitor) Forget(symbol string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.last, symbol)
+	delete(m.since, symbol)
+	delete(m.stale, symbol)
+}
+
+// Touch 記錄商品收到價格或心跳，從 stale 恢復時通知
+func (m *Monitor) Touch(symbol string, at time.Time) {
+	m.mu.Lock()
+	m.last[symbol] = at
+	recovered := m.stale[symbol]
+	if recovered {
+		m.stale[symbol] = false
+	}
+	status := m.statusLocked(symbol, at)
+	m.mu.Unlock()
+
+	if recovered {
+		m.notify(status)
+	}
+}
+
+// Run 定期檢查商品是否超過門檻時間沒有更新，直到 ctx 取消
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, status := range m.check(now) {
+				m.notify(status)
+			}
+		}
+	}
+}
+
+// check 標記超過門檻時間的商品為 stale，返回狀態有變化的商品
+func (m *Monitor) check(now time.Time) []model.FeedStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var changed []model.FeedStatus
+	for _, symbol := range m.symbolsLocked() {
+		if m.stale[symbol] || now.Sub(m.referenceLocked(symbol)) <= m.threshold {
+			continue
+		}
+		m.stale[symbol] = true
+		changed = append(changed, m.statusLocked(symbol, now))
+	}
+	return changed
+}
+
+// Status 商品目前的價格來源狀態，尚未追蹤也沒有收到過價格或心跳時 ok 為 false
+func (m *Monitor) Status(symbol string) (model.FeedStatus, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, received := m.last[symbol]
+	_, watched := m.since[symbol]
+	if !received && !watched {
+		return model.FeedStatus{}, false
+	}
+	return m.statusLocked(symbol, time.Now()), true
+}
+
+// Statuses 所有商品目前的價格來源狀態，依商品排序
+func (m *Monitor) Statuses() []model.FeedStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	symbols := m.symbolsLocked()
+	statuses := make([]model.FeedStatus, 0, len(symbols))
+	for _, symbol := range symbols {
+		statuses = append(statuses, m.statusLocked(symbol, now))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Symbol < statuses[j].Symbol
+	})
+	return statuses
+}
+
+// IsStale 商品是否超過門檻時間沒有收到價格或心跳
+func (m *Monitor) IsStale(symbol string) bool {
+	status, ok := m.Status(symbol)
+	return ok && status.Status == model.FeedStale
+}
+
+// statusLocked 組成商品的狀態，呼叫端需持有鎖
+// 門檻時間以經過時間判斷，不等待下一次 check，查詢結果不會比實際晚
+// 尚未收到訊息的商品 LastUpdate 為 0，Age 從開始追蹤起算
+func (m *Monitor) statusLocked(symbol string, now time.Time) model.FeedStatus {
+	age := now.Sub(m.referenceLocked(symbol))
+
+	status := model.FeedLive
+	if m.stale[symbol] || age > m.threshold {
+		status = model.FeedStale
+	}
+
+	var lastUpdate int64
+	if last, ok := m.last[symbol]; ok {
+		lastUpdate = last.UnixMilli()
+	}
+
+	return model.FeedStatus{
+		Symbol:     symbol,
+		Status:     status,
+		LastUpdate: lastUpdate,
+		Age:        age.Milliseconds(),
+	}
+}
+
+// referenceLocked 門檻時間的起點：最後收到訊息的時間，開始追蹤之後還沒收到訊息時為開始追蹤的時間，呼叫端需持有鎖
+func (m *Monitor) referenceLocked(symbol string) time.Time {
+	last := m.last[symbol]
+	if since, ok := m.since[symbol]; ok && since.After(last) {
+		return since
+	}
+	return last
+}
+
+// symbolsLocked 追蹤中或收到過訊息的商品，呼叫端需持有鎖
+func (m *Monitor) symbolsLocked() []string {
+	symbols := make([]string, 0, len(m.last)+len(m.since))
+	for symbol := range m.last {
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range m.since {
+		if _, ok := m.last[symbol]; !ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+func (m *Monitor) notify(status model.FeedStatus) {
+	if m.onChange != nil {
+		m.onChange(status)
+	}
+}
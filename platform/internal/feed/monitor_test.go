@@ -0,0 +1,62 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+func TestMonitorCheck(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var notified []model.FeedStatus
+	m := NewMonitor(10*time.Second, func(status model.FeedStatus) {
+		notified = append(notified, status)
+	})
+
+	m.Touch("GOLD", start)
+	if changed := m.check(start.Add(5 * time.Second)); len(changed) != 0 {
+		t.Fatalf("check() within threshold = %v, want no changes", changed)
+	}
+
+	changed := m.check(start.Add(11 * time.Second))
+	if len(changed) != 1 || changed[0].Symbol != "GOLD" || changed[0].Status != model.FeedStale {
+		t.Fatalf("check() after threshold = %v, want GOLD stale", changed)
+	}
+	if changed := m.check(start.Add(12 * time.Second)); len(changed) != 0 {
+		t.Errorf("check() while already stale = %v, want no changes", changed)
+	}
+
+	// 恢復時通知
+	m.Touch("GOLD", start.Add(13*time.Second))
+	if len(notified) != 1 || notified[0].Status != model.FeedLive {
+		t.Errorf("Touch() after stale notified %v, want one live status", notified)
+	}
+}
+
+func TestMonitorWatch(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMonitor(10*time.Second, nil)
+
+	// 取消關注前收到的最後一則訊息早已超過門檻時間
+	m.Touch("GOLD", start)
+	m.check(start.Add(time.Minute))
+	m.Forget("GOLD")
+	if _, ok := m.Status("GOLD"); ok {
+		t.Fatal("Status() after Forget reported a status")
+	}
+
+	// 重新關注時從關注時間起算，不會立即標記為 stale
+	watched := start.Add(2 * time.Minute)
+	m.Watch("GOLD", watched)
+	if changed := m.check(watched.Add(5 * time.Second)); len(changed) != 0 {
+		t.Fatalf("check() right after Watch = %v, want no changes", changed)
+	}
+
+	// 關注後一直沒有收到訊息，超過門檻時間仍標記為 stale
+	changed := m.check(watched.Add(11 * time.Second))
+	if len(changed) != 1 || changed[0].Status != model.FeedStale || changed[0].LastUpdate != 0 {
+		t.Errorf("check() without messages after Watch = %v, want GOLD stale with no last update", changed)
+	}
+}
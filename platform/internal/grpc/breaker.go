@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen 斷路器開啟中，請求未送出直接失敗
+var ErrCircuitOpen = status.Error(codes.Unavailable, "price service circuit breaker is open")
+
+// 斷路器狀態
+const (
+	BreakerClosed   = "closed"    // 正常送出請求
+	BreakerOpen     = "open"      // 連續失敗達到門檻，請求直接失敗
+	BreakerHalfOpen = "half_open" // 開啟時間結束，只放行一個試探請求
+)
+
+// breaker 斷路器：連續失敗 failures 次後開啟，經過 openTimeout 後放行一個試探請求，
+// 試探成功即關閉，失敗則重新開啟，被取消則維持半開。失敗次數以重試結束後的最終結果計算。
+type breaker struct {
+	failures    int // 開啟所需的連續失敗次數，0 表示停用
+	openTimeout time.Duration
+	mu          sync.Mutex
+	state       string
+	consecutive int
+	openedAt    time.Time
+	probing     bool // 半開狀態下已有試探請求進行中
+}
+
+func newBreaker(failures int, openTimeout time.Duration) *breaker {
+	return &breaker{
+		failures:    failures,
+		openTimeout: openTimeout,
+		state:       BreakerClosed,
+	}
+}
+
+// allow 是否可以送出請求
+func (b *breaker) allow() error {
+	if b.failures == 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		log.Printf("🔌 Price Service circuit breaker half-open, probing")
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// record 記錄請求結果
+// 呼叫端取消的請求不表示 Price Service 是否可用，不改變狀態，半開時讓下一個請求重新試探
+func (b *breaker) record(err error) {
+	if b.failures == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if isCanceled(err) {
+		return
+	}
+
+	if !isFailure(err) {
+		if b.state != BreakerClosed {
+			log.Printf("✅ Price Service circuit breaker closed")
+		}
+		b.state = BreakerClosed
+		b.consecutive = 0
+		return
+	}
+
+	b.consecutive++
+	if b.state == BreakerHalfOpen || b.consecutive >= b.failures {
+		if b.state != BreakerOpen {
+			log.Printf("⛔ Price Service circuit breaker open after %d failures: %v", b.consecutive, err)
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State 斷路器目前的狀態
+func (b *breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.openTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// isCanceled 請求是否由呼叫端取消
+func isCanceled(err error) bool {
+	return errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled
+}
+
+// isFailure 錯誤是否表示 Price Service 無法服務
+// 參數錯誤、查無資料等由服務正常回應的錯誤不計入；Unknown 不計入，避免未分類的錯誤讓呼叫端的錯誤請求開啟斷路器
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal:
+		return true
+	}
+	return false
+}
+
+// unaryInterceptor 以斷路器包裝 unary 請求（在 gRPC 內建重試之外，重試全部失敗才記一次失敗）
+func (b *breaker) unaryInterceptor(ctx context.Context, method string, req, reply any,
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	b.record(err)
+	return err
+}
+
+// streamInterceptor 以斷路器包裝串流
+// 串流建立不需要等待伺服器回應，所以以第一次接收的結果記錄：收到訊息或正常結束為成功
+func (b *breaker) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		b.record(err)
+		return nil, err
+	}
+	return &breakerStream{ClientStream: stream, breaker: b}, nil
+}
+
+// breakerStream 第一次接收時記錄串流結果
+type breakerStream struct {
+	grpc.ClientStream
+	breaker  *breaker
+	recorded sync.Once
+}
+
+func (s *breakerStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.recorded.Do(func() {
+		if err == io.EOF {
+			s.breaker.record(nil)
+		} else {
+			s.breaker.record(err)
+		}
+	})
+	return err
+}
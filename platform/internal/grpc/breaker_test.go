@@ -0,0 +1,212 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnavailable = status.Error(codes.Unavailable, "unavailable")
+	errNotFound    = status.Error(codes.NotFound, "not found")
+	errInvalid     = status.Error(codes.InvalidArgument, "invalid argument")
+	errUnknown     = status.Error(codes.Unknown, "unknown")
+	errCanceled    = status.Error(codes.Canceled, "canceled")
+)
+
+func TestBreaker(t *testing.T) {
+	// 每一步先調用 allow()，通過時記錄 result；wait 為 true 時先讓開啟時間結束
+	type step struct {
+		wait      bool
+		result    error
+		wantAllow error  // allow() 的結果
+		wantState string // 記錄後的狀態
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "opens after consecutive failures",
+			steps: []step{
+				{result: errUnavailable, wantState: BreakerClosed},
+				{result: errUnavailable, wantState: BreakerClosed},
+				{result: errUnavailable, wantState: BreakerOpen},
+				{wantAllow: ErrCircuitOpen, wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "success resets the failure count",
+			steps: []step{
+				{result: errUnavailable, wantState: BreakerClosed},
+				{result: errUnavailable, wantState: BreakerClosed},
+				{result: nil, wantState: BreakerClosed},
+				{result: errUnavailable, wantState: BreakerClosed},
+				{result: errUnavailable, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "service errors are not failures",
+			steps: []step{
+				{result: errNotFound, wantState: BreakerClosed},
+				{result: errNotFound, wantState: BreakerClosed},
+				{result: errNotFound, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "invalid arguments never trip",
+			steps: []step{
+				{result: errInvalid, wantState: BreakerClosed},
+				{result: errInvalid, wantState: BreakerClosed},
+				{result: errInvalid, wantState: BreakerClosed},
+				{result: errInvalid, wantState: BreakerClosed},
+				{result: errInvalid, wantState: BreakerClosed},
+				{result: errUnknown, wantState: BreakerClosed},
+				{result: errUnavailable, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "successful probe closes",
+			steps: []step{
+				{result: errUnavailable},
+				{result: errUnavailable},
+				{result: errUnavailable, wantState: BreakerOpen},
+				{wait: true, result: nil, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "failed probe reopens",
+			steps: []step{
+				{result: errUnavailable},
+				{result: errUnavailable},
+				{result: errUnavailable, wantState: BreakerOpen},
+				{wait: true, result: errUnavailable, wantState: BreakerOpen},
+				{wantAllow: ErrCircuitOpen, wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "canceled probe stays half-open",
+			steps: []step{
+				{result: errUnavailable},
+				{result: errUnavailable},
+				{result: errUnavailable, wantState: BreakerOpen},
+				{wait: true, result: context.Canceled, wantState: BreakerHalfOpen},
+				{result: errCanceled, wantState: BreakerHalfOpen},
+				{result: nil, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "canceled requests do not reset failures",
+			steps: []step{
+				{result: errUnavailable},
+				{result: errUnavailable},
+				{result: context.Canceled, wantState: BreakerClosed},
+				{result: errUnavailable, wantState: BreakerOpen},
+			},
+		},
+	}
+
+	const openTimeout = time.Minute
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(3, openTimeout)
+
+			for i, s := range tt.steps {
+				if s.wait {
+					b.openedAt = b.openedAt.Add(-openTimeout)
+				}
+
+				err := b.allow()
+				if !errors.Is(err, s.wantAllow) {
+					t.Fatalf("step %d: allow() = %v, want %v", i, err, s.wantAllow)
+				}
+				if err == nil {
+					b.record(s.result)
+				}
+
+				if s.wantState != "" && b.State() != s.wantState {
+					t.Fatalf("step %d: state = %s, want %s", i, b.State(), s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerHalfOpenAllowsOneProbe(t *testing.T) {
+	b := newBreaker(1, time.Minute)
+	b.record(errUnavailable)
+	b.openedAt = b.openedAt.Add(-time.Minute)
+
+	if err := b.allow(); err != nil {
+		t.Fatalf("first probe: %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second request during probe: %v, want ErrCircuitOpen", err)
+	}
+}
+
+// fakeStream 依序返回 errs 的 ClientStream
+type fakeStream struct {
+	grpc.ClientStream
+	errs []error
+}
+
+func (s *fakeStream) RecvMsg(m any) error {
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func TestBreakerStreamInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		createErr error   // 建立串流的錯誤
+		recv      []error // 依序接收的結果
+		wantState string
+	}{
+		{name: "first message closes", recv: []error{nil, errUnavailable}, wantState: BreakerClosed},
+		{name: "immediate end closes", recv: []error{io.EOF}, wantState: BreakerClosed},
+		{name: "first receive fails", recv: []error{errUnavailable}, wantState: BreakerOpen},
+		{name: "creation fails", createErr: errUnavailable, wantState: BreakerOpen},
+		{name: "canceled before first message", recv: []error{errCanceled}, wantState: BreakerHalfOpen},
+		{name: "not recorded until received", wantState: BreakerHalfOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 開啟後進入半開，串流為試探請求
+			b := newBreaker(1, time.Minute)
+			b.record(errUnavailable)
+			b.openedAt = b.openedAt.Add(-time.Minute)
+
+			streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+				method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				if tt.createErr != nil {
+					return nil, tt.createErr
+				}
+				return &fakeStream{errs: tt.recv}, nil
+			}
+
+			stream, err := b.streamInterceptor(context.Background(), &grpc.StreamDesc{}, nil, "/price.PriceService/SubscribePrices", streamer)
+			if !errors.Is(err, tt.createErr) {
+				t.Fatalf("streamInterceptor() error = %v, want %v", err, tt.createErr)
+			}
+
+			for range tt.recv {
+				stream.RecvMsg(nil)
+			}
+
+			if got := b.State(); got != tt.wantState {
+				t.Errorf("state = %s, want %s", got, tt.wantState)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mike/golden-buy/platform/internal/config"
@@ -10,37 +12,104 @@ import (
 	pb "github.com/mike/golden-buy/platform/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
 )
 
+// staticScheme PRICE_SERVICE_ADDR 為多個位址時使用的 resolver scheme
+const staticScheme = "price-static"
+
+// idempotentMethods 可以安全重試的 RPC（皆為查詢）
+// SubscribePrices 串流中斷後由呼叫端以退避重新訂閱，不在此重試
+var idempotentMethods = []string{
+	"GetCurrentPrice",
+	"GetCurrentPrices",
+	"GetKlines",
+	"GetStatistics",
+	"GetIndicators",
+	"GetTicks",
+}
+
 // PriceClient Price Service 的 gRPC 客戶端
 type PriceClient struct {
-	conn   *grpc.ClientConn
-	client pb.PriceServiceClient
-	cfg    *config.Config
+	conn    *grpc.ClientConn
+	client  pb.PriceServiceClient
+	cfg     *config.Config
+	breaker *breaker
 }
 
 // NewPriceClient 創建新的 Price Service 客戶端
+// 連線在第一次請求時才建立，Price Service 尚未啟動不影響平台啟動。
+// PRICE_SERVICE_ADDR 可為單一位址（依 DNS 解析出的所有位址輪詢）、dns:/// 開頭的目標，
+// 或以逗號分隔的多個位址（固定清單輪詢）。
 func NewPriceClient(cfg *config.Config) (*PriceClient, error) {
-	// 連接 Price Service
-	conn, err := grpc.Dial(
-		cfg.PriceServiceAddr,
+	b := newBreaker(cfg.GRPCBreakerFailures, cfg.GRPCBreakerOpenTimeout)
+
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-		grpc.WithTimeout(cfg.GRPCTimeout),
-	)
+		grpc.WithDefaultServiceConfig(serviceConfig(cfg)),
+		grpc.WithChainUnaryInterceptor(b.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(b.streamInterceptor),
+	}
+
+	target := cfg.PriceServiceAddr
+	if addrs := strings.Split(target, ","); len(addrs) > 1 {
+		r := manual.NewBuilderWithScheme(staticScheme)
+		state := resolver.State{}
+		for _, addr := range addrs {
+			state.Addresses = append(state.Addresses, resolver.Address{Addr: strings.TrimSpace(addr)})
+		}
+		r.InitialState(state)
+
+		target = staticScheme + ":///price-service"
+		opts = append(opts, grpc.WithResolvers(r))
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to price service at %s: %w", cfg.PriceServiceAddr, err)
+		return nil, fmt.Errorf("failed to create client for price service at %s: %w", cfg.PriceServiceAddr, err)
 	}
 
 	client := pb.NewPriceServiceClient(conn)
 
 	return &PriceClient{
-		conn:   conn,
-		client: client,
-		cfg:    cfg,
+		conn:    conn,
+		client:  client,
+		cfg:     cfg,
+		breaker: b,
 	}, nil
 }
 
+// serviceConfig 輪詢負載平衡，查詢 RPC 在 UNAVAILABLE 時以指數退避重試
+func serviceConfig(cfg *config.Config) string {
+	names := make([]map[string]string, len(idempotentMethods))
+	for i, method := range idempotentMethods {
+		names[i] = map[string]string{"service": "price.PriceService", "method": method}
+	}
+
+	methodConfig := map[string]any{"name": names}
+	if cfg.GRPCRetryMaxAttempts > 1 {
+		methodConfig["retryPolicy"] = map[string]any{
+			"maxAttempts":          cfg.GRPCRetryMaxAttempts,
+			"initialBackoff":       fmt.Sprintf("%.3fs", cfg.GRPCRetryInitialBackoff.Seconds()),
+			"maxBackoff":           fmt.Sprintf("%.3fs", cfg.GRPCRetryMaxBackoff.Seconds()),
+			"backoffMultiplier":    2,
+			"retryableStatusCodes": []string{"UNAVAILABLE"},
+		}
+	}
+
+	data, _ := json.Marshal(map[string]any{
+		"loadBalancingConfig": []map[string]any{{"round_robin": map[string]any{}}},
+		"methodConfig":        []any{methodConfig},
+	})
+	return string(data)
+}
+
+// BreakerState 斷路器目前的狀態：closed、open 或 half_open
+func (pc *PriceClient) BreakerState() string {
+	return pc.breaker.State()
+}
+
 // GetCurrentPrice 獲取單個商品當前價格
 func (pc *PriceClient) GetCurrentPrice(ctx context.Context, symbol string) (*model.Price, error) {
 	ctx, cancel := context.WithTimeout(ctx, pc.cfg.GRPCTimeout)
@@ -88,20 +157,25 @@ func (pc *PriceClient) GetCurrentPrices(ctx context.Context, symbols []string) (
 	return prices, nil
 }
 
-// GetKlines 獲取 K 線資料
-func (pc *PriceClient) GetKlines(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int32) ([]*model.Kline, error) {
+// GetKlines 獲取 K 線資料（分頁）
+func (pc *PriceClient) GetKlines(ctx context.Context, query model.KlineQuery) (*model.KlinePage, error) {
 	ctx, cancel := context.WithTimeout(ctx, pc.cfg.GRPCTimeout)
 	defer cancel()
 
 	resp, err := pc.client.GetKlines(ctx, &pb.GetKlinesRequest{
-		Symbol:    symbol,
-		Interval:  interval,
-		StartTime: startTime,
-		EndTime:   endTime,
-		Limit:     limit,
+		Symbol:       query.Symbol,
+		Interval:     query.Interval,
+		StartTime:    query.StartTime,
+		EndTime:      query.EndTime,
+		Limit:        query.Limit,
+		Direction:    query.Direction,
+		Cursor:       query.Cursor,
+		Fill:         query.Fill,
+		Timezone:     query.Timezone,
+		SessionStart: query.SessionStart,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get klines for %s: %w", symbol, err)
+		return nil, fmt.Errorf("failed to get klines for %s: %w", query.Symbol, err)
 	}
 
 	klines := make([]*model.Kline, len(resp.Klines))
@@ -113,10 +187,139 @@ func (pc *PriceClient) GetKlines(ctx context.Context, symbol, interval string, s
 			Low:       k.Low,
 			Close:     k.Close,
 			Volume:    k.Volume,
+			Filled:    k.Filled,
+			Empty:     k.Empty,
 		}
 	}
 
-	return klines, nil
+	return &model.KlinePage{
+		Klines:     klines,
+		Total:      int(resp.Total),
+		NextCursor: resp.NextCursor,
+		Partial:    resp.Partial,
+	}, nil
+}
+
+// GetStatistics 獲取市場統計
+func (pc *PriceClient) GetStatistics(ctx context.Context, query model.StatisticsQuery) (*model.Statistics, error) {
+	ctx, cancel := context.WithTimeout(ctx, pc.cfg.GRPCTimeout)
+	defer cancel()
+
+	resp, err := pc.client.GetStatistics(ctx, &pb.GetStatisticsRequest{
+		Symbol:    query.Symbol,
+		Range:     query.Range,
+		StartTime: query.StartTime,
+		EndTime:   query.EndTime,
+		Timezone:  query.Timezone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics for %s: %w", query.Symbol, err)
+	}
+
+	return &model.Statistics{
+		Symbol:        resp.Symbol,
+		StartTime:     resp.StartTime,
+		EndTime:       resp.EndTime,
+		Open:          resp.Open,
+		High:          resp.High,
+		Low:           resp.Low,
+		Close:         resp.Close,
+		Mean:          resp.Mean,
+		VWAP:          resp.Vwap,
+		TWAP:          resp.Twap,
+		Change:        resp.Change,
+		ChangePercent: resp.ChangePercent,
+		TickCount:     resp.TickCount,
+		Volatility:    resp.Volatility,
+		Volume:        resp.Volume,
+	}, nil
+}
+
+// GetIndicators 獲取技術指標
+func (pc *PriceClient) GetIndicators(ctx context.Context, query model.IndicatorQuery) (*model.IndicatorSet, error) {
+	ctx, cancel := context.WithTimeout(ctx, pc.cfg.GRPCTimeout)
+	defer cancel()
+
+	specs := make([]*pb.IndicatorSpec, len(query.Indicators))
+	for i, spec := range query.Indicators {
+		specs[i] = &pb.IndicatorSpec{
+			Name:   spec.Name,
+			Params: spec.Params,
+		}
+	}
+
+	resp, err := pc.client.GetIndicators(ctx, &pb.GetIndicatorsRequest{
+		Symbol:       query.Symbol,
+		Interval:     query.Interval,
+		Indicators:   specs,
+		StartTime:    query.StartTime,
+		EndTime:      query.EndTime,
+		Limit:        query.Limit,
+		Timezone:     query.Timezone,
+		SessionStart: query.SessionStart,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indicators for %s: %w", query.Symbol, err)
+	}
+
+	series := make([]*model.IndicatorSeries, len(resp.Indicators))
+	for i, ind := range resp.Indicators {
+		lines := make([]*model.IndicatorLine, len(ind.Lines))
+		for j, line := range ind.Lines {
+			points := make([]*model.IndicatorPoint, len(line.Points))
+			for k, p := range line.Points {
+				points[k] = &model.IndicatorPoint{
+					Timestamp: p.Timestamp,
+					Value:     p.Value,
+				}
+			}
+			lines[j] = &model.IndicatorLine{Name: line.Name, Points: points}
+		}
+
+		series[i] = &model.IndicatorSeries{
+			Name:   ind.Name,
+			Params: ind.Params,
+			Lines:  lines,
+		}
+	}
+
+	return &model.IndicatorSet{
+		Indicators: series,
+		Partial:    resp.Partial,
+	}, nil
+}
+
+// GetTicks 分頁獲取原始 tick
+func (pc *PriceClient) GetTicks(ctx context.Context, query model.TickQuery) (*model.TickPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, pc.cfg.GRPCTimeout)
+	defer cancel()
+
+	resp, err := pc.client.GetTicks(ctx, &pb.GetTicksRequest{
+		Symbol:    query.Symbol,
+		StartTime: query.StartTime,
+		EndTime:   query.EndTime,
+		PageToken: query.PageToken,
+		PageSize:  query.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticks for %s: %w", query.Symbol, err)
+	}
+
+	ticks := make([]*model.Price, len(resp.Ticks))
+	for i, t := range resp.Ticks {
+		ticks[i] = &model.Price{
+			Symbol:        resp.Symbol,
+			Price:         t.Price,
+			Timestamp:     t.Timestamp,
+			Change:        t.Change,
+			ChangePercent: t.ChangePercent,
+		}
+	}
+
+	return &model.TickPage{
+		Ticks:         ticks,
+		NextPageToken: resp.NextPageToken,
+	}, nil
 }
 
 // SubscribePrices 訂閱價格流（Server Streaming）
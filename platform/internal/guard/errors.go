@@ -0,0 +1,11 @@
+package guard
+
+import "errors"
+
+var (
+	// ErrNotHalted 商品目前沒有暫停
+	ErrNotHalted = errors.New("symbol is not halted")
+
+	// ErrDisabled 價格檢查未啟用
+	ErrDisabled = errors.New("price validation is disabled")
+)
@@ -0,0 +1,372 @@
+package guard
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// minSigmaSamples 計算報酬標準差所需的最少參考價格筆數，不足時只檢查偏離百分比
+const minSigmaSamples = 20
+
+// Verdict 價格檢查結果
+type Verdict int
+
+const (
+	Accepted Verdict = iota
+	Rejected         // 偏離參考價格過多，丟棄此價格
+	Halted           // 商品暫停中（或因此價格暫停），丟棄此價格
+)
+
+// Listener 商品暫停狀態變化的回調（暫停或恢復）
+type Listener func(status model.HaltStatus)
+
+// Guard 推送前檢查每個商品的價格
+//
+// 參考價格為最近接受價格的平均：偏離超過 PRICE_VALIDATION_MAX_CHANGE_PERCENT，
+// 或相對上一筆接受價格的報酬超過參考期間報酬標準差的 PRICE_VALIDATION_MAX_SIGMA 倍即拒絕。
+// HALT_WINDOW 內接受的價格波動過大或被拒絕的價格過多時暫停商品，
+// 暫停後需手動恢復，或經過 HALT_RESUME_AFTER 自動恢復。時間範圍以價格的事件時間計算。
+type Guard struct {
+	maxSigma       float64
+	maxChange      float64 // 百分比
+	referenceSize  int
+	haltWindow     time.Duration
+	haltMaxMove    float64 // 百分比
+	haltRejections int
+	resumeAfter    time.Duration
+	onChange       Listener // 為 nil 時不通知
+	mu             sync.Mutex
+	symbols        map[string]*symbolState
+}
+
+// sample 接受的價格
+type sample struct {
+	at    int64 // 事件時間（Unix 毫秒）
+	price float64
+}
+
+// symbolState 單一商品的檢查狀態
+type symbolState struct {
+	reference  []float64 // 最近接受的價格，最多 referenceSize 筆
+	accepted   []sample  // haltWindow 內接受的價格
+	rejections []int64   // haltWindow 內被拒絕價格的事件時間
+	status     model.HaltStatus
+}
+
+// New 創建價格檢查
+func New(cfg *config.Config, onChange Listener) *Guard {
+	return &Guard{
+		maxSigma:       cfg.ValidationMaxSigma,
+		maxChange:      cfg.ValidationMaxChange,
+		referenceSize:  cfg.ValidationReferenceSize,
+		haltWindow:     cfg.HaltWindow,
+		haltMaxMove:    cfg.HaltMaxMove,
+		haltRejections: cfg.HaltMaxRejections,
+		resumeAfter:    cfg.HaltResumeAfter,
+		onChange:       onChange,
+		symbols:        make(map[string]*symbolState),
+	}
+}
+
+// Check 檢查價格是否可以推送，不是 Accepted 時返回原因
+func (g *Guard) Check(price *model.Price) (Verdict, string) {
+	now := time.Now()
+	var changed []model.HaltStatus
+
+	g.mu.Lock()
+	st := g.state(price.Symbol)
+
+	if st.status.Halted {
+		if !g.expired(st, now) {
+			g.mu.Unlock()
+			return Halted, st.status.Reason
+		}
+		g.resumeLocked(st)
+		changed = append(changed, st.status)
+	}
+
+	verdict, reason := g.checkLocked(st, price, now)
+	if verdict == Halted {
+		changed = append(changed, st.status)
+	}
+	g.mu.Unlock()
+
+	for _, status := range changed {
+		g.notify(status)
+	}
+	return verdict, reason
+}
+
+// Validate 只對照參考價格檢查價格，不更新參考價格、拒絕計數和暫停狀態，通過時返回空字串
+// 用於同一窗口中預設策略以外的策略價格：每個窗口只有預設策略的價格經過 Check 計入狀態
+func (g *Guard) Validate(price *model.Price) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	st, ok := g.symbols[price.Symbol]
+	if !ok {
+		st = &symbolState{}
+	}
+	return g.validate(st, price.Price)
+}
+
+// checkLocked 檢查價格並更新參考價格，呼叫端需持有鎖
+func (g *Guard) checkLocked(st *symbolState, price *model.Price, now time.Time) (Verdict, string) {
+	st.prune(price.Timestamp - g.haltWindow.Milliseconds())
+
+	if reason := g.validate(st, price.Price); reason != "" {
+		st.status.Rejected++
+		st.status.LastRejection = reason
+		st.rejections = append(st.rejections, price.Timestamp)
+
+		if g.haltRejections > 0 && len(st.rejections) >= g.haltRejections {
+			g.haltLocked(st, fmt.Sprintf("%d prices rejected within %s, last: %s",
+				len(st.rejections), g.haltWindow, reason), now, true)
+			return Halted, st.status.Reason
+		}
+		return Rejected, reason
+	}
+
+	st.accept(price, g.referenceSize)
+
+	if move := st.move(); g.haltMaxMove > 0 && move > g.haltMaxMove {
+		g.haltLocked(st, fmt.Sprintf("moved %.2f%% within %s (limit %.2f%%)",
+			move, g.haltWindow, g.haltMaxMove), now, true)
+		return Halted, st.status.Reason
+	}
+
+	return Accepted, ""
+}
+
+// validate 對照參考價格檢查價格，返回拒絕原因，通過時為空字串
+func (g *Guard) validate(st *symbolState, price float64) string {
+	if price <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return fmt.Sprintf("invalid price %v", price)
+	}
+
+	n := len(st.reference)
+	if n == 0 {
+		return ""
+	}
+
+	if g.maxChange > 0 {
+		var sum float64
+		for _, p := range st.reference {
+			sum += p
+		}
+		mean := sum / float64(n)
+
+		if change := math.Abs(price-mean) / mean * 100; change > g.maxChange {
+			return fmt.Sprintf("%.2f%% from reference %.4f (limit %.2f%%)", change, mean, g.maxChange)
+		}
+	}
+
+	if g.maxSigma > 0 && n >= minSigmaSamples {
+		// 參考期間對數報酬的標準差
+		var sum, sumSq float64
+		for i := 1; i < n; i++ {
+			r := math.Log(st.reference[i] / st.reference[i-1])
+			sum += r
+			sumSq += r * r
+		}
+		count := float64(n - 1)
+		mean := sum / count
+		sigma := math.Sqrt(math.Max(sumSq/count-mean*mean, 0))
+
+		if sigma > 0 {
+			r := math.Log(price / st.reference[n-1])
+			if z := math.Abs(r-mean) / sigma; z > g.maxSigma {
+				return fmt.Sprintf("%.1f sigma move from %.4f (limit %.1f)", z, st.reference[n-1], g.maxSigma)
+			}
+		}
+	}
+
+	return ""
+}
+
+// Halt 手動暫停商品，需手動恢復
+func (g *Guard) Halt(symbol, reason string) model.HaltStatus {
+	if reason == "" {
+		reason = "halted manually"
+	}
+
+	g.mu.Lock()
+	st := g.state(symbol)
+	g.haltLocked(st, reason, time.Now(), false)
+	status := st.status
+	g.mu.Unlock()
+
+	g.notify(status)
+	return status
+}
+
+// Resume 手動恢復暫停的商品，商品沒有暫停時返回 ErrNotHalted
+func (g *Guard) Resume(symbol string) (model.HaltStatus, error) {
+	g.mu.Lock()
+	st, ok := g.symbols[symbol]
+	if !ok || !st.status.Halted {
+		g.mu.Unlock()
+		return model.HaltStatus{}, ErrNotHalted
+	}
+	g.resumeLocked(st)
+	status := st.status
+	g.mu.Unlock()
+
+	g.notify(status)
+	return status, nil
+}
+
+// Run 定期恢復超過自動恢復時間的商品，直到 ctx 取消
+// 商品暫停期間可能沒有新價格觸發 Check，由此確保客戶端準時收到恢復通知
+func (g *Guard) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, status := range g.resumeExpired(now) {
+				g.notify(status)
+			}
+		}
+	}
+}
+
+// resumeExpired 恢復超過自動恢復時間的商品，返回已恢復的商品
+func (g *Guard) resumeExpired(now time.Time) []model.HaltStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var resumed []model.HaltStatus
+	for _, st := range g.symbols {
+		if st.status.Halted && g.expired(st, now) {
+			g.resumeLocked(st)
+			resumed = append(resumed, st.status)
+		}
+	}
+	return resumed
+}
+
+// Status 商品目前的暫停狀態，尚未檢查過價格時 ok 為 false
+func (g *Guard) Status(symbol string) (model.HaltStatus, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	st, ok := g.symbols[symbol]
+	if !ok {
+		return model.HaltStatus{}, false
+	}
+	return st.status, true
+}
+
+// Statuses 所有商品目前的暫停狀態，依商品排序
+func (g *Guard) Statuses() []model.HaltStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	statuses := make([]model.HaltStatus, 0, len(g.symbols))
+	for _, st := range g.symbols {
+		statuses = append(statuses, st.status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Symbol < statuses[j].Symbol
+	})
+	return statuses
+}
+
+// IsHalted 商品是否暫停中
+func (g *Guard) IsHalted(symbol string) bool {
+	status, ok := g.Status(symbol)
+	return ok && status.Halted
+}
+
+// state 商品的檢查狀態，不存在時創建，呼叫端需持有鎖
+func (g *Guard) state(symbol string) *symbolState {
+	st, ok := g.symbols[symbol]
+	if !ok {
+		st = &symbolState{status: model.HaltStatus{Symbol: symbol}}
+		g.symbols[symbol] = st
+	}
+	return st
+}
+
+// haltLocked 暫停商品，timed 為 true 且設定了 HALT_RESUME_AFTER 時自動恢復，呼叫端需持有鎖
+func (g *Guard) haltLocked(st *symbolState, reason string, now time.Time, timed bool) {
+	st.status.Halted = true
+	st.status.Reason = reason
+	st.status.HaltedAt = now.UnixMilli()
+	st.status.ResumeAt = 0
+	if timed && g.resumeAfter > 0 {
+		st.status.ResumeAt = now.Add(g.resumeAfter).UnixMilli()
+	}
+}
+
+// resumeLocked 恢復商品並清除參考價格，恢復後的第一筆價格成為新的參考，呼叫端需持有鎖
+func (g *Guard) resumeLocked(st *symbolState) {
+	st.status.Halted = false
+	st.status.Reason = ""
+	st.status.HaltedAt = 0
+	st.status.ResumeAt = 0
+	st.reference = nil
+	st.accepted = nil
+	st.rejections = nil
+}
+
+// expired 暫停是否已超過自動恢復時間，呼叫端需持有鎖
+func (g *Guard) expired(st *symbolState, now time.Time) bool {
+	return st.status.ResumeAt > 0 && now.UnixMilli() >= st.status.ResumeAt
+}
+
+func (g *Guard) notify(status model.HaltStatus) {
+	if g.onChange != nil {
+		g.onChange(status)
+	}
+}
+
+// accept 記錄接受的價格
+func (st *symbolState) accept(price *model.Price, referenceSize int) {
+	st.reference = append(st.reference, price.Price)
+	if len(st.reference) > referenceSize {
+		st.reference = st.reference[len(st.reference)-referenceSize:]
+	}
+	st.accepted = append(st.accepted, sample{at: price.Timestamp, price: price.Price})
+}
+
+// prune 移除事件時間早於 since（Unix 毫秒）的接受價格和拒絕記錄
+func (st *symbolState) prune(since int64) {
+	i := 0
+	for i < len(st.accepted) && st.accepted[i].at < since {
+		i++
+	}
+	st.accepted = st.accepted[i:]
+
+	j := 0
+	for j < len(st.rejections) && st.rejections[j] < since {
+		j++
+	}
+	st.rejections = st.rejections[j:]
+}
+
+// move 時間範圍內接受價格的最高和最低相差的百分比
+func (st *symbolState) move() float64 {
+	if len(st.accepted) < 2 {
+		return 0
+	}
+
+	low, high := st.accepted[0].price, st.accepted[0].price
+	for _, s := range st.accepted[1:] {
+		low = math.Min(low, s.price)
+		high = math.Max(high, s.price)
+	}
+	return (high - low) / low * 100
+}
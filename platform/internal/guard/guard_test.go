@@ -0,0 +1,262 @@
+package guard
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		ValidationMaxChange:     5,
+		ValidationReferenceSize: 10,
+		HaltWindow:              time.Minute,
+		HaltMaxMove:             3,
+		HaltMaxRejections:       3,
+		HaltResumeAfter:         time.Minute,
+	}
+}
+
+func TestCheck(t *testing.T) {
+	type tick struct {
+		price float64
+		at    int64 // 事件時間（Unix 毫秒）
+		want  Verdict
+	}
+
+	tests := []struct {
+		name  string
+		ticks []tick
+	}{
+		{
+			name: "accepts prices within the change limit",
+			ticks: []tick{
+				{price: 100, at: 1000, want: Accepted},
+				{price: 102, at: 2000, want: Accepted},
+			},
+		},
+		{
+			name: "rejects a price too far from the reference",
+			ticks: []tick{
+				{price: 100, at: 1000, want: Accepted},
+				{price: 110, at: 2000, want: Rejected},
+				{price: 101, at: 3000, want: Accepted},
+			},
+		},
+		{
+			name: "rejects invalid prices",
+			ticks: []tick{
+				{price: 0, at: 1000, want: Rejected},
+				{price: -1, at: 2000, want: Rejected},
+			},
+		},
+		{
+			name: "halts after repeated rejections",
+			ticks: []tick{
+				{price: 100, at: 1000, want: Accepted},
+				{price: 110, at: 2000, want: Rejected},
+				{price: 111, at: 3000, want: Rejected},
+				{price: 112, at: 4000, want: Halted},
+				{price: 100, at: 5000, want: Halted},
+			},
+		},
+		{
+			name: "forgets rejections outside the halt window",
+			ticks: []tick{
+				{price: 100, at: 0, want: Accepted},
+				{price: 110, at: 1000, want: Rejected},
+				{price: 110, at: 2000, want: Rejected},
+				{price: 110, at: 70000, want: Rejected},
+			},
+		},
+		{
+			name: "halts on a large move within the window",
+			ticks: []tick{
+				{price: 100, at: 1000, want: Accepted},
+				{price: 102, at: 2000, want: Accepted},
+				{price: 104, at: 3000, want: Halted},
+			},
+		},
+		{
+			name: "ignores moves spread beyond the window",
+			ticks: []tick{
+				{price: 100, at: 0, want: Accepted},
+				{price: 102, at: 30000, want: Accepted},
+				{price: 104, at: 70000, want: Accepted},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(testConfig(), nil)
+
+			for i, tick := range tt.ticks {
+				got, reason := g.Check(&model.Price{Symbol: "GOLD", Price: tick.price, Timestamp: tick.at})
+				if got != tick.want {
+					t.Fatalf("tick %d (%v): Check() = %d (%s), want %d", i, tick.price, got, reason, tick.want)
+				}
+				if (got == Accepted) != (reason == "") {
+					t.Errorf("tick %d: Check() reason = %q for verdict %d", i, reason, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSigma(t *testing.T) {
+	cfg := testConfig()
+	cfg.ValidationMaxChange = 0
+	cfg.ValidationMaxSigma = 3
+	cfg.ValidationReferenceSize = 30
+	cfg.HaltMaxMove = 0
+
+	g := New(cfg, nil)
+
+	// 在 100 和 100.1 之間來回，建立足夠的參考價格
+	for i := 0; i <= minSigmaSamples+1; i++ {
+		price := 100.0
+		if i%2 == 1 {
+			price = 100.1
+		}
+		if got, reason := g.Check(&model.Price{Symbol: "GOLD", Price: price, Timestamp: int64(i) * 1000}); got != Accepted {
+			t.Fatalf("reference %d: Check() = %d (%s)", i, got, reason)
+		}
+	}
+
+	tests := []struct {
+		price float64
+		want  Verdict
+	}{
+		{price: 100.1, want: Accepted},
+		{price: 101, want: Rejected},
+		{price: math.Inf(1), want: Rejected},
+	}
+
+	for _, tt := range tests {
+		if got, reason := g.Check(&model.Price{Symbol: "GOLD", Price: tt.price, Timestamp: 60000}); got != tt.want {
+			t.Errorf("Check(%v) = %d (%s), want %d", tt.price, got, reason, tt.want)
+		}
+	}
+}
+
+func TestCheckSigmaAtMinimumReferenceSize(t *testing.T) {
+	cfg := testConfig()
+	cfg.ValidationMaxChange = 0
+	cfg.ValidationMaxSigma = 3
+	cfg.ValidationReferenceSize = minSigmaSamples // 配置允許的最小值
+	cfg.HaltMaxMove = 0
+
+	g := New(cfg, nil)
+
+	for i := 0; i < minSigmaSamples; i++ {
+		price := 100.0
+		if i%2 == 1 {
+			price = 100.1
+		}
+		if got, reason := g.Check(&model.Price{Symbol: "GOLD", Price: price, Timestamp: int64(i) * 1000}); got != Accepted {
+			t.Fatalf("reference %d: Check() = %d (%s)", i, got, reason)
+		}
+	}
+
+	// 參考價格填滿後即檢查 sigma
+	if got, reason := g.Check(&model.Price{Symbol: "GOLD", Price: 101, Timestamp: 60000}); got != Rejected {
+		t.Errorf("Check(101) = %d (%s), want rejected", got, reason)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := testConfig()
+	cfg.HaltMaxMove = 0
+
+	g := New(cfg, nil)
+
+	// 沒有參考價格時只檢查價格本身
+	if reason := g.Validate(&model.Price{Symbol: "GOLD", Price: 100, Timestamp: 1000}); reason != "" {
+		t.Fatalf("Validate() without reference = %q, want accepted", reason)
+	}
+	if reason := g.Validate(&model.Price{Symbol: "GOLD", Price: 0, Timestamp: 1000}); reason == "" {
+		t.Fatal("Validate(0) accepted an invalid price")
+	}
+
+	if got, reason := g.Check(&model.Price{Symbol: "GOLD", Price: 100, Timestamp: 1000}); got != Accepted {
+		t.Fatalf("Check() = %d (%s), want accepted", got, reason)
+	}
+
+	// 偏離過大的價格被拒絕，但重複檢查不會累積拒絕次數而暫停商品
+	for i := 0; i < 5; i++ {
+		if reason := g.Validate(&model.Price{Symbol: "GOLD", Price: 110, Timestamp: 2000}); reason == "" {
+			t.Fatalf("Validate(110) #%d accepted a price 10%% from the reference", i)
+		}
+	}
+	if status, _ := g.Status("GOLD"); status.Halted || status.Rejected != 0 {
+		t.Errorf("Status() after Validate = %+v, want no rejections and not halted", status)
+	}
+
+	// 通過的價格不會成為參考價格
+	if reason := g.Validate(&model.Price{Symbol: "GOLD", Price: 104.9, Timestamp: 3000}); reason != "" {
+		t.Fatalf("Validate(104.9) = %q, want accepted", reason)
+	}
+	if got, reason := g.Check(&model.Price{Symbol: "GOLD", Price: 96, Timestamp: 4000}); got != Accepted {
+		t.Errorf("Check(96) = %d (%s), want accepted against the unchanged reference", got, reason)
+	}
+}
+
+func TestHaltAndResume(t *testing.T) {
+	var notified []model.HaltStatus
+	g := New(testConfig(), func(status model.HaltStatus) {
+		notified = append(notified, status)
+	})
+
+	if _, err := g.Resume("GOLD"); !errors.Is(err, ErrNotHalted) {
+		t.Fatalf("Resume() before halt error = %v, want ErrNotHalted", err)
+	}
+
+	// 手動暫停不會自動恢復
+	status := g.Halt("GOLD", "")
+	if !status.Halted || status.ResumeAt != 0 || status.Reason == "" {
+		t.Fatalf("Halt() = %+v, want a manual halt with a reason", status)
+	}
+	if got, _ := g.Check(&model.Price{Symbol: "GOLD", Price: 100, Timestamp: 1000}); got != Halted {
+		t.Fatalf("Check() while halted = %d, want Halted", got)
+	}
+	if resumed := g.resumeExpired(time.Now().Add(time.Hour)); len(resumed) != 0 {
+		t.Fatalf("resumeExpired() resumed a manual halt: %+v", resumed)
+	}
+
+	status, err := g.Resume("GOLD")
+	if err != nil || status.Halted {
+		t.Fatalf("Resume() = %+v, %v", status, err)
+	}
+	if len(notified) != 2 || !notified[0].Halted || notified[1].Halted {
+		t.Fatalf("notifications = %+v, want halt then resume", notified)
+	}
+
+	// 自動暫停在 HALT_RESUME_AFTER 後恢復，恢復後重新建立參考價格
+	g.Check(&model.Price{Symbol: "GOLD", Price: 100, Timestamp: 1000})
+	for i, price := range []float64{110, 111, 112} {
+		g.Check(&model.Price{Symbol: "GOLD", Price: price, Timestamp: int64(2000 + i*1000)})
+	}
+	status, _ = g.Status("GOLD")
+	if !status.Halted || status.ResumeAt == 0 {
+		t.Fatalf("status after rejections = %+v, want a timed halt", status)
+	}
+
+	if resumed := g.resumeExpired(time.Now()); len(resumed) != 0 {
+		t.Fatalf("resumeExpired() resumed before ResumeAt: %+v", resumed)
+	}
+	if resumed := g.resumeExpired(time.Now().Add(2 * time.Minute)); len(resumed) != 1 || resumed[0].Halted {
+		t.Fatalf("resumeExpired() = %+v, want GOLD resumed", resumed)
+	}
+
+	if got, reason := g.Check(&model.Price{Symbol: "GOLD", Price: 150, Timestamp: 10000}); got != Accepted {
+		t.Errorf("Check() after resume = %d (%s), want Accepted with a fresh reference", got, reason)
+	}
+	if g.IsHalted("GOLD") {
+		t.Error("IsHalted() = true after resume")
+	}
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mike/golden-buy/platform/internal/service"
+)
+
+// AdminServer 管理用 HTTP 服務器
+//
+// 暫停、恢復商品等會影響所有用戶的操作只註冊在這裡，不放在公開的路由上；
+// 監聽 ADMIN_HTTP_ADDR（預設只接受本機連線），不加 CORS，瀏覽器頁面無法跨站呼叫
+type AdminServer struct {
+	handler *Handler
+	engine  *gin.Engine
+	addr    string
+}
+
+// NewAdminServer 創建管理用 HTTP 服務器
+func NewAdminServer(addr string, svc *service.PlatformService) *AdminServer {
+	engine := gin.Default()
+	handler := NewHandler(svc)
+
+	admin := engine.Group("/admin")
+	{
+		prices := admin.Group("/prices")
+		{
+			prices.GET("/halts", handler.HandleGetHalts)
+			prices.POST("/halts", handler.HandleHaltSymbol)
+			prices.POST("/halts/resume", handler.HandleResumeSymbol)
+		}
+	}
+
+	return &AdminServer{
+		handler: handler,
+		engine:  engine,
+		addr:    addr,
+	}
+}
+
+// Start 啟動管理用 HTTP 服務器
+func (s *AdminServer) Start() error {
+	log.Printf("🔐 Starting admin HTTP server on %s", s.addr)
+	log.Println("📍 Admin endpoints:")
+	log.Println("   GET  /admin/prices/halts        - Get symbol halts and rejected prices")
+	log.Println("   POST /admin/prices/halts        - Halt a symbol manually")
+	log.Println("   POST /admin/prices/halts/resume - Resume a halted symbol")
+
+	return s.engine.Run(s.addr)
+}
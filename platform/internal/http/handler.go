@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,10 +10,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mike/golden-buy/platform/internal/audit"
+	"github.com/mike/golden-buy/platform/internal/guard"
 	"github.com/mike/golden-buy/platform/internal/model"
 	"github.com/mike/golden-buy/platform/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// supportedSymbols Price Service 支援的商品
+var supportedSymbols = []string{"GOLD", "SILVER", "PLATINUM", "PALLADIUM"}
+
 // Handler HTTP 處理器
 type Handler struct {
 	service *service.PlatformService
@@ -40,14 +48,36 @@ type PriceResponse struct {
 	ChangePercent float64   `json:"change_percent"`
 	Timestamp     int64     `json:"timestamp"`
 	UpdatedAt     time.Time `json:"updated_at"`
+	Stale         bool      `json:"stale"`                        // 超過 PRICE_STALE_THRESHOLD 沒有收到價格或心跳
+	LastUpdateAge *int64    `json:"last_update_age_ms,omitempty"` // 距離最後收到價格或心跳的時間（毫秒），直接從 Price Service 獲取時為空
+	Halted        bool      `json:"halted"`                       // 商品暫停中，價格為暫停前最後推送的價格
 }
 
 // KlineResponse K 線回應
 type KlineResponse struct {
-	Symbol   string         `json:"symbol"`
-	Interval string         `json:"interval"`
-	Klines   []*model.Kline `json:"klines"`
-	Count    int            `json:"count"`
+	Symbol     string         `json:"symbol"`
+	Interval   string         `json:"interval"`
+	Klines     []*model.Kline `json:"klines"`
+	Count      int            `json:"count"`
+	Total      int            `json:"total"`                 // 查詢範圍內的 K 線總數
+	NextCursor string         `json:"next_cursor,omitempty"` // 往前捲動載入下一頁時帶入 cursor 參數
+	Partial    bool           `json:"partial,omitempty"`     // 部分資料來源失敗，結果可能不完整
+}
+
+// TickResponse 原始 tick 回應
+type TickResponse struct {
+	Symbol        string         `json:"symbol"`
+	Ticks         []*model.Price `json:"ticks"`
+	Count         int            `json:"count"`
+	NextPageToken string         `json:"next_page_token,omitempty"` // 帶入 page_token 參數載入下一頁
+}
+
+// IndicatorResponse 技術指標回應
+type IndicatorResponse struct {
+	Symbol     string                   `json:"symbol"`
+	Interval   string                   `json:"interval"`
+	Indicators []*model.IndicatorSeries `json:"indicators"`
+	Partial    bool                     `json:"partial,omitempty"` // 部分資料來源失敗，結果可能不完整
 }
 
 // UserResponse 用戶回應
@@ -69,18 +99,23 @@ func (h *Handler) HandleGetCurrentPrice(c *gin.Context) {
 	// 如果指定了商品，返回單個商品價格
 	if symbol != "" {
 		// 優先從緩存獲取
-		price, err := h.service.GetLatestPrice(symbol)
+		if price, err := h.service.GetLatestPrice(symbol); err == nil {
+			c.JSON(http.StatusOK, Response{
+				Success: true,
+				Data:    h.withFeedStatus(convertToResponse(price)),
+			})
+			return
+		}
+
+		// 緩存沒有，從 Price Service 獲取
+		price, err := h.service.GetCurrentPriceFromService(c.Request.Context(), symbol)
 		if err != nil {
-			// 緩存沒有，從 Price Service 獲取
-			price, err = h.service.GetCurrentPriceFromService(c.Request.Context(), symbol)
-			if err != nil {
-				log.Printf("❌ Failed to get price for %s: %v", symbol, err)
-				c.JSON(http.StatusInternalServerError, Response{
-					Success: false,
-					Error:   "Failed to get price",
-				})
-				return
-			}
+			log.Printf("❌ Failed to get price for %s: %v", symbol, err)
+			c.JSON(http.StatusInternalServerError, Response{
+				Success: false,
+				Error:   "Failed to get price",
+			})
+			return
 		}
 
 		c.JSON(http.StatusOK, Response{
@@ -95,8 +130,7 @@ func (h *Handler) HandleGetCurrentPrice(c *gin.Context) {
 
 	// 如果緩存為空，從 Price Service 獲取
 	if len(prices) == 0 {
-		symbols := []string{"GOLD", "SILVER", "PLATINUM", "PALLADIUM"}
-		servicePrices, err := h.service.GetCurrentPricesFromService(c.Request.Context(), symbols)
+		servicePrices, err := h.service.GetCurrentPricesFromService(c.Request.Context(), supportedSymbols)
 		if err != nil {
 			log.Printf("❌ Failed to get prices: %v", err)
 			c.JSON(http.StatusInternalServerError, Response{
@@ -122,7 +156,7 @@ func (h *Handler) HandleGetCurrentPrice(c *gin.Context) {
 	// 轉換為回應格式
 	priceMap := make(map[string]*PriceResponse)
 	for symbol, price := range prices {
-		priceMap[symbol] = convertToResponse(price)
+		priceMap[symbol] = h.withFeedStatus(convertToResponse(price))
 	}
 
 	c.JSON(http.StatusOK, Response{
@@ -132,7 +166,7 @@ func (h *Handler) HandleGetCurrentPrice(c *gin.Context) {
 }
 
 // HandleGetHistory 獲取歷史 K 線資料
-// GET /api/prices/history?symbol=GOLD&interval=1m&start=1234567890000&end=1234567899000&limit=100
+// GET /api/prices/history?symbol=GOLD&interval=1m&start=1234567890000&end=1234567899000&limit=100&direction=latest&cursor=...&fill=previous&timezone=America/New_York&session_start=17:00
 func (h *Handler) HandleGetHistory(c *gin.Context) {
 	// 解析查詢參數
 	symbol := strings.ToUpper(c.Query("symbol"))
@@ -147,6 +181,32 @@ func (h *Handler) HandleGetHistory(c *gin.Context) {
 		return
 	}
 
+	if err := validateKlineParams(symbol, interval, c.Query("timezone"), c.Query("session_start")); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	direction := c.Query("direction")
+	if direction != "" && direction != "latest" && direction != "oldest" {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   fmt.Sprintf("unsupported direction: %s", direction),
+		})
+		return
+	}
+
+	fill := c.Query("fill")
+	if fill != "" && fill != "none" && fill != "previous" && fill != "null" {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   fmt.Sprintf("unsupported fill: %s", fill),
+		})
+		return
+	}
+
 	// 解析時間範圍
 	var startTime, endTime int64
 	var limit int32 = 100 // 預設 100 筆
@@ -176,90 +236,506 @@ func (h *Handler) HandleGetHistory(c *gin.Context) {
 	}
 
 	// 從服務獲取 K 線資料
-	klines, err := h.service.GetKlines(c.Request.Context(), symbol, interval, startTime, endTime, limit)
+	page, err := h.service.GetKlines(c.Request.Context(), model.KlineQuery{
+		Symbol:       symbol,
+		Interval:     interval,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Limit:        limit,
+		Direction:    direction,                // latest（預設）或 oldest
+		Cursor:       c.Query("cursor"),        // 上一頁的 next_cursor
+		Fill:         fill,                     // none（預設）、previous 或 null
+		Timezone:     c.Query("timezone"),      // 日線以上 K 線對齊的時區，預設 UTC
+		SessionStart: c.Query("session_start"), // 交易日開始時間 HH:MM，預設 00:00
+	})
 	if err != nil {
 		log.Printf("❌ Failed to get klines for %s %s: %v", symbol, interval, err)
+		writeUpstreamError(c, err, "Failed to get klines")
+		return
+	}
+
+	response := &KlineResponse{
+		Symbol:     symbol,
+		Interval:   interval,
+		Klines:     page.Klines,
+		Count:      len(page.Klines),
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		Partial:    page.Partial,
+	}
 
-		// 生成填充的空數據，而不是返回錯誤
-		filledKlines := h.generateFilledKlines(symbol, interval, startTime, endTime, limit)
-		log.Printf("📊 Generated %d filled klines for %s %s", len(filledKlines), symbol, interval)
+	var message string
+	if page.Partial {
+		message = "Partial data: the most recent klines may be missing"
+	}
 
-		response := &KlineResponse{
-			Symbol:   symbol,
-			Interval: interval,
-			Klines:   filledKlines,
-			Count:    len(filledKlines),
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    response,
+		Message: message,
+	})
+}
+
+// validateKlineParams 在請求 Price Service 之前驗證商品、K 線週期和對齊參數，讓參數錯誤直接回應 400
+func validateKlineParams(symbol, interval, timezone, sessionStart string) error {
+	if !isValidSymbol(symbol) {
+		return fmt.Errorf("unsupported symbol: %s", symbol)
+	}
+
+	if !isValidInterval(interval) {
+		return fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	return validateAlignment(timezone, sessionStart)
+}
+
+// validateAlignment 驗證時區和交易日開始時間（HH:MM）
+func validateAlignment(timezone, sessionStart string) error {
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("unsupported timezone: %s", timezone)
 		}
-		c.JSON(http.StatusOK, Response{
-			Success: true,
-			Data:    response,
-			Message: fmt.Sprintf("Generated filled data for %s %s (no historical data available)", symbol, interval),
+	}
+
+	if sessionStart != "" {
+		if _, err := time.Parse("15:04", sessionStart); err != nil {
+			return fmt.Errorf("session_start must be HH:MM: %s", sessionStart)
+		}
+	}
+
+	return nil
+}
+
+// isValidSymbol 驗證商品代碼是否為 Price Service 支援的商品
+func isValidSymbol(symbol string) bool {
+	for _, supported := range supportedSymbols {
+		if symbol == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// writeUpstreamError 回應 Price Service 的錯誤
+// 參數錯誤回應 400、查無資料回應 404 並附上原因，其餘視為上游異常回應 502
+func writeUpstreamError(c *gin.Context, err error, message string) {
+	code := http.StatusBadGateway
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		code = http.StatusBadRequest
+	case codes.NotFound:
+		code = http.StatusNotFound
+	}
+
+	if code != http.StatusBadGateway {
+		var se interface{ GRPCStatus() *status.Status }
+		if errors.As(err, &se) {
+			message = fmt.Sprintf("%s: %s", message, se.GRPCStatus().Message())
+		}
+	}
+
+	c.JSON(code, Response{
+		Success: false,
+		Error:   message,
+	})
+}
+
+// isValidInterval 驗證 K 線週期是否為 Price Service 支援的週期
+func isValidInterval(interval string) bool {
+	switch interval {
+	case "1s", "1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "1d", "1w", "1M":
+		return true
+	default:
+		return false
+	}
+}
+
+// HandleGetTicks 獲取原始 tick
+// GET /api/prices/ticks?symbol=GOLD&start=1234567890000&end=1234567950000&page_size=500&page_token=...
+// GET /api/prices/ticks?symbol=GOLD&at=1234567867000 (返回該秒內的所有 tick)
+func (h *Handler) HandleGetTicks(c *gin.Context) {
+	symbol := strings.ToUpper(c.Query("symbol"))
+
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "symbol is required",
 		})
 		return
 	}
 
-	response := &KlineResponse{
-		Symbol:   symbol,
-		Interval: interval,
-		Klines:   klines,
-		Count:    len(klines),
+	if !isValidSymbol(symbol) {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   fmt.Sprintf("unsupported symbol: %s", symbol),
+		})
+		return
+	}
+
+	query := model.TickQuery{
+		Symbol:    symbol,
+		PageToken: c.Query("page_token"),
+	}
+
+	if startStr := c.Query("start"); startStr != "" {
+		val, err := strconv.ParseInt(startStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Error:   "invalid start",
+			})
+			return
+		}
+		query.StartTime = val
+	}
+
+	if endStr := c.Query("end"); endStr != "" {
+		val, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Error:   "invalid end",
+			})
+			return
+		}
+		query.EndTime = val
+	}
+
+	// at 指定某一秒，查詢該秒內的所有 tick
+	if atStr := c.Query("at"); atStr != "" {
+		at, err := strconv.ParseInt(atStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Error:   "invalid at",
+			})
+			return
+		}
+		query.StartTime = at - at%1000
+		query.EndTime = query.StartTime + 1000
+	}
+
+	if sizeStr := c.Query("page_size"); sizeStr != "" {
+		if val, err := strconv.ParseInt(sizeStr, 10, 32); err == nil {
+			query.PageSize = int32(val)
+		}
+	}
+
+	page, err := h.service.GetTicks(c.Request.Context(), query)
+	if err != nil {
+		log.Printf("❌ Failed to get ticks for %s: %v", symbol, err)
+		writeUpstreamError(c, err, "Failed to get ticks")
+		return
 	}
 
 	c.JSON(http.StatusOK, Response{
 		Success: true,
-		Data:    response,
+		Data: &TickResponse{
+			Symbol:        symbol,
+			Ticks:         page.Ticks,
+			Count:         len(page.Ticks),
+			NextPageToken: page.NextPageToken,
+		},
 	})
 }
 
-// generateFilledKlines 生成填充的空 K 線數據
-func (h *Handler) generateFilledKlines(symbol, interval string, startTime, endTime int64, limit int32) []*model.Kline {
-	var klines []*model.Kline
+// HandleGetPriceAudit 獲取聚合窗口的價格審計記錄（合規與價格爭議查詢）
+// GET /api/prices/audit?symbol=GOLD&second=1234567867
+// GET /api/prices/audit?symbol=GOLD&timestamp=1234567867250
+func (h *Handler) HandleGetPriceAudit(c *gin.Context) {
+	symbol := strings.ToUpper(c.Query("symbol"))
+	secondStr := c.Query("second")
+	timestampStr := c.Query("timestamp")
 
-	// 計算時間間隔（毫秒）
-	intervalMs := h.getIntervalMs(interval)
-	if intervalMs == 0 {
-		intervalMs = 60 * 1000 // 預設 1 分鐘
+	if symbol == "" || (secondStr == "" && timestampStr == "") {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "symbol and second (or timestamp) are required",
+		})
+		return
 	}
 
-	// 生成時間序列
-	currentTime := startTime
-	count := 0
+	// timestamp 為 Unix 毫秒，用於 1 秒以下的聚合窗口；second 為 Unix 秒
+	var timestamp int64
+	if timestampStr != "" {
+		val, err := strconv.ParseInt(timestampStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Error:   "timestamp must be a Unix timestamp in milliseconds",
+			})
+			return
+		}
+		timestamp = val
+	} else {
+		second, err := strconv.ParseInt(secondStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Error:   "second must be a Unix timestamp in seconds",
+			})
+			return
+		}
+		timestamp = second * 1000
+	}
+
+	record, err := h.service.GetPriceAudit(c.Request.Context(), symbol, timestamp)
+	if err != nil {
+		switch {
+		case errors.Is(err, audit.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, Response{
+				Success: false,
+				Error:   "No audit record for this window",
+			})
+		case errors.Is(err, audit.ErrDisabled):
+			c.JSON(http.StatusServiceUnavailable, Response{
+				Success: false,
+				Error:   "Price audit is disabled",
+			})
+		default:
+			log.Printf("❌ Failed to get price audit for %s at %d: %v", symbol, timestamp, err)
+			c.JSON(http.StatusInternalServerError, Response{
+				Success: false,
+				Error:   "Failed to get price audit",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    record,
+	})
+}
 
-	for currentTime < endTime && count < int(limit) {
-		klines = append(klines, &model.Kline{
-			Timestamp: currentTime,
-			Open:      0,
-			High:      0,
-			Low:       0,
-			Close:     0,
+// HandleGetAggregationStats 獲取各商品事件時間聚合的 watermark 和遲到 tick 統計
+// GET /api/prices/aggregation
+func (h *Handler) HandleGetAggregationStats(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    h.service.GetAggregationStats(),
+	})
+}
+
+// HandleGetIndicators 獲取技術指標
+// GET /api/prices/indicators?symbol=GOLD&interval=1m&indicators=sma:20,ema:50,bollinger:20:2,rsi:14,macd:12:26:9&start=1234567890000&end=1234567899000&limit=100
+func (h *Handler) HandleGetIndicators(c *gin.Context) {
+	// 解析查詢參數
+	symbol := strings.ToUpper(c.Query("symbol"))
+	interval := c.DefaultQuery("interval", "1m")
+
+	// 必需參數檢查
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "symbol is required",
 		})
-		currentTime += intervalMs
-		count++
+		return
 	}
 
-	return klines
+	if err := validateKlineParams(symbol, interval, c.Query("timezone"), c.Query("session_start")); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	specs, err := parseIndicatorSpecs(c.Query("indicators"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 解析時間範圍
+	var startTime, endTime int64
+	var limit int32 = 100 // 預設 100 筆
+
+	if startStr := c.Query("start"); startStr != "" {
+		if val, err := strconv.ParseInt(startStr, 10, 64); err == nil {
+			startTime = val
+		}
+	}
+
+	if endStr := c.Query("end"); endStr != "" {
+		if val, err := strconv.ParseInt(endStr, 10, 64); err == nil {
+			endTime = val
+		}
+	}
+
+	// 如果沒有指定時間範圍，使用最近 1 小時（與 K 線歷史一致）
+	if startTime == 0 || endTime == 0 {
+		endTime = time.Now().UnixMilli()
+		startTime = endTime - (60 * 60 * 1000) // 1 小時前
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.ParseInt(limitStr, 10, 32); err == nil {
+			limit = int32(val)
+		}
+	}
+
+	set, err := h.service.GetIndicators(c.Request.Context(), model.IndicatorQuery{
+		Symbol:       symbol,
+		Interval:     interval,
+		Indicators:   specs,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Limit:        limit,
+		Timezone:     c.Query("timezone"),
+		SessionStart: c.Query("session_start"),
+	})
+	if err != nil {
+		log.Printf("❌ Failed to get indicators for %s %s: %v", symbol, interval, err)
+		writeUpstreamError(c, err, "Failed to get indicators")
+		return
+	}
+
+	var message string
+	if set.Partial {
+		message = "Partial data: the most recent klines may be missing"
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data: &IndicatorResponse{
+			Symbol:     symbol,
+			Interval:   interval,
+			Indicators: set.Indicators,
+			Partial:    set.Partial,
+		},
+		Message: message,
+	})
 }
 
-// getIntervalMs 獲取時間間隔的毫秒數
-func (h *Handler) getIntervalMs(interval string) int64 {
-	switch interval {
-	case "1m":
-		return 60 * 1000
-	case "5m":
-		return 5 * 60 * 1000
-	case "15m":
-		return 15 * 60 * 1000
-	case "30m":
-		return 30 * 60 * 1000
-	case "1h":
-		return 60 * 60 * 1000
-	case "4h":
-		return 4 * 60 * 60 * 1000
-	case "1d":
-		return 24 * 60 * 60 * 1000
-	default:
-		return 60 * 1000 // 預設 1 分鐘
+// parseIndicatorSpecs 解析指標列表，格式為以逗號分隔的 name[:param...]，例如 sma:20,bollinger:20:2,rsi
+// 指標名稱和參數範圍由 Price Service 驗證
+func parseIndicatorSpecs(value string) ([]model.IndicatorSpec, error) {
+	if value == "" {
+		return nil, fmt.Errorf("indicators is required")
 	}
+
+	var specs []model.IndicatorSpec
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if parts[0] == "" {
+			return nil, fmt.Errorf("invalid indicator: %q", item)
+		}
+
+		spec := model.IndicatorSpec{Name: strings.ToLower(parts[0])}
+		for _, p := range parts[1:] {
+			param, err := strconv.ParseFloat(p, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter for %s: %q", spec.Name, p)
+			}
+			spec.Params = append(spec.Params, param)
+		}
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// HandleGetStatistics 獲取市場統計
+// GET /api/prices/statistics?symbol=GOLD&range=24h
+// GET /api/prices/statistics?symbol=GOLD&range=ytd&timezone=Asia/Taipei
+// GET /api/prices/statistics?symbol=GOLD&start=1234567890000&end=1234567899000
+func (h *Handler) HandleGetStatistics(c *gin.Context) {
+	symbol := strings.ToUpper(c.Query("symbol"))
+	statsRange := c.DefaultQuery("range", "24h")
+
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "symbol is required",
+		})
+		return
+	}
+
+	if !isValidSymbol(symbol) {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   fmt.Sprintf("unsupported symbol: %s", symbol),
+		})
+		return
+	}
+
+	if err := validateAlignment(c.Query("timezone"), ""); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	query := model.StatisticsQuery{
+		Symbol:   symbol,
+		Range:    statsRange,
+		Timezone: c.Query("timezone"),
+	}
+
+	if startStr := c.Query("start"); startStr != "" {
+		val, err := strconv.ParseInt(startStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Error:   "invalid start",
+			})
+			return
+		}
+		query.StartTime = val
+	} else if !isValidStatisticsRange(statsRange) {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   fmt.Sprintf("unsupported range: %s", statsRange),
+		})
+		return
+	}
+
+	if endStr := c.Query("end"); endStr != "" {
+		val, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Error:   "invalid end",
+			})
+			return
+		}
+		query.EndTime = val
+	}
+
+	stats, err := h.service.GetStatistics(c.Request.Context(), query)
+	if err != nil {
+		log.Printf("❌ Failed to get statistics for %s: %v", symbol, err)
+		writeUpstreamError(c, err, "Failed to get statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// isValidStatisticsRange 驗證統計範圍是否為 Price Service 支援的格式（30m、24h、7d、4w 或 ytd）
+func isValidStatisticsRange(statsRange string) bool {
+	if statsRange == "ytd" {
+		return true
+	}
+
+	if len(statsRange) < 2 || len(statsRange) > 5 {
+		return false
+	}
+
+	n, err := strconv.Atoi(statsRange[:len(statsRange)-1])
+	if err != nil || n <= 0 {
+		return false
+	}
+
+	return strings.ContainsRune("mhdw", rune(statsRange[len(statsRange)-1]))
 }
 
 // HandleGetUserInfo 獲取用戶資訊（Demo 版本）
@@ -284,9 +760,15 @@ func (h *Handler) HandleGetUserInfo(c *gin.Context) {
 // GET /health
 func (h *Handler) HandleHealthCheck(c *gin.Context) {
 	health := map[string]interface{}{
-		"status":    "healthy",
-		"service":   "platform-gateway",
-		"timestamp": time.Now().Unix(),
+		"status":        "healthy",
+		"service":       "platform-gateway",
+		"timestamp":     time.Now().Unix(),
+		"price_source":  h.service.GetPriceSource(),
+		"price_service": h.service.GetPriceServiceState(),
+	}
+
+	if stats, ok := h.service.GetKlineCacheStats(); ok {
+		health["kline_cache"] = stats
 	}
 
 	c.JSON(http.StatusOK, Response{
@@ -295,6 +777,108 @@ func (h *Handler) HandleHealthCheck(c *gin.Context) {
 	})
 }
 
+// HandleGetFeedStatus 獲取各商品價格來源的狀態（live 或 stale）
+// GET /api/prices/feed
+func (h *Handler) HandleGetFeedStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    h.service.GetFeedStatuses(),
+	})
+}
+
+// withFeedStatus 以商品價格來源和暫停狀態標記緩存的價格
+func (h *Handler) withFeedStatus(resp *PriceResponse) *PriceResponse {
+	if status, ok := h.service.GetFeedStatus(resp.Symbol); ok {
+		resp.Stale = status.Status == model.FeedStale
+		resp.LastUpdateAge = &status.Age
+	}
+	if status, ok := h.service.GetHaltStatus(resp.Symbol); ok {
+		resp.Halted = status.Halted
+	}
+	return resp
+}
+
+// HandleGetHalts 獲取各商品的暫停狀態和被拒絕的價格數
+// GET /api/prices/halts
+func (h *Handler) HandleGetHalts(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    h.service.GetHaltStatuses(),
+	})
+}
+
+// HandleHaltSymbol 手動暫停商品的價格推送，需以 HandleResumeSymbol 恢復
+// POST /admin/prices/halts?symbol=GOLD&reason=maintenance
+func (h *Handler) HandleHaltSymbol(c *gin.Context) {
+	symbol := strings.ToUpper(c.Query("symbol"))
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "symbol is required",
+		})
+		return
+	}
+
+	status, err := h.service.HaltSymbol(symbol, c.Query("reason"))
+	if err != nil {
+		h.handleHaltError(c, symbol, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    status,
+		Message: "Symbol halted",
+	})
+}
+
+// HandleResumeSymbol 手動恢復暫停的商品
+// POST /admin/prices/halts/resume?symbol=GOLD
+func (h *Handler) HandleResumeSymbol(c *gin.Context) {
+	symbol := strings.ToUpper(c.Query("symbol"))
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "symbol is required",
+		})
+		return
+	}
+
+	status, err := h.service.ResumeSymbol(symbol)
+	if err != nil {
+		h.handleHaltError(c, symbol, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    status,
+		Message: "Symbol resumed",
+	})
+}
+
+// handleHaltError 回應暫停或恢復商品的錯誤
+func (h *Handler) handleHaltError(c *gin.Context, symbol string, err error) {
+	switch {
+	case errors.Is(err, guard.ErrNotHalted):
+		c.JSON(http.StatusConflict, Response{
+			Success: false,
+			Error:   "Symbol is not halted",
+		})
+	case errors.Is(err, guard.ErrDisabled):
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Success: false,
+			Error:   "Price validation is disabled",
+		})
+	default:
+		log.Printf("❌ Failed to update halt status for %s: %v", symbol, err)
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Error:   "Failed to update halt status",
+		})
+	}
+}
+
 // convertToResponse 轉換 Price 為回應格式
 func convertToResponse(price *model.Price) *PriceResponse {
 	return &PriceResponse{
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWriteUpstreamError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "invalid argument", err: status.Error(codes.InvalidArgument, "游標格式錯誤"), want: http.StatusBadRequest},
+		{name: "wrapped not found", err: fmt.Errorf("failed to get statistics: %w", status.Error(codes.NotFound, "沒有資料")), want: http.StatusNotFound},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "connection refused"), want: http.StatusBadGateway},
+		{name: "internal", err: status.Error(codes.Internal, "InfluxDB 連接失敗"), want: http.StatusBadGateway},
+		{name: "plain error", err: errors.New("boom"), want: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			writeUpstreamError(c, tt.err, "Failed to get klines")
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateKlineParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		symbol       string
+		interval     string
+		timezone     string
+		sessionStart string
+		wantErr      bool
+	}{
+		{name: "valid", symbol: "GOLD", interval: "1d", timezone: "Asia/Taipei", sessionStart: "17:00"},
+		{name: "defaults", symbol: "SILVER", interval: "1m"},
+		{name: "unknown symbol", symbol: "FOO", interval: "1m", wantErr: true},
+		{name: "unknown interval", symbol: "GOLD", interval: "7m", wantErr: true},
+		{name: "unknown timezone", symbol: "GOLD", interval: "1d", timezone: "Mars/Olympus", wantErr: true},
+		{name: "invalid session start", symbol: "GOLD", interval: "1d", sessionStart: "25:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKlineParams(tt.symbol, tt.interval, tt.timezone, tt.sessionStart)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKlineParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
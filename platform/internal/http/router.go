@@ -30,7 +30,7 @@ func NewServer(addr string, svc *service.PlatformService, wsHub *websocket.Hub)
 
 	// 創建處理器
 	handler := NewHandler(svc)
-	wsHandler := websocket.NewHandler(wsHub)
+	wsHandler := websocket.NewHandler(wsHub, svc.AuthenticateToken)
 
 	// 健康檢查
 	engine.GET("/health", handler.HandleHealthCheck)
@@ -43,6 +43,13 @@ func NewServer(addr string, svc *service.PlatformService, wsHub *websocket.Hub)
 		{
 			prices.GET("/current", handler.HandleGetCurrentPrice)
 			prices.GET("/history", handler.HandleGetHistory)
+			prices.GET("/statistics", handler.HandleGetStatistics)
+			prices.GET("/indicators", handler.HandleGetIndicators)
+			prices.GET("/ticks", handler.HandleGetTicks)
+			prices.GET("/audit", handler.HandleGetPriceAudit)
+			prices.GET("/aggregation", handler.HandleGetAggregationStats)
+			prices.GET("/feed", handler.HandleGetFeedStatus)
+			prices.GET("/halts", handler.HandleGetHalts)
 		}
 
 		// 用戶相關路由
@@ -72,6 +79,13 @@ func (s *Server) Start() error {
 	log.Println("   GET  /health                  - Health check")
 	log.Println("   GET  /api/prices/current      - Get current prices")
 	log.Println("   GET  /api/prices/history      - Get historical klines")
+	log.Println("   GET  /api/prices/statistics   - Get market statistics")
+	log.Println("   GET  /api/prices/indicators   - Get technical indicators")
+	log.Println("   GET  /api/prices/ticks        - Get raw ticks")
+	log.Println("   GET  /api/prices/audit        - Get per-window price audit")
+	log.Println("   GET  /api/prices/aggregation  - Get aggregation watermarks and late ticks")
+	log.Println("   GET  /api/prices/feed         - Get price feed status (live/stale)")
+	log.Println("   GET  /api/prices/halts        - Get symbol halts and rejected prices")
 	log.Println("   GET  /api/user/info           - Get user info (demo)")
 	log.Println("   WS   /ws/prices               - WebSocket price stream")
 
@@ -0,0 +1,169 @@
+package klinecache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// calendarMaxStep 日線以上 K 線的最長週期（月線），用於判斷範圍內的 K 線是否都已收盤
+const calendarMaxStep = 31 * 24 * time.Hour
+
+// Loader 從 Price Service 讀取 K 線
+type Loader func(ctx context.Context, query model.KlineQuery) (*model.KlinePage, error)
+
+// Cache K 線查詢結果的 LRU 緩存
+//
+// 查詢範圍對齊到 K 線邊界後作為 key，同一根 K 線內的圖表載入共用同一筆結果。
+// 範圍內的 K 線都已收盤（並經過 KLINE_CACHE_SETTLE_DELAY）的結果不會過期，只會被 LRU 淘汰；
+// 包含未收盤 K 線或部分資料的結果保存 KLINE_CACHE_OPEN_TTL。
+// 相同查詢同時只有一個請求送到 Price Service，其餘等待並共用結果。
+type Cache struct {
+	load    Loader
+	size    int
+	openTTL time.Duration
+	settle  time.Duration
+	mu      sync.Mutex
+	entries map[model.KlineQuery]*list.Element
+	lru     *list.List // 最近使用的在前
+	calls   map[model.KlineQuery]*call
+	stats   model.KlineCacheStats
+}
+
+// entry 緩存的查詢結果
+type entry struct {
+	key       model.KlineQuery
+	page      *model.KlinePage
+	expiresAt time.Time // 零值表示不會過期
+}
+
+// call 進行中的 Price Service 請求
+type call struct {
+	done chan struct{}
+	page *model.KlinePage
+	err  error
+}
+
+// New 創建 K 線緩存
+func New(cfg *config.Config, load Loader) *Cache {
+	return &Cache{
+		load:    load,
+		size:    cfg.KlineCacheSize,
+		openTTL: cfg.KlineCacheOpenTTL,
+		settle:  cfg.KlineCacheSettleDelay,
+		entries: make(map[model.KlineQuery]*list.Element),
+		lru:     list.New(),
+		calls:   make(map[model.KlineQuery]*call),
+	}
+}
+
+// Get 獲取 K 線，緩存沒有或已過期時從 Price Service 讀取
+// 返回的結果由所有呼叫端共用，不可修改
+func (c *Cache) Get(ctx context.Context, query model.KlineQuery) (*model.KlinePage, error) {
+	key := normalize(query)
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		if e.expiresAt.IsZero() || time.Now().Before(e.expiresAt) {
+			c.lru.MoveToFront(elem)
+			c.stats.Hits++
+			c.mu.Unlock()
+			return e.page, nil
+		}
+		c.removeLocked(elem)
+	}
+
+	if cl, ok := c.calls[key]; ok {
+		c.stats.Coalesced++
+		c.mu.Unlock()
+		return cl.wait(ctx)
+	}
+
+	cl := &call{done: make(chan struct{})}
+	c.calls[key] = cl
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// 請求由等待中的呼叫端共用，第一個呼叫端取消不影響其他呼叫端
+	cl.page, cl.err = c.load(context.WithoutCancel(ctx), key)
+
+	c.mu.Lock()
+	delete(c.calls, key)
+	if cl.err == nil {
+		c.storeLocked(key, cl.page)
+	}
+	c.mu.Unlock()
+	close(cl.done)
+
+	return cl.page, cl.err
+}
+
+// Stats 緩存的命中統計
+func (c *Cache) Stats() model.KlineCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// storeLocked 保存查詢結果並淘汰最久未使用的結果，呼叫端需持有鎖
+func (c *Cache) storeLocked(key model.KlineQuery, page *model.KlinePage) {
+	e := &entry{key: key, page: page}
+	if page.Partial || !c.settled(key, time.Now()) {
+		e.expiresAt = time.Now().Add(c.openTTL)
+	}
+
+	c.entries[key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.size {
+		c.removeLocked(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *Cache) removeLocked(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key)
+}
+
+// settled 查詢範圍內的 K 線是否都已收盤並經過等待時間（遲到的價格已寫入）
+func (c *Cache) settled(query model.KlineQuery, now time.Time) bool {
+	end := query.EndTime
+	if _, ok := query.Step(); !ok {
+		// 日線以上的最後一根 K 線可能在查詢範圍之後才收盤
+		end += calendarMaxStep.Milliseconds()
+	}
+	return end > 0 && end+c.settle.Milliseconds() <= now.UnixMilli()
+}
+
+// wait 等待進行中的請求完成
+func (cl *call) wait(ctx context.Context) (*model.KlinePage, error) {
+	select {
+	case <-cl.done:
+		return cl.page, cl.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// normalize 將查詢範圍對齊到 K 線邊界：開始時間向下、結束時間向上
+// 第一根 K 線從邊界開始完整彙總，最後一根 K 線（可能未收盤）包含到目前為止的價格
+func normalize(query model.KlineQuery) model.KlineQuery {
+	d, ok := query.Step()
+	if !ok {
+		return query
+	}
+
+	ms := d.Milliseconds()
+	query.StartTime -= query.StartTime % ms
+	if rem := query.EndTime % ms; rem != 0 {
+		query.EndTime += ms - rem
+	}
+	return query
+}
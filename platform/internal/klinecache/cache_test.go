@@ -0,0 +1,238 @@
+package klinecache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     model.KlineQuery
+		wantStart int64
+		wantEnd   int64
+	}{
+		{
+			name:      "unaligned minute range widens to boundaries",
+			query:     model.KlineQuery{Interval: "1m", StartTime: 90500, EndTime: 150500},
+			wantStart: 60000,
+			wantEnd:   180000,
+		},
+		{
+			name:      "aligned range is unchanged",
+			query:     model.KlineQuery{Interval: "5m", StartTime: 300000, EndTime: 900000},
+			wantStart: 300000,
+			wantEnd:   900000,
+		},
+		{
+			name:      "default daily",
+			query:     model.KlineQuery{Interval: "1d", StartTime: 86400000 + 1, EndTime: 2 * 86400000},
+			wantStart: 86400000,
+			wantEnd:   2 * 86400000,
+		},
+		{
+			name:      "daily with a time zone is not aligned",
+			query:     model.KlineQuery{Interval: "1d", Timezone: "Asia/Taipei", StartTime: 1, EndTime: 2},
+			wantStart: 1,
+			wantEnd:   2,
+		},
+		{
+			name:      "weekly is not aligned",
+			query:     model.KlineQuery{Interval: "1w", StartTime: 1, EndTime: 2},
+			wantStart: 1,
+			wantEnd:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalize(tt.query)
+			if got.StartTime != tt.wantStart || got.EndTime != tt.wantEnd {
+				t.Errorf("normalize() = [%d, %d), want [%d, %d)", got.StartTime, got.EndTime, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestSettled(t *testing.T) {
+	now := time.UnixMilli(100 * 86400000)
+	c := New(&config.Config{KlineCacheSize: 1, KlineCacheSettleDelay: 5 * time.Second}, nil)
+
+	tests := []struct {
+		name  string
+		query model.KlineQuery
+		want  bool
+	}{
+		{name: "closed past the settle delay", query: model.KlineQuery{Interval: "1m", EndTime: now.UnixMilli() - 5000}, want: true},
+		{name: "within the settle delay", query: model.KlineQuery{Interval: "1m", EndTime: now.UnixMilli() - 4999}},
+		{name: "open kline", query: model.KlineQuery{Interval: "1m", EndTime: now.UnixMilli() + 60000}},
+		{name: "no end time", query: model.KlineQuery{Interval: "1m"}},
+		{name: "monthly may close after the range", query: model.KlineQuery{Interval: "1M", EndTime: now.UnixMilli() - 86400000}},
+		{name: "monthly long ago", query: model.KlineQuery{Interval: "1M", EndTime: now.UnixMilli() - 40*86400000}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.settled(tt.query, now); got != tt.want {
+				t.Errorf("settled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingLoader 記錄讀取次數的 Loader，每次讀取返回新的結果
+type countingLoader struct {
+	loads   int64
+	partial bool
+	err     error
+}
+
+func (l *countingLoader) load(ctx context.Context, query model.KlineQuery) (*model.KlinePage, error) {
+	l.loads++
+	if l.err != nil {
+		return nil, l.err
+	}
+	return &model.KlinePage{Total: int(l.loads), Partial: l.partial}, nil
+}
+
+func TestCacheGet(t *testing.T) {
+	past := time.Now().Add(-time.Hour).Truncate(time.Minute).UnixMilli()
+	closed := model.KlineQuery{Symbol: "GOLD", Interval: "1m", StartTime: past - 600000, EndTime: past}
+	open := model.KlineQuery{Symbol: "GOLD", Interval: "1m", StartTime: past, EndTime: time.Now().Add(time.Hour).UnixMilli()}
+
+	tests := []struct {
+		name      string
+		loader    countingLoader
+		size      int
+		queries   []model.KlineQuery
+		wantLoads int64
+	}{
+		{
+			name:      "closed range is served from the cache",
+			size:      10,
+			queries:   []model.KlineQuery{closed, closed, closed},
+			wantLoads: 1,
+		},
+		{
+			name: "queries within the same kline share an entry",
+			size: 10,
+			queries: []model.KlineQuery{
+				closed,
+				{Symbol: "GOLD", Interval: "1m", StartTime: closed.StartTime + 1500, EndTime: closed.EndTime - 1500},
+			},
+			wantLoads: 1,
+		},
+		{
+			name:      "open range expires after the open TTL",
+			size:      10,
+			queries:   []model.KlineQuery{open, open},
+			wantLoads: 2,
+		},
+		{
+			name:      "partial result expires after the open TTL",
+			loader:    countingLoader{partial: true},
+			size:      10,
+			queries:   []model.KlineQuery{closed, closed},
+			wantLoads: 2,
+		},
+		{
+			name:      "errors are not cached",
+			loader:    countingLoader{err: errors.New("unavailable")},
+			size:      10,
+			queries:   []model.KlineQuery{closed, closed},
+			wantLoads: 2,
+		},
+		{
+			name: "least recently used entry is evicted",
+			size: 1,
+			queries: []model.KlineQuery{
+				closed,
+				{Symbol: "SILVER", Interval: "1m", StartTime: closed.StartTime, EndTime: closed.EndTime},
+				closed,
+			},
+			wantLoads: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 未收盤的結果立即過期
+			c := New(&config.Config{KlineCacheSize: tt.size, KlineCacheOpenTTL: 0}, tt.loader.load)
+
+			for _, query := range tt.queries {
+				c.Get(context.Background(), query)
+			}
+
+			if got := tt.loader.loads; got != tt.wantLoads {
+				t.Errorf("loads = %d, want %d", got, tt.wantLoads)
+			}
+			if stats := c.Stats(); stats.Hits+stats.Misses != int64(len(tt.queries)) || stats.Misses != tt.wantLoads {
+				t.Errorf("stats = %+v, want %d misses of %d requests", stats, tt.wantLoads, len(tt.queries))
+			}
+		})
+	}
+}
+
+func TestCacheCoalescesConcurrentRequests(t *testing.T) {
+	const callers = 5
+
+	release := make(chan struct{})
+	var loads atomic.Int64
+	load := func(ctx context.Context, query model.KlineQuery) (*model.KlinePage, error) {
+		loads.Add(1)
+		<-release
+		// 第一個呼叫端取消不影響共用的請求
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return &model.KlinePage{Total: 1}, nil
+	}
+
+	c := New(&config.Config{KlineCacheSize: 10}, load)
+	query := model.KlineQuery{Symbol: "GOLD", Interval: "1m", StartTime: 0, EndTime: 60000}
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	pages := make([]*model.KlinePage, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pages[0], errs[0] = c.Get(firstCtx, query)
+	}()
+	for loads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pages[i], errs[i] = c.Get(context.Background(), query)
+		}(i)
+	}
+	for c.Stats().Coalesced < callers-1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancelFirst()
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Fatalf("loads = %d, want 1", got)
+	}
+	for i := range pages {
+		if errs[i] != nil || pages[i] != pages[0] {
+			t.Errorf("caller %d = %p, %v, want the shared page", i, pages[i], errs[i])
+		}
+	}
+}
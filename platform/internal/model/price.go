@@ -1,5 +1,10 @@
 package model
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Price 價格資料結構
 type Price struct {
 	Symbol        string  `json:"symbol"`
@@ -7,6 +12,32 @@ type Price struct {
 	Timestamp     int64   `json:"timestamp"`
 	Change        float64 `json:"change"`
 	ChangePercent float64 `json:"change_percent"`
+	Heartbeat     bool    `json:"-"` // Price Service 的心跳訊息，只表示價格來源仍在運作，不是新的價格
+}
+
+// 價格來源狀態
+const (
+	FeedLive  = "live"  // 門檻時間內收到過價格或心跳
+	FeedStale = "stale" // 超過門檻時間沒有收到任何價格或心跳
+)
+
+// FeedStatus 商品價格來源的狀態
+type FeedStatus struct {
+	Symbol     string `json:"symbol"`
+	Status     string `json:"status"`      // live 或 stale
+	LastUpdate int64  `json:"last_update"` // 最後收到價格或心跳的本機時間（Unix 毫秒）
+	Age        int64  `json:"age_ms"`      // 距離最後收到價格或心跳的時間（毫秒）
+}
+
+// HaltStatus 商品的暫停狀態與價格檢查結果，暫停期間不推送價格
+type HaltStatus struct {
+	Symbol        string `json:"symbol"`
+	Halted        bool   `json:"halted"`
+	Reason        string `json:"reason,omitempty"`         // 暫停原因
+	HaltedAt      int64  `json:"halted_at,omitempty"`      // 暫停時間（Unix 毫秒）
+	ResumeAt      int64  `json:"resume_at,omitempty"`      // 自動恢復時間（Unix 毫秒），0 表示需手動恢復
+	Rejected      int64  `json:"rejected"`                 // 累計被拒絕的價格數
+	LastRejection string `json:"last_rejection,omitempty"` // 最近一次拒絕價格的原因
 }
 
 // Kline K 線資料結構
@@ -17,13 +48,203 @@ type Kline struct {
 	Low       float64 `json:"low"`
 	Close     float64 `json:"close"`
 	Volume    float64 `json:"volume"`
+	Filled    bool    `json:"filled,omitempty"` // 缺口填補產生的 K 線
+	Empty     bool    `json:"-"`                // fill=null 產生的空 K 線，序列化時 OHLC 輸出為 null
+}
+
+// MarshalJSON 空 K 線的 OHLC 輸出為 null，讓圖表顯示為空白而不是 0
+func (k Kline) MarshalJSON() ([]byte, error) {
+	type kline Kline
+	if !k.Empty {
+		return json.Marshal(kline(k))
+	}
+
+	return json.Marshal(struct {
+		Timestamp int64    `json:"timestamp"`
+		Open      *float64 `json:"open"`
+		High      *float64 `json:"high"`
+		Low       *float64 `json:"low"`
+		Close     *float64 `json:"close"`
+		Volume    *float64 `json:"volume"`
+		Filled    bool     `json:"filled"`
+	}{
+		Timestamp: k.Timestamp,
+		Filled:    true,
+	})
+}
+
+// KlineQuery K 線查詢條件，字串欄位為空時使用 Price Service 的預設值
+type KlineQuery struct {
+	Symbol       string
+	Interval     string
+	StartTime    int64 // Unix 毫秒
+	EndTime      int64 // Unix 毫秒
+	Limit        int32
+	Direction    string // latest 或 oldest
+	Cursor       string // 上一頁的 NextCursor
+	Fill         string // none、previous 或 null
+	Timezone     string // 日線以上 K 線對齊的 IANA 時區
+	SessionStart string // 交易日開始時間 HH:MM
+}
+
+// fixedKlineSteps 固定長度的 K 線週期
+// 日線只有預設對齊（UTC、00:00 開盤）時為固定長度，週線、月線依日曆對齊
+var fixedKlineSteps = map[string]time.Duration{
+	"1s":  time.Second,
+	"1m":  time.Minute,
+	"3m":  3 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"2h":  2 * time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// FixedKlineIntervals 固定長度的 K 線週期及其長度（日線為 UTC 對齊）
+func FixedKlineIntervals() map[string]time.Duration {
+	intervals := make(map[string]time.Duration, len(fixedKlineSteps))
+	for interval, step := range fixedKlineSteps {
+		intervals[interval] = step
+	}
+	return intervals
 }
 
-// PriceBuffer 每秒內價格緩衝區
+// Step 查詢週期為固定長度時返回 K 線長度，K 線從 Unix epoch 起以此長度對齊
+func (q KlineQuery) Step() (time.Duration, bool) {
+	if q.Interval == "1d" && (q.Timezone != "" || q.SessionStart != "") {
+		return 0, false
+	}
+	step, ok := fixedKlineSteps[q.Interval]
+	return step, ok
+}
+
+// KlinePage K 線分頁結果
+type KlinePage struct {
+	Klines     []*Kline
+	Total      int    // 查詢範圍內的 K 線總數
+	NextCursor string // 下一頁游標，沒有更多資料時為空
+	Partial    bool   // 部分資料來源失敗，結果可能不完整
+}
+
+// KlineCacheStats K 線緩存的命中統計
+type KlineCacheStats struct {
+	Entries   int   `json:"entries"`   // 目前緩存的查詢結果數
+	Hits      int64 `json:"hits"`      // 直接從緩存返回的請求數
+	Misses    int64 `json:"misses"`    // 送到 Price Service 的請求數
+	Coalesced int64 `json:"coalesced"` // 等待相同查詢進行中的請求、共用結果的請求數
+	Evictions int64 `json:"evictions"` // 因超過 KLINE_CACHE_SIZE 被淘汰的結果數
+}
+
+// TickQuery 原始 tick 查詢條件，時間為 0 時使用 Price Service 的預設值
+type TickQuery struct {
+	Symbol    string
+	StartTime int64 // Unix 毫秒
+	EndTime   int64 // Unix 毫秒，與 StartTime 相差最多 1 小時
+	PageToken string
+	PageSize  int32
+}
+
+// TickPage 原始 tick 分頁結果
+type TickPage struct {
+	Ticks         []*Price // 依時間升序排列
+	NextPageToken string   // 下一頁標記，沒有更多資料時為空
+}
+
+// IndicatorSpec 技術指標規格，Params 為空時使用 Price Service 的預設參數
+type IndicatorSpec struct {
+	Name   string    // sma、ema、bollinger、rsi、macd
+	Params []float64 // 例如 bollinger [20, 2]、macd [12, 26, 9]
+}
+
+// IndicatorQuery 技術指標查詢條件，字串欄位為空時使用 Price Service 的預設值
+type IndicatorQuery struct {
+	Symbol       string
+	Interval     string
+	Indicators   []IndicatorSpec
+	StartTime    int64 // Unix 毫秒
+	EndTime      int64 // Unix 毫秒
+	Limit        int32 // 計算範圍內最新的 N 根 K 線
+	Timezone     string
+	SessionStart string
+}
+
+// IndicatorPoint 指標在某根 K 線上的數值
+type IndicatorPoint struct {
+	Timestamp int64   `json:"timestamp"` // 對應 K 線的開始時間
+	Value     float64 `json:"value"`
+}
+
+// IndicatorLine 指標輸出的一條線，例如布林通道的 upper
+type IndicatorLine struct {
+	Name   string            `json:"name"`
+	Points []*IndicatorPoint `json:"points"`
+}
+
+// IndicatorSeries 單一指標的計算結果
+type IndicatorSeries struct {
+	Name   string           `json:"name"`
+	Params []float64        `json:"params"`
+	Lines  []*IndicatorLine `json:"lines"`
+}
+
+// IndicatorSet 技術指標查詢結果
+type IndicatorSet struct {
+	Indicators []*IndicatorSeries
+	Partial    bool // 部分 K 線資料來源失敗，結果可能不完整
+}
+
+// StatisticsQuery 市場統計查詢條件，字串欄位為空時使用 Price Service 的預設值
+type StatisticsQuery struct {
+	Symbol    string
+	Range     string // 30m、24h、7d、4w 或 ytd，指定 StartTime 時忽略
+	StartTime int64  // Unix 毫秒
+	EndTime   int64  // Unix 毫秒
+	Timezone  string // ytd 年初使用的 IANA 時區
+}
+
+// Statistics 市場統計資料結構
+type Statistics struct {
+	Symbol        string  `json:"symbol"`
+	StartTime     int64   `json:"start_time"`
+	EndTime       int64   `json:"end_time"`
+	Open          float64 `json:"open"`
+	High          float64 `json:"high"`
+	Low           float64 `json:"low"`
+	Close         float64 `json:"close"`
+	Mean          float64 `json:"mean"` // tick 價格的算術平均
+	VWAP          float64 `json:"vwap"` // 成交量加權平均價，範圍內沒有成交量時為 0
+	TWAP          float64 `json:"twap"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	TickCount     int64   `json:"tick_count"`
+	Volatility    float64 `json:"volatility"` // 1 分鐘對數報酬的已實現波動率（未年化）
+	Volume        float64 `json:"volume"`     // 範圍內的總成交量
+}
+
+// PriceBuffer 一個聚合窗口內的價格緩衝區
 type PriceBuffer struct {
 	Prices    []Price
 	Symbol    string
-	Timestamp int64 // 秒級時間戳
+	Timestamp int64 // 窗口開始時間（Unix 毫秒）
+	Window    int64 // 窗口長度（毫秒）
+}
+
+// End 窗口結束時間（Unix 毫秒，不含）
+func (pb *PriceBuffer) End() int64 {
+	return pb.Timestamp + pb.Window
+}
+
+// AggregationStats 單一商品事件時間聚合的狀態
+type AggregationStats struct {
+	Symbol        string `json:"symbol"`
+	Watermark     int64  `json:"watermark"`      // Unix 毫秒，結束時間不晚於此的窗口已關閉
+	MaxEventTime  int64  `json:"max_event_time"` // 已收到的最新 tick 事件時間（Unix 毫秒）
+	OpenWindows   int    `json:"open_windows"`   // 仍在收集中的窗口數
+	ClosedWindows int64  `json:"closed_windows"` // 已關閉並推送的窗口數
+	LateTicks     int64  `json:"late_ticks"`     // 所屬窗口已關閉而被丟棄的 tick 數
+	LastLateTick  int64  `json:"last_late_tick"` // 最近一筆遲到 tick 的事件時間（Unix 毫秒）
 }
 
 // GetBestPrice 獲取緩衝區內最佳價格（最低買入價）
@@ -57,3 +278,63 @@ func (pb *PriceBuffer) GetWorstPrice() *Price {
 
 	return &worst
 }
+
+// 推送前價格檢查的結果，記錄在審計中
+const (
+	VerdictAccepted = "accepted" // 通過檢查並推送
+	VerdictRejected = "rejected" // 偏離參考價格過多，未推送
+	VerdictHalted   = "halted"   // 商品暫停中（或因此價格暫停），未推送
+)
+
+// PriceSelection 一個聚合窗口依每個選價策略選出的價格
+type PriceSelection struct {
+	Symbol        string
+	Strategy      string            // 商品設定的預設策略
+	Prices        map[string]*Price // 策略名稱 -> 選出的價格
+	WindowStart   int64             // 窗口開始時間（Unix 毫秒）
+	Window        int64             // 窗口長度（毫秒）
+	Stale         bool              // 窗口結束後超過門檻時間才推送，例如切換價格來源後補齊的窗口
+	Verdict       string            // 推送前價格檢查的結果，由處理回調設定
+	VerdictReason string            // 未通過檢查的原因
+	Rejected      map[string]string // 未通過檢查的其他策略價格：策略名稱 -> 原因，這些策略改用預設策略的價格
+}
+
+// Default 預設策略選出的價格
+func (s *PriceSelection) Default() *Price {
+	return s.Prices[s.Strategy]
+}
+
+// Get 指定策略選出的價格，策略為空、不存在或價格未通過檢查時返回預設策略的價格
+func (s *PriceSelection) Get(strategy string) (*Price, string) {
+	if _, rejected := s.Rejected[strategy]; rejected {
+		return s.Default(), s.Strategy
+	}
+	if price, ok := s.Prices[strategy]; ok && strategy != "" {
+		return price, strategy
+	}
+	return s.Default(), s.Strategy
+}
+
+// Reject 標記策略選出的價格未通過檢查
+func (s *PriceSelection) Reject(strategy, reason string) {
+	if s.Rejected == nil {
+		s.Rejected = make(map[string]string)
+	}
+	s.Rejected[strategy] = reason
+}
+
+// PriceAudit 聚合窗口的價格審計記錄：該窗口收到的所有候選價格、套用的策略和最終選中的價格
+type PriceAudit struct {
+	Symbol      string            `json:"symbol"`
+	Second      int64             `json:"second"`               // 窗口開始時間（Unix 秒）
+	WindowStart int64             `json:"window_start"`         // 窗口開始時間（Unix 毫秒）
+	Window      int64             `json:"window"`               // 窗口長度（毫秒），舊記錄為 0（1 秒）
+	Strategy    string            `json:"strategy"`             // 選價策略名稱
+	Candidates  []Price           `json:"candidates"`           // 依收到順序排列
+	Selected    Price             `json:"selected"`             // 預設策略選中的價格
+	Selections  map[string]Price  `json:"selections,omitempty"` // 每個策略選出的價格（用戶可各自套用不同策略）
+	Verdict     string            `json:"verdict,omitempty"`    // 價格檢查結果：accepted、rejected 或 halted，舊記錄為空
+	Reason      string            `json:"reason,omitempty"`     // 未通過檢查的原因
+	Rejected    map[string]string `json:"rejected,omitempty"`   // 未通過檢查、改推送預設策略價格的其他策略：策略名稱 -> 原因
+	RecordedAt  int64             `json:"recorded_at"`          // 記錄時間（Unix 毫秒）
+}
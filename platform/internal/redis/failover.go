@@ -0,0 +1,197 @@
+package redis
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/bus"
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// pingTimeout 重連前檢查訊息匯流排的逾時
+const pingTimeout = 2 * time.Second
+
+// FailoverSource 訊息匯流排中斷時的備援價格來源（Price Service gRPC）
+type FailoverSource interface {
+	// SubscribePrices 接收即時價格串流，symbols 為空時接收所有商品
+	SubscribePrices(ctx context.Context, symbols []string, callback func(*model.Price)) error
+	// GetTicks 分頁獲取原始 tick，用於補齊切換來源前遺漏的價格
+	GetTicks(ctx context.Context, query model.TickQuery) (*model.TickPage, error)
+}
+
+// SetFailoverSource 設置訊息匯流排中斷時的備援價格來源，需在 Start 之前調用
+func (s *Subscriber) SetFailoverSource(source FailoverSource) {
+	s.failoverMu.Lock()
+	defer s.failoverMu.Unlock()
+
+	s.failoverSource = source
+}
+
+// Source 目前的價格來源：MESSAGE_BUS 的名稱，或切換到備援來源時為 grpc
+func (s *Subscriber) Source() string {
+	s.failoverMu.Lock()
+	defer s.failoverMu.Unlock()
+
+	if s.failoverCancel != nil {
+		return "grpc"
+	}
+	return s.cfg.MessageBus
+}
+
+// consume 從訊息匯流排接收價格，直到 context 取消
+// 訂閱中斷時切換到備援來源，並以指數退避重連；重連後收到第一筆價格即切回訊息匯流排。
+// 兩個來源重疊期間的重複 tick 由窗口去除，所以切換時不會重複或遺漏窗口。
+func (s *Subscriber) consume() error {
+	backoff := s.cfg.BusReconnectMinBackoff
+
+	for {
+		s.received.Store(false)
+		err := s.source.Subscribe(s.ctx, s.handleBusPrice)
+		if s.ctx.Err() != nil {
+			return s.ctx.Err()
+		}
+
+		// 這次訂閱有收到價格表示連線曾經恢復，退避時間重新計算
+		if s.received.Load() {
+			backoff = s.cfg.BusReconnectMinBackoff
+		}
+
+		log.Printf("❌ Message bus subscription lost: %v", err)
+		s.startFailover()
+
+		for {
+			log.Printf("🔁 Reconnecting to message bus in %s", backoff)
+			select {
+			case <-s.ctx.Done():
+				return s.ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, s.cfg.BusReconnectMaxBackoff)
+
+			ctx, cancel := context.WithTimeout(s.ctx, pingTimeout)
+			err := s.source.Ping(ctx)
+			cancel()
+			if err == nil {
+				break
+			}
+			log.Printf("❌ Message bus still unavailable: %v", err)
+		}
+
+		log.Printf("✅ Message bus reachable, resubscribing")
+	}
+}
+
+// handleBusPrice 處理訊息匯流排的價格，備援來源接收中時切回訊息匯流排
+func (s *Subscriber) handleBusPrice(price *model.Price, ack bus.Ack) {
+	s.received.Store(true)
+	s.stopFailover()
+	s.receive(price, ack)
+}
+
+// startFailover 開始從備援來源接收價格
+func (s *Subscriber) startFailover() {
+	s.failoverMu.Lock()
+	defer s.failoverMu.Unlock()
+
+	if s.failoverSource == nil || s.failoverCancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.failoverCancel = cancel
+
+	log.Printf("🛟 Failing over to Price Service gRPC stream")
+	s.wg.Add(1)
+	go s.runFailover(ctx)
+}
+
+// stopFailover 停止從備援來源接收價格
+func (s *Subscriber) stopFailover() {
+	s.failoverMu.Lock()
+	defer s.failoverMu.Unlock()
+
+	if s.failoverCancel == nil {
+		return
+	}
+
+	s.failoverCancel()
+	s.failoverCancel = nil
+	log.Printf("✅ Message bus recovered, switched back from gRPC stream")
+}
+
+// runFailover 接收 gRPC 價格串流直到 ctx 取消，串流中斷時以指數退避重新訂閱
+// 每次串流收到第一筆價格時，先以 GetTicks 補齊各商品最後一筆價格之後遺漏的 tick
+func (s *Subscriber) runFailover(ctx context.Context) {
+	defer s.wg.Done()
+
+	backoff := s.cfg.BusReconnectMinBackoff
+	for {
+		first := true
+		err := s.failoverSource.SubscribePrices(ctx, nil, func(price *model.Price) {
+			if first {
+				first = false
+				backoff = s.cfg.BusReconnectMinBackoff
+				s.backfill(ctx, price.Timestamp)
+			}
+			s.receive(price, nil)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("❌ gRPC price stream error: %v, retrying in %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.cfg.BusReconnectMaxBackoff)
+	}
+}
+
+// backfill 補齊各商品最後收到的價格到 until（Unix 毫秒）之間的 tick
+// 已推送窗口的 tick 會被視為遲到丟棄，重複的 tick 由窗口去除
+func (s *Subscriber) backfill(ctx context.Context, until int64) {
+	s.mu.RLock()
+	since := make(map[string]int64, len(s.windows))
+	for symbol, windows := range s.windows {
+		since[symbol] = windows.maxEventTime
+	}
+	s.mu.RUnlock()
+
+	for symbol, last := range since {
+		// GetTicks 的查詢範圍最多 1 小時
+		query := model.TickQuery{
+			Symbol:    symbol,
+			StartTime: max(last+1, until-time.Hour.Milliseconds()),
+			EndTime:   until,
+		}
+		if query.StartTime > query.EndTime {
+			continue
+		}
+
+		count := 0
+		for {
+			page, err := s.failoverSource.GetTicks(ctx, query)
+			if err != nil {
+				log.Printf("❌ [%s] Failed to backfill ticks: %v", symbol, err)
+				break
+			}
+
+			for _, tick := range page.Ticks {
+				s.addToBuffer(tick, nil)
+			}
+			count += len(page.Ticks)
+
+			if page.NextPageToken == "" {
+				break
+			}
+			query.PageToken = page.NextPageToken
+		}
+
+		if count > 0 {
+			log.Printf("🧩 [%s] Backfilled %d ticks (%d ~ %d)", symbol, count, query.StartTime, until)
+		}
+	}
+}
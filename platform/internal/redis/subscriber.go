@@ -2,162 +2,172 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/mike/golden-buy/platform/internal/bus"
 	"github.com/mike/golden-buy/platform/internal/config"
 	"github.com/mike/golden-buy/platform/internal/model"
-	"github.com/redis/go-redis/v9"
+	"github.com/mike/golden-buy/platform/internal/strategy"
 )
 
-const (
-	// PriceUpdatesChannel Redis Pub/Sub 頻道名稱
-	PriceUpdatesChannel = "price:updates"
-)
+// PriceHandler 價格處理回調函數，每個聚合窗口調用一次，包含每個策略選出的價格
+type PriceHandler func(*model.PriceSelection)
+
+// AuditRecorder 每秒價格審計記錄器
+type AuditRecorder interface {
+	Record(record *model.PriceAudit) error
+}
 
-// PriceHandler 價格處理回調函數
-type PriceHandler func(*model.Price)
+// flushDivisor 檢查 watermark 的間隔為最短聚合窗口的幾分之一（1s 窗口每 100ms 檢查一次）
+const flushDivisor = 10
 
-// Subscriber Redis 訂閱器
+// FeedRecorder 記錄商品收到價格或心跳的時間，用於判斷價格來源是否中斷
+type FeedRecorder interface {
+	Touch(symbol string, at time.Time)
+}
+
+// Subscriber 價格訂閱器，從訊息匯流排接收價格，依事件時間按窗口聚合，每個窗口選出一筆推送
 type Subscriber struct {
-	client  *redis.Client
-	cfg     *config.Config
-	mu      sync.RWMutex
-	buffers map[string]*model.PriceBuffer // symbol -> buffer
-	ticker  *time.Ticker
-	ctx     context.Context
-	cancel  context.CancelFunc
-	wg      sync.WaitGroup
+	source         bus.Subscriber // 價格事件來源（MESSAGE_BUS）
+	cfg            *config.Config
+	mu             sync.RWMutex
+	windows        map[string]*symbolWindows             // symbol -> 事件時間窗口
+	window         time.Duration                         // AGGREGATION_WINDOW
+	symWindows     map[string]time.Duration              // symbol -> 聚合窗口長度，未設定的商品使用 window
+	lateness       time.Duration                         // AGGREGATION_ALLOWED_LATENESS
+	registry       *strategy.Registry                    // 所有可用的選價策略，每個窗口都會全部計算
+	strategies     map[string]strategy.SelectionStrategy // symbol -> 選價策略，未設定的商品使用 fallback
+	fallback       strategy.SelectionStrategy            // PRICE_STRATEGY
+	auditor        AuditRecorder                         // 為 nil 時不記錄審計
+	feed           FeedRecorder                          // 為 nil 時不記錄
+	failoverSource FailoverSource                        // 訊息匯流排中斷時的備援來源，為 nil 時只重連
+	failoverCancel context.CancelFunc                    // 備援來源接收中時不為 nil
+	failoverMu     sync.Mutex
+	received       atomic.Bool // 本次訂閱後是否已從訊息匯流排收到價格
+	ticker         *time.Ticker
+	ctx            context.Context
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
 }
 
-// NewSubscriber 創建新的 Redis 訂閱器
+// NewSubscriber 創建新的價格訂閱器，依 MESSAGE_BUS 連接對應的訊息匯流排
 func NewSubscriber(cfg *config.Config) (*Subscriber, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisAddr,
-		Password: cfg.RedisPassword,
-		DB:       cfg.RedisDB,
-	})
+	registry := strategy.NewRegistry(cfg.SpreadAdjustFactor)
 
-	// 測試連接
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	fallback, err := registry.Get(cfg.PriceStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRICE_STRATEGY (available: %v): %w", registry.Names(), err)
+	}
+
+	strategies := make(map[string]strategy.SelectionStrategy, len(cfg.SymbolStrategies))
+	for symbol, name := range cfg.SymbolStrategies {
+		st, err := registry.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PRICE_STRATEGY_SYMBOLS for %s (available: %v): %w", symbol, registry.Names(), err)
+		}
+		strategies[symbol] = st
+	}
 
-	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect to redis at %s: %w", cfg.RedisAddr, err)
+	// 用戶和角色的策略只在推送時挑選，這裡只檢查名稱
+	for _, assigned := range []map[string]string{cfg.UserStrategies, cfg.RoleStrategies} {
+		for who, name := range assigned {
+			if _, err := registry.Get(name); err != nil {
+				return nil, fmt.Errorf("invalid price strategy for %s (available: %v): %w", who, registry.Names(), err)
+			}
+		}
+	}
+
+	source, err := bus.NewSubscriber(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
 
 	sub := &Subscriber{
-		client:  client,
-		cfg:     cfg,
-		buffers: make(map[string]*model.PriceBuffer),
-		ticker:  time.NewTicker(1 * time.Second),
-		ctx:     ctx,
-		cancel:  cancelFunc,
+		source:     source,
+		cfg:        cfg,
+		windows:    make(map[string]*symbolWindows),
+		window:     cfg.AggregationWindow,
+		symWindows: cfg.SymbolWindows,
+		lateness:   cfg.AggregationLateness,
+		registry:   registry,
+		strategies: strategies,
+		fallback:   fallback,
+		ticker:     time.NewTicker(flushInterval(cfg)),
+		ctx:        ctx,
+		cancel:     cancelFunc,
 	}
 
 	return sub, nil
 }
 
-// Start 開始訂閱 Redis 價格更新
+// Start 開始訂閱價格更新
 func (s *Subscriber) Start(handler PriceHandler) error {
-	// 訂閱價格更新頻道
-	pubsub := s.client.Subscribe(s.ctx, PriceUpdatesChannel)
-	defer pubsub.Close()
-
-	// 確認訂閱成功
-	_, err := pubsub.Receive(s.ctx)
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to channel %s: %w", PriceUpdatesChannel, err)
+	log.Printf("📡 Message bus: %s", s.cfg.MessageBus)
+	log.Printf("📊 Price strategy: %s", s.fallback.Name())
+	for symbol, st := range s.strategies {
+		log.Printf("📊 Price strategy for %s: %s", symbol, st.Name())
 	}
 
-	log.Printf("✅ Subscribed to Redis channel: %s", PriceUpdatesChannel)
-	log.Printf("📊 Price strategy: %s", s.cfg.PriceStrategy)
+	log.Printf("⏱️  Aggregation window: %s, allowed lateness: %s", s.window, s.lateness)
+	for symbol, window := range s.symWindows {
+		log.Printf("⏱️  Aggregation window for %s: %s", symbol, window)
+	}
 
-	// 啟動定時處理器（每秒處理一次緩衝區）
+	// 啟動定時處理器（watermark 越過窗口結束時間即關閉窗口）
 	s.wg.Add(1)
-	log.Printf("🚀 Starting processBuffers goroutine")
 	go s.processBuffers(handler)
 
-	// 立即執行一次處理，確保定時器工作
-	log.Printf("⏰ Immediate flush buffers")
-	s.flushBuffers(handler)
-
-	// 強制啟動定時器
-	go func() {
-		ticker := time.NewTicker(1 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-s.ctx.Done():
-				return
-			case <-ticker.C:
-				log.Printf("⏰ Force timer tick - flushing buffers")
-				s.flushBuffers(handler)
-			}
-		}
-	}()
-
-	// 接收訊息
-	ch := pubsub.Channel()
-	for {
-		select {
-		case <-s.ctx.Done():
-			log.Println("📴 Subscriber context cancelled")
-			return s.ctx.Err()
-
-		case msg, ok := <-ch:
-			if !ok {
-				return fmt.Errorf("redis channel closed")
-			}
+	// 接收訊息並加入緩衝區，直到 context 取消；連線中斷時重連並暫時改用備援來源
+	return s.consume()
+}
 
-			// 解析價格更新
-			var price model.Price
-			if err := json.Unmarshal([]byte(msg.Payload), &price); err != nil {
-				log.Printf("❌ Failed to unmarshal price: %v", err)
-				continue
-			}
+// receive 處理即時收到的價格：記錄價格來源仍在運作，心跳訊息不加入窗口
+// ack 不為 nil 時在價格所屬窗口推送後調用，心跳訊息直接確認
+func (s *Subscriber) receive(price *model.Price, ack bus.Ack) {
+	if s.feed != nil {
+		s.feed.Touch(price.Symbol, time.Now())
+	}
 
-			// 將價格加入緩衝區
-			s.addToBuffer(&price)
+	if price.Heartbeat {
+		if ack != nil {
+			ack()
 		}
+		return
 	}
+	s.addToBuffer(price, ack)
 }
 
-// addToBuffer 將價格加入緩衝區
-func (s *Subscriber) addToBuffer(price *model.Price) {
+// addToBuffer 將價格加入所屬的事件時間窗口，窗口已關閉的 tick 記為遲到並丟棄
+// 加入窗口的 tick 在窗口推送後才確認，遲到和重複的 tick 直接確認
+func (s *Subscriber) addToBuffer(price *model.Price, ack bus.Ack) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	symbol := price.Symbol
-	currentSecond := price.Timestamp / 1000 // 轉換為秒級時間戳
-
-	// 獲取或創建該商品的緩衝區
-	buffer, exists := s.buffers[symbol]
-	if !exists || buffer.Timestamp != currentSecond {
-		// 創建新的緩衝區（新的一秒）
-		buffer = &model.PriceBuffer{
-			Symbol:    symbol,
-			Timestamp: currentSecond,
-			Prices:    make([]model.Price, 0, 3), // 預分配空間給 3 筆價格
-		}
-		s.buffers[symbol] = buffer
+	windows, exists := s.windows[symbol]
+	if !exists {
+		windows = newSymbolWindows(s.WindowFor(symbol))
+		s.windows[symbol] = windows
 	}
 
-	// 加入價格到緩衝區
-	buffer.Prices = append(buffer.Prices, *price)
+	result := windows.add(price, ack, time.Now())
+	if result == tickLate {
+		log.Printf("⏰ [%s] Late tick dropped: event time %d, window %d already closed (late ticks: %d)",
+			symbol, price.Timestamp, windows.windowStart(price.Timestamp), windows.lateTicks)
+	}
+	s.mu.Unlock()
 
-	// 日誌記錄（調試用）
-	if len(buffer.Prices) == 1 {
-		log.Printf("📝 [%s] New second buffer: %d", symbol, currentSecond)
+	if result != tickAdded && ack != nil {
+		ack()
 	}
 }
 
-// processBuffers 定時處理緩衝區（每秒執行一次）
+// processBuffers 定時關閉 watermark 已越過的窗口
 func (s *Subscriber) processBuffers(handler PriceHandler) {
 	defer s.wg.Done()
 
@@ -167,67 +177,167 @@ func (s *Subscriber) processBuffers(handler PriceHandler) {
 			return
 
 		case <-s.ticker.C:
-			log.Printf("⏰ Timer tick - flushing buffers")
 			s.flushBuffers(handler)
 		}
 	}
 }
 
-// flushBuffers 清空緩衝區並選擇最佳/最差價格
+// closedWindow 已關閉、等待推送的窗口
+type closedWindow struct {
+	symbol string
+	buffer *model.PriceBuffer
+}
+
+// flushBuffers 依時間順序推送已關閉的窗口，以每個策略選出價格
+// 窗口推送後才確認其中的訊息，推送前中斷時訊息由持久化的傳輸重新投遞
+// 推送、確認和審計記錄都在鎖外進行，Redis 變慢或中斷時不阻塞接收價格（包括備援來源）
 func (s *Subscriber) flushBuffers(handler PriceHandler) {
+	var closed []closedWindow
+	var acks []bus.Ack
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	now := time.Now()
 
-	currentSecond := time.Now().Unix()
+	for symbol, windows := range s.windows {
+		buffers, closedAcks := windows.closeUpTo(windows.watermark(now, s.lateness))
+		for _, buffer := range buffers {
+			if len(buffer.Prices) > 0 {
+				closed = append(closed, closedWindow{symbol: symbol, buffer: buffer})
+			}
+		}
+		acks = append(acks, closedAcks...)
+	}
+	s.mu.Unlock()
 
-	for symbol, buffer := range s.buffers {
-		// 只處理上一秒的數據（確保該秒已收集完整）
-		if buffer.Timestamp < currentSecond-1 {
-			// 該緩衝區太舊，直接刪除
-			delete(s.buffers, symbol)
-			continue
+	var audits []*model.PriceAudit
+	for _, window := range closed {
+		if audit := s.emit(window.symbol, window.buffer, handler); audit != nil {
+			audits = append(audits, audit)
 		}
+	}
 
-		if buffer.Timestamp == currentSecond-1 {
-			// 處理上一秒的完整數據
-			if len(buffer.Prices) > 0 {
-				var selectedPrice *model.Price
-
-				// 根據策略選擇價格
-				if s.cfg.PriceStrategy == "best" {
-					selectedPrice = buffer.GetBestPrice()
-				} else {
-					selectedPrice = buffer.GetWorstPrice()
-				}
-
-				if selectedPrice != nil {
-					log.Printf("💰 [%s] Selected %s price: %.2f (from %d prices)",
-						symbol, s.cfg.PriceStrategy, selectedPrice.Price, len(buffer.Prices))
-
-					// 調用處理器
-					log.Printf("🔄 Calling handler for %s", symbol)
-					handler(selectedPrice)
-					log.Printf("✅ Handler called for %s", symbol)
-				}
-			}
+	for _, ack := range acks {
+		ack()
+	}
 
-			// 刪除已處理的緩衝區
-			delete(s.buffers, symbol)
+	if s.auditor != nil {
+		for _, audit := range audits {
+			if err := s.auditor.Record(audit); err != nil {
+				log.Printf("❌ Failed to record price audit: %v", err)
+			}
 		}
-		// 如果 buffer.Timestamp == currentSecond，則保留（還在收集中）
 	}
 }
 
-// GetCurrentBuffer 獲取當前緩衝區狀態（調試用）
+// emit 以所有策略選價並推送一個窗口，用戶各自套用自己的策略；商品設定的策略為預設
+// 返回該窗口的審計記錄（未設置審計記錄器時返回 nil），由呼叫端寫入
+func (s *Subscriber) emit(symbol string, buffer *model.PriceBuffer, handler PriceHandler) *model.PriceAudit {
+	st := s.strategyFor(symbol)
+	selection := &model.PriceSelection{
+		Symbol:      symbol,
+		Strategy:    st.Name(),
+		Prices:      s.registry.SelectAll(buffer),
+		WindowStart: buffer.Timestamp,
+		Window:      buffer.Window,
+	}
+
+	selectedPrice := selection.Default()
+	if selectedPrice == nil {
+		return nil
+	}
+
+	log.Printf("💰 [%s] Selected %s price: %.2f (from %d prices)",
+		symbol, st.Name(), selectedPrice.Price, len(buffer.Prices))
+
+	handler(selection)
+
+	if s.auditor == nil {
+		return nil
+	}
+
+	// 處理回調完成推送前的價格檢查後才產生審計記錄，保留該窗口所有候選價格、選中的價格和檢查結果
+	return &model.PriceAudit{
+		Symbol:      symbol,
+		Second:      buffer.Timestamp / 1000,
+		WindowStart: buffer.Timestamp,
+		Window:      buffer.Window,
+		Strategy:    st.Name(),
+		Candidates:  buffer.Prices,
+		Selected:    *selectedPrice,
+		Selections:  auditSelections(selection),
+		Verdict:     selection.Verdict,
+		Reason:      selection.VerdictReason,
+		Rejected:    selection.Rejected,
+		RecordedAt:  time.Now().UnixMilli(),
+	}
+}
+
+// auditSelections 審計記錄中每個策略選出的價格
+func auditSelections(selection *model.PriceSelection) map[string]model.Price {
+	selections := make(map[string]model.Price, len(selection.Prices))
+	for name, price := range selection.Prices {
+		selections[name] = *price
+	}
+	return selections
+}
+
+// strategyFor 商品的選價策略
+func (s *Subscriber) strategyFor(symbol string) strategy.SelectionStrategy {
+	if st, ok := s.strategies[symbol]; ok {
+		return st
+	}
+	return s.fallback
+}
+
+// WindowFor 商品的聚合窗口長度
+func (s *Subscriber) WindowFor(symbol string) time.Duration {
+	if window, ok := s.symWindows[symbol]; ok {
+		return window
+	}
+	return s.window
+}
+
+// flushInterval 依最短的聚合窗口決定檢查 watermark 的間隔
+func flushInterval(cfg *config.Config) time.Duration {
+	shortest := cfg.AggregationWindow
+	for _, window := range cfg.SymbolWindows {
+		shortest = min(shortest, window)
+	}
+	return shortest / flushDivisor
+}
+
+// SetFeedRecorder 設置價格來源狀態記錄器，需在 Start 之前調用
+func (s *Subscriber) SetFeedRecorder(feed FeedRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.feed = feed
+}
+
+// SetAuditRecorder 設置每秒價格審計記錄器，需在 Start 之前調用
+func (s *Subscriber) SetAuditRecorder(auditor AuditRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.auditor = auditor
+}
+
+// GetCurrentBuffer 獲取最新仍在收集中的窗口（調試用）
 func (s *Subscriber) GetCurrentBuffer(symbol string) *model.PriceBuffer {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if buffer, exists := s.buffers[symbol]; exists {
+	windows, exists := s.windows[symbol]
+	if !exists {
+		return nil
+	}
+
+	if buffer := windows.latest(); buffer != nil {
 		// 返回副本避免併發問題
 		bufferCopy := &model.PriceBuffer{
 			Symbol:    buffer.Symbol,
 			Timestamp: buffer.Timestamp,
+			Window:    buffer.Window,
 			Prices:    make([]model.Price, len(buffer.Prices)),
 		}
 		copy(bufferCopy.Prices, buffer.Prices)
@@ -237,9 +347,43 @@ func (s *Subscriber) GetCurrentBuffer(symbol string) *model.PriceBuffer {
 	return nil
 }
 
+// Stats 各商品的事件時間聚合狀態（watermark、遲到 tick 數等），依商品排序
+func (s *Subscriber) Stats() []model.AggregationStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	stats := make([]model.AggregationStats, 0, len(s.windows))
+	for symbol, windows := range s.windows {
+		stats = append(stats, windows.stats(symbol, now, s.lateness))
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Symbol < stats[j].Symbol
+	})
+
+	return stats
+}
+
+// Follow 商品開始有客戶端關注，訊息匯流排支援時按需訂閱該商品
+func (s *Subscriber) Follow(symbol string) error {
+	if follower, ok := s.source.(bus.SymbolFollower); ok {
+		return follower.Follow(symbol)
+	}
+	return nil
+}
+
+// Unfollow 商品已沒有客戶端關注，訊息匯流排支援時取消訂閱該商品
+func (s *Subscriber) Unfollow(symbol string) error {
+	if follower, ok := s.source.(bus.SymbolFollower); ok {
+		return follower.Unfollow(symbol)
+	}
+	return nil
+}
+
 // Stop 停止訂閱器
 func (s *Subscriber) Stop() error {
-	log.Println("🛑 Stopping Redis subscriber...")
+	log.Println("🛑 Stopping price subscriber...")
 
 	// 停止定時器
 	if s.ticker != nil {
@@ -254,18 +398,18 @@ func (s *Subscriber) Stop() error {
 	// 等待 goroutines 結束
 	s.wg.Wait()
 
-	// 關閉 Redis 連接
-	if s.client != nil {
-		if err := s.client.Close(); err != nil {
-			return fmt.Errorf("failed to close redis client: %w", err)
+	// 關閉訊息匯流排連接
+	if s.source != nil {
+		if err := s.source.Close(); err != nil {
+			return fmt.Errorf("failed to close message bus: %w", err)
 		}
 	}
 
-	log.Println("✅ Redis subscriber stopped")
+	log.Println("✅ Price subscriber stopped")
 	return nil
 }
 
-// Ping 檢查 Redis 連接是否正常
+// Ping 檢查訊息匯流排連接是否正常
 func (s *Subscriber) Ping(ctx context.Context) error {
-	return s.client.Ping(ctx).Err()
+	return s.source.Ping(ctx)
 }
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+	"github.com/mike/golden-buy/platform/internal/strategy"
+)
+
+// blockingAuditor 在 release 關閉前阻塞寫入，模擬 Redis 中斷時的審計記錄
+type blockingAuditor struct {
+	entered chan struct{}
+	release chan struct{}
+	records []*model.PriceAudit
+}
+
+func (a *blockingAuditor) Record(record *model.PriceAudit) error {
+	a.entered <- struct{}{}
+	<-a.release
+	a.records = append(a.records, record)
+	return nil
+}
+
+func TestFlushBuffersRecordsAuditOutsideLock(t *testing.T) {
+	registry := strategy.NewRegistry(0)
+	fallback, err := registry.Get(strategy.Last)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auditor := &blockingAuditor{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	s := &Subscriber{
+		windows:  make(map[string]*symbolWindows),
+		window:   time.Second,
+		registry: registry,
+		fallback: fallback,
+		auditor:  auditor,
+	}
+
+	// 較新的 tick 讓 watermark 越過第一個窗口
+	acked := make(chan struct{}, 1)
+	s.addToBuffer(&model.Price{Symbol: "GOLD", Price: 100, Timestamp: 10200}, func() { acked <- struct{}{} })
+	s.addToBuffer(&model.Price{Symbol: "GOLD", Price: 101, Timestamp: 12500}, nil)
+
+	var emitted []*model.PriceSelection
+	done := make(chan struct{})
+	go func() {
+		s.flushBuffers(func(selection *model.PriceSelection) {
+			emitted = append(emitted, selection)
+		})
+		close(done)
+	}()
+
+	select {
+	case <-auditor.entered:
+	case <-time.After(time.Second):
+		t.Fatal("audit record was not written")
+	}
+
+	// 審計寫入阻塞時仍可確認訊息和接收價格
+	select {
+	case <-acked:
+	case <-time.After(time.Second):
+		t.Fatal("window was not acked before the audit write finished")
+	}
+
+	added := make(chan struct{})
+	go func() {
+		s.addToBuffer(&model.Price{Symbol: "GOLD", Price: 102, Timestamp: 12600}, nil)
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Fatal("addToBuffer blocked while the audit write was in progress")
+	}
+
+	close(auditor.release)
+	<-done
+
+	if len(emitted) != 1 || emitted[0].WindowStart != 10000 {
+		t.Fatalf("emitted = %+v, want the window at 10000", emitted)
+	}
+	if len(auditor.records) != 1 || auditor.records[0].Selected.Price != 100 {
+		t.Errorf("audit records = %+v, want one record selecting 100", auditor.records)
+	}
+}
@@ -0,0 +1,146 @@
+package redis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/bus"
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// addResult tick 加入窗口的結果
+type addResult int
+
+const (
+	tickAdded     addResult = iota
+	tickLate                // 所屬窗口或之後的窗口已推送
+	tickDuplicate           // 同一筆 tick 已從另一個來源收到（切換價格來源時重疊）
+)
+
+// symbolWindows 單一商品的事件時間窗口，窗口從 Unix epoch 起以固定長度對齊
+//
+// watermark = 最新事件時間 + 收到後經過的本機時間 - 允許遲到時間。
+// 經過時間用本機時鐘量測，不比較兩個容器的時鐘，所以時鐘偏差不會讓窗口提早關閉；
+// 沒有新 tick 時 watermark 仍會前進，最後一個窗口不會卡住。
+type symbolWindows struct {
+	size          int64                        // 窗口長度（毫秒）
+	buffers       map[int64]*model.PriceBuffer // 窗口開始時間（Unix 毫秒）-> 緩衝區
+	acks          map[int64][]bus.Ack          // 窗口開始時間 -> 窗口推送後才確認的訊息
+	maxEventTime  int64                        // 已收到的最新事件時間（Unix 毫秒）
+	maxArrival    time.Time                    // 收到 maxEventTime 那筆 tick 的本機時間
+	closedUntil   int64                        // 最後推送窗口的結束時間（Unix 毫秒），早於此的 tick 為遲到
+	closedWindows int64
+	lateTicks     int64
+	lastLateTick  int64
+}
+
+func newSymbolWindows(window time.Duration) *symbolWindows {
+	return &symbolWindows{
+		size:    window.Milliseconds(),
+		buffers: make(map[int64]*model.PriceBuffer),
+		acks:    make(map[int64][]bus.Ack),
+	}
+}
+
+// windowStart tick 所屬窗口的開始時間（Unix 毫秒）
+func (w *symbolWindows) windowStart(timestamp int64) int64 {
+	return timestamp - timestamp%w.size
+}
+
+// add 將 tick 放入所屬窗口，tickAdded 時 ack（不為 nil）保留到窗口推送後由 closeUpTo 返回
+// 沒有收到 tick 而關閉的窗口沒有推送過，之後補到的 tick 仍會被接受並依時間順序推送，例如切換價格來源後補齊的 tick
+func (w *symbolWindows) add(price *model.Price, ack bus.Ack, now time.Time) addResult {
+	start := w.windowStart(price.Timestamp)
+
+	if start < w.closedUntil {
+		w.lateTicks++
+		w.lastLateTick = price.Timestamp
+		return tickLate
+	}
+
+	if price.Timestamp > w.maxEventTime {
+		w.maxEventTime = price.Timestamp
+		w.maxArrival = now
+	}
+
+	buffer, exists := w.buffers[start]
+	if !exists {
+		buffer = &model.PriceBuffer{
+			Symbol:    price.Symbol,
+			Timestamp: start,
+			Window:    w.size,
+			Prices:    make([]model.Price, 0, 3), // 預分配空間給 3 筆價格
+		}
+		w.buffers[start] = buffer
+	}
+
+	for _, p := range buffer.Prices {
+		if p.Timestamp == price.Timestamp && p.Price == price.Price {
+			return tickDuplicate
+		}
+	}
+	buffer.Prices = append(buffer.Prices, *price)
+	if ack != nil {
+		w.acks[start] = append(w.acks[start], ack)
+	}
+
+	return tickAdded
+}
+
+// watermark 目前的 watermark（Unix 毫秒），尚未收到 tick 時為 0
+func (w *symbolWindows) watermark(now time.Time, lateness time.Duration) int64 {
+	if w.maxEventTime == 0 {
+		return 0
+	}
+	return w.maxEventTime + now.Sub(w.maxArrival).Milliseconds() - lateness.Milliseconds()
+}
+
+// closeUpTo 依時間順序取出結束時間不晚於 watermark 的窗口並標記為已關閉
+// 同時返回這些窗口的 ack，呼叫端在窗口推送後調用
+func (w *symbolWindows) closeUpTo(watermark int64) ([]*model.PriceBuffer, []bus.Ack) {
+	var closed []*model.PriceBuffer
+	var acks []bus.Ack
+	for start, buffer := range w.buffers {
+		if start+w.size <= watermark {
+			closed = append(closed, buffer)
+			acks = append(acks, w.acks[start]...)
+			delete(w.buffers, start)
+			delete(w.acks, start)
+		}
+	}
+
+	sort.Slice(closed, func(i, j int) bool {
+		return closed[i].Timestamp < closed[j].Timestamp
+	})
+
+	if n := len(closed); n > 0 {
+		w.closedUntil = closed[n-1].End()
+		w.closedWindows += int64(n)
+	}
+
+	return closed, acks
+}
+
+// latest 最新仍在收集中的窗口
+func (w *symbolWindows) latest() *model.PriceBuffer {
+	var latest *model.PriceBuffer
+	for _, buffer := range w.buffers {
+		if latest == nil || buffer.Timestamp > latest.Timestamp {
+			latest = buffer
+		}
+	}
+	return latest
+}
+
+// stats 聚合狀態
+func (w *symbolWindows) stats(symbol string, now time.Time, lateness time.Duration) model.AggregationStats {
+	return model.AggregationStats{
+		Symbol:        symbol,
+		Watermark:     w.watermark(now, lateness),
+		MaxEventTime:  w.maxEventTime,
+		OpenWindows:   len(w.buffers),
+		ClosedWindows: w.closedWindows,
+		LateTicks:     w.lateTicks,
+		LastLateTick:  w.lastLateTick,
+	}
+}
@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+func TestSymbolWindows(t *testing.T) {
+	const lateness = 500 * time.Millisecond
+
+	// 每一步在本機時間 at 加入一筆 tick，或以當時的 watermark 關閉窗口
+	type step struct {
+		at         time.Duration
+		tick       int64   // tick 事件時間（Unix 毫秒），0 表示關閉窗口
+		price      float64 // tick 價格，0 時使用 100
+		want       addResult
+		wantClosed []int64 // 關閉的窗口開始時間
+		wantAcks   int     // 關閉時返回的 ack 數量
+	}
+
+	tests := []struct {
+		name      string
+		steps     []step
+		wantLate  int64
+		wantOpen  int
+		wantCount int64 // 已關閉的窗口數
+	}{
+		{
+			name: "window closes once local time passes end plus lateness",
+			steps: []step{
+				{at: 0, tick: 10200, want: tickAdded},
+				{at: time.Second},
+				{at: 1300 * time.Millisecond, wantClosed: []int64{10000}, wantAcks: 1},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "out of order ticks close in window order",
+			steps: []step{
+				{at: 0, tick: 12100, want: tickAdded},
+				{at: 0, tick: 10100, want: tickAdded},
+				{at: 0, tick: 11100, want: tickAdded},
+				{at: 1400 * time.Millisecond, wantClosed: []int64{10000, 11000, 12000}, wantAcks: 3},
+			},
+			wantCount: 3,
+		},
+		{
+			name: "tick for a closed window is late",
+			steps: []step{
+				{at: 0, tick: 10200, want: tickAdded},
+				{at: 1300 * time.Millisecond, wantClosed: []int64{10000}, wantAcks: 1},
+				{at: 1300 * time.Millisecond, tick: 10900, want: tickLate},
+			},
+			wantLate:  1,
+			wantCount: 1,
+		},
+		{
+			name: "duplicate tick is not buffered or acked twice",
+			steps: []step{
+				{at: 0, tick: 10200, want: tickAdded},
+				{at: 0, tick: 10200, want: tickDuplicate},
+				{at: 0, tick: 10200, price: 101, want: tickAdded},
+				{at: 1300 * time.Millisecond, wantClosed: []int64{10000}, wantAcks: 2},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "window without ticks still accepts backfill",
+			steps: []step{
+				{at: 0, tick: 10200, want: tickAdded},
+				{at: 0, tick: 12500, want: tickAdded},
+				{at: 0, wantClosed: []int64{10000}, wantAcks: 1},
+				{at: 0, tick: 11500, want: tickAdded},
+				{at: time.Second, wantClosed: []int64{11000, 12000}, wantAcks: 2},
+			},
+			wantCount: 3,
+		},
+		{
+			name: "newer ticks keep later windows open",
+			steps: []step{
+				{at: 0, tick: 10200, want: tickAdded},
+				{at: 0, tick: 10800, want: tickAdded},
+				{at: 600 * time.Millisecond, tick: 11900, want: tickAdded},
+				{at: 600 * time.Millisecond, wantClosed: []int64{10000}, wantAcks: 2},
+			},
+			wantOpen:  1,
+			wantCount: 1,
+		},
+	}
+
+	base := time.Unix(1700000000, 0)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newSymbolWindows(time.Second)
+
+			for i, s := range tt.steps {
+				now := base.Add(s.at)
+
+				if s.tick != 0 {
+					price := s.price
+					if price == 0 {
+						price = 100
+					}
+					acked := false
+					got := w.add(&model.Price{Symbol: "GOLD", Price: price, Timestamp: s.tick}, func() { acked = true }, now)
+					if got != s.want {
+						t.Fatalf("step %d: add() = %d, want %d", i, got, s.want)
+					}
+					if acked {
+						t.Fatalf("step %d: add() acked before the window closed", i)
+					}
+					continue
+				}
+
+				closed, acks := w.closeUpTo(w.watermark(now, lateness))
+				starts := make([]int64, 0, len(closed))
+				for _, buffer := range closed {
+					starts = append(starts, buffer.Timestamp)
+				}
+				if len(starts) == 0 {
+					starts = nil
+				}
+				if !reflect.DeepEqual(starts, s.wantClosed) {
+					t.Fatalf("step %d: closed windows = %v, want %v", i, starts, s.wantClosed)
+				}
+				if len(acks) != s.wantAcks {
+					t.Fatalf("step %d: %d acks, want %d", i, len(acks), s.wantAcks)
+				}
+			}
+
+			stats := w.stats("GOLD", base, lateness)
+			if stats.LateTicks != tt.wantLate || stats.OpenWindows != tt.wantOpen || stats.ClosedWindows != tt.wantCount {
+				t.Errorf("stats = late %d open %d closed %d, want late %d open %d closed %d",
+					stats.LateTicks, stats.OpenWindows, stats.ClosedWindows, tt.wantLate, tt.wantOpen, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestSymbolWindowsWatermark(t *testing.T) {
+	w := newSymbolWindows(time.Second)
+	base := time.Unix(1700000000, 0)
+
+	if got := w.watermark(base, 0); got != 0 {
+		t.Fatalf("watermark() before any tick = %d, want 0", got)
+	}
+
+	w.add(&model.Price{Symbol: "GOLD", Price: 100, Timestamp: 10000}, nil, base)
+	// 較舊的 tick 不會讓 watermark 後退
+	w.add(&model.Price{Symbol: "GOLD", Price: 100, Timestamp: 9000}, nil, base.Add(time.Second))
+
+	tests := []struct {
+		at   time.Duration
+		want int64
+	}{
+		{at: 0, want: 9500},
+		{at: 250 * time.Millisecond, want: 9750},
+		{at: 2 * time.Second, want: 11500},
+	}
+
+	for _, tt := range tests {
+		if got := w.watermark(base.Add(tt.at), 500*time.Millisecond); got != tt.want {
+			t.Errorf("watermark(+%s) = %d, want %d", tt.at, got, tt.want)
+		}
+	}
+}
@@ -5,22 +5,51 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
+	"github.com/mike/golden-buy/platform/internal/audit"
+	"github.com/mike/golden-buy/platform/internal/auth"
+	"github.com/mike/golden-buy/platform/internal/candle"
 	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/feed"
 	"github.com/mike/golden-buy/platform/internal/grpc"
+	"github.com/mike/golden-buy/platform/internal/guard"
+	"github.com/mike/golden-buy/platform/internal/klinecache"
 	"github.com/mike/golden-buy/platform/internal/model"
 	"github.com/mike/golden-buy/platform/internal/redis"
 	"github.com/mike/golden-buy/platform/internal/user"
 	"github.com/mike/golden-buy/platform/internal/websocket"
 )
 
+// feedCheckInterval 檢查商品價格來源是否中斷的間隔
+const feedCheckInterval = 500 * time.Millisecond
+
+// haltCheckInterval 檢查暫停商品是否到達自動恢復時間的間隔
+const haltCheckInterval = time.Second
+
+// symbolActivityBuffer 等待處理的商品關注狀態變化數量，超過時 Hub 等待處理完成
+const symbolActivityBuffer = 256
+
+// symbolActivity 商品關注狀態變化
+type symbolActivity struct {
+	symbol string
+	active bool
+}
+
 // PlatformService 平台服務
 type PlatformService struct {
 	cfg          *config.Config
 	grpcClient   *grpc.PriceClient
 	subscriber   *redis.Subscriber
+	auditStore   *audit.Store // AUDIT_ENABLED=false 時為 nil
+	feed         *feed.Monitor
+	guard        *guard.Guard      // PRICE_VALIDATION_ENABLED=false 時為 nil
+	klines       *klinecache.Cache // KLINE_CACHE_ENABLED=false 時為 nil
+	candles      *candle.Store     // 由推送的價格組成的滾動 K 線，併入歷史查詢結果
 	wsHub        *websocket.Hub
 	userManager  *user.Manager
+	verifier     *auth.Verifier      // AUTH_TOKEN_SECRET 未設定時為 nil
+	activity     chan symbolActivity // 商品關注狀態變化，由獨立的 goroutine 依序處理
 	mu           sync.RWMutex
 	latestPrices map[string]*model.Price // 存儲每個商品的最新處理價格
 	ctx          context.Context
@@ -36,27 +65,46 @@ func New(cfg *config.Config) (*PlatformService, error) {
 		return nil, fmt.Errorf("failed to create grpc client: %w", err)
 	}
 
-	// 測試 gRPC 連接
+	// 測試 gRPC 連接，Price Service 尚未啟動時仍繼續啟動，之後的請求會自動連線
 	ctx := context.Background()
 	if err := grpcClient.Ping(ctx); err != nil {
-		grpcClient.Close()
-		return nil, fmt.Errorf("grpc ping failed: %w", err)
+		log.Printf("⚠️  Price Service at %s not reachable yet: %v", cfg.PriceServiceAddr, err)
+	} else {
+		log.Printf("✅ Connected to Price Service at %s", cfg.PriceServiceAddr)
 	}
-	log.Printf("✅ Connected to Price Service at %s", cfg.PriceServiceAddr)
 
-	// 創建 Redis 訂閱器
+	// 創建價格訂閱器（依 MESSAGE_BUS 連接訊息匯流排）
 	subscriber, err := redis.NewSubscriber(cfg)
 	if err != nil {
 		grpcClient.Close()
-		return nil, fmt.Errorf("failed to create redis subscriber: %w", err)
+		return nil, fmt.Errorf("failed to create price subscriber: %w", err)
 	}
 
-	// 測試 Redis 連接
+	// 測試訊息匯流排連接
 	if err := subscriber.Ping(ctx); err != nil {
 		grpcClient.Close()
-		return nil, fmt.Errorf("redis ping failed: %w", err)
+		subscriber.Stop()
+		return nil, fmt.Errorf("message bus ping failed: %w", err)
+	}
+	log.Printf("✅ Connected to message bus: %s", cfg.MessageBus)
+
+	// 訊息匯流排中斷期間改從 Price Service gRPC 串流接收價格
+	if cfg.GRPCFailover {
+		subscriber.SetFailoverSource(grpcClient)
+	}
+
+	// 創建價格審計存儲
+	var auditStore *audit.Store
+	if cfg.AuditEnabled {
+		auditStore, err = audit.NewStore(cfg)
+		if err != nil {
+			grpcClient.Close()
+			subscriber.Stop()
+			return nil, fmt.Errorf("failed to create audit store: %w", err)
+		}
+		subscriber.SetAuditRecorder(auditStore)
+		log.Printf("✅ Price audit enabled (retention: %s)", cfg.AuditRetention)
 	}
-	log.Printf("✅ Connected to Redis at %s", cfg.RedisAddr)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -66,16 +114,53 @@ func New(cfg *config.Config) (*PlatformService, error) {
 	// 創建用戶管理器
 	userManager := user.NewManager()
 
-	return &PlatformService{
+	s := &PlatformService{
 		cfg:          cfg,
 		grpcClient:   grpcClient,
 		subscriber:   subscriber,
+		auditStore:   auditStore,
 		wsHub:        wsHub,
 		userManager:  userManager,
 		latestPrices: make(map[string]*model.Price),
+		candles:      candle.NewStore(),
+		activity:     make(chan symbolActivity, symbolActivityBuffer),
 		ctx:          ctx,
 		cancel:       cancel,
-	}, nil
+	}
+
+	// 追蹤每個商品最後收到價格或心跳的時間，狀態變化時通知 WebSocket 客戶端
+	s.feed = feed.NewMonitor(cfg.StaleThreshold, s.handleFeedStatus)
+	subscriber.SetFeedRecorder(s.feed)
+	wsHub.SetFeedStatusProvider(s.feed.Status)
+
+	// 推送前檢查價格，商品暫停或恢復時通知 WebSocket 客戶端
+	if cfg.ValidationEnabled {
+		s.guard = guard.New(cfg, s.handleHaltStatus)
+		wsHub.SetHaltStatusProvider(s.guard.Status)
+		log.Printf("✅ Price validation enabled (max change: %.2f%%, max sigma: %.1f, halt window: %s)",
+			cfg.ValidationMaxChange, cfg.ValidationMaxSigma, cfg.HaltWindow)
+	}
+
+	// 緩存 K 線查詢結果，圖表載入和切換週期不必每次查詢 InfluxDB
+	if cfg.KlineCacheEnabled {
+		s.klines = klinecache.New(cfg, s.loadKlines)
+		log.Printf("✅ Kline cache enabled (size: %d, open candle TTL: %s)", cfg.KlineCacheSize, cfg.KlineCacheOpenTTL)
+	}
+
+	// 依 WebSocket 客戶端關注的商品按需訂閱價格頻道
+	wsHub.SetSymbolListener(s.handleSymbolActivity)
+
+	// WebSocket 連線以登入服務簽發的 token 識別用戶
+	if cfg.AuthTokenSecret != "" {
+		s.verifier = auth.NewVerifier(cfg)
+	} else {
+		log.Printf("⚠️  AUTH_TOKEN_SECRET not set, WebSocket connections are anonymous")
+	}
+
+	// WebSocket 客戶端依連線的用戶套用選價策略
+	wsHub.SetStrategyResolver(s.StrategyForUser)
+
+	return s, nil
 }
 
 // Start 啟動服務
@@ -90,14 +175,37 @@ func (s *PlatformService) Start() error {
 	}()
 	log.Println("✅ WebSocket Hub started")
 
-	// 啟動 Redis 訂閱器
+	// 啟動價格來源狀態檢查
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.feed.Run(s.ctx, feedCheckInterval)
+	}()
+
+	// 啟動商品關注狀態的處理（按需訂閱需要網路請求，不在 Hub 的 goroutine 中進行）
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runSymbolActivity()
+	}()
+
+	// 啟動暫停商品的自動恢復檢查
+	if s.guard != nil {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.guard.Run(s.ctx, haltCheckInterval)
+		}()
+	}
+
+	// 啟動價格訂閱器
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
 
 		err := s.subscriber.Start(s.handlePriceUpdate)
 		if err != nil && err != context.Canceled {
-			log.Printf("❌ Redis subscriber error: %v", err)
+			log.Printf("❌ Price subscriber error: %v", err)
 		}
 	}()
 
@@ -106,21 +214,153 @@ func (s *PlatformService) Start() error {
 }
 
 // handlePriceUpdate 處理價格更新（來自 Redis 訂閱器）
-func (s *PlatformService) handlePriceUpdate(price *model.Price) {
+// 最新價格保存商品預設策略的價格，WebSocket 客戶端收到各自用戶策略的價格
+// 未通過價格檢查或商品暫停中的窗口不更新最新價格也不推送
+func (s *PlatformService) handlePriceUpdate(selection *model.PriceSelection) {
+	price := selection.Default()
 	log.Printf("🔄 handlePriceUpdate called: %s = %.2f", price.Symbol, price.Price)
 
+	// 檢查結果由訂閱器寫入該窗口的審計記錄
+	selection.Verdict = model.VerdictAccepted
+	if s.guard != nil {
+		switch verdict, reason := s.guard.Check(price); verdict {
+		case guard.Rejected:
+			log.Printf("🚫 [%s] Price %.2f rejected: %s", price.Symbol, price.Price, reason)
+			selection.Verdict, selection.VerdictReason = model.VerdictRejected, reason
+			return
+		case guard.Halted:
+			log.Printf("⛔ [%s] Price %.2f dropped, symbol halted: %s", price.Symbol, price.Price, reason)
+			selection.Verdict, selection.VerdictReason = model.VerdictHalted, reason
+			return
+		}
+
+		// 其他策略的價格同樣需要通過檢查，未通過時這些策略的客戶端改收預設策略的價格
+		for name, p := range selection.Prices {
+			if name == selection.Strategy || p == nil {
+				continue
+			}
+			if reason := s.guard.Validate(p); reason != "" {
+				log.Printf("🚫 [%s] %s price %.2f rejected: %s", p.Symbol, name, p.Price, reason)
+				selection.Reject(name, reason)
+			}
+		}
+	}
+
 	s.mu.Lock()
 	s.latestPrices[price.Symbol] = price
 	s.mu.Unlock()
 
+	// K 線與推送的價格一致：同樣使用預設策略的價格，並依窗口開始時間分桶
+	s.candles.Update(price, selection.WindowStart)
+
+	// 窗口結束後超過門檻時間才推送（例如切換價格來源後補齊的窗口）
+	windowEnd := selection.WindowStart + selection.Window
+	selection.Stale = time.Now().UnixMilli()-windowEnd > s.cfg.StaleThreshold.Milliseconds()
+
 	// 推送到 WebSocket 客戶端
 	log.Printf("📡 Calling BroadcastPrice for %s", price.Symbol)
-	s.wsHub.BroadcastPrice(price)
+	s.wsHub.BroadcastSelection(selection)
 
 	log.Printf("📊 Latest price updated: %s = %.2f (change: %.2f%%)",
 		price.Symbol, price.Price, price.ChangePercent)
 }
 
+// handleFeedStatus 商品價格來源狀態變化時通知訂閱該商品的 WebSocket 客戶端
+func (s *PlatformService) handleFeedStatus(status model.FeedStatus) {
+	if status.Status == model.FeedStale {
+		log.Printf("⚠️  [%s] Price feed stale: no price or heartbeat for %dms", status.Symbol, status.Age)
+	} else {
+		log.Printf("✅ [%s] Price feed live again", status.Symbol)
+	}
+
+	s.wsHub.BroadcastFeedStatus(status)
+}
+
+// handleHaltStatus 商品暫停或恢復時通知訂閱該商品的 WebSocket 客戶端
+func (s *PlatformService) handleHaltStatus(status model.HaltStatus) {
+	if status.Halted {
+		log.Printf("⛔ [%s] Symbol halted: %s", status.Symbol, status.Reason)
+	} else {
+		log.Printf("✅ [%s] Symbol resumed", status.Symbol)
+	}
+
+	s.wsHub.BroadcastHaltStatus(status)
+}
+
+// handleSymbolActivity 商品關注狀態變化時交給 runSymbolActivity 依序處理
+// 在 Hub 的 goroutine 中調用，只放入緩衝 channel，不等待訂閱完成
+func (s *PlatformService) handleSymbolActivity(symbol string, active bool) {
+	select {
+	case s.activity <- symbolActivity{symbol: symbol, active: active}:
+	case <-s.ctx.Done():
+	}
+}
+
+// runSymbolActivity 依收到的順序處理商品關注狀態變化，直到服務停止
+func (s *PlatformService) runSymbolActivity() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case a := <-s.activity:
+			s.applySymbolActivity(a.symbol, a.active)
+		}
+	}
+}
+
+// applySymbolActivity 通知訂閱器商品關注狀態變化
+// on_demand 模式下取消訂閱的商品不再收到價格和心跳，清除其最新價格讓 HTTP 查詢改從 Price Service 獲取，
+// 並停止監控其價格來源；重新關注時從訂閱時間起算 stale 門檻
+func (s *PlatformService) applySymbolActivity(symbol string, active bool) {
+	onDemand := s.cfg.RedisSubscribeMode == "on_demand"
+
+	if active {
+		if onDemand {
+			s.feed.Watch(symbol, time.Now())
+		}
+		if err := s.subscriber.Follow(symbol); err != nil {
+			log.Printf("❌ Failed to follow %s: %v", symbol, err)
+		}
+		return
+	}
+
+	if err := s.subscriber.Unfollow(symbol); err != nil {
+		log.Printf("❌ Failed to unfollow %s: %v", symbol, err)
+	}
+
+	if onDemand {
+		s.mu.Lock()
+		delete(s.latestPrices, symbol)
+		s.mu.Unlock()
+		s.candles.Remove(symbol)
+		s.feed.Forget(symbol)
+	}
+}
+
+// AuthenticateToken 驗證 token 並返回已認證的用戶 ID
+func (s *PlatformService) AuthenticateToken(token string) (string, error) {
+	if s.verifier == nil {
+		return "", auth.ErrDisabled
+	}
+	return s.verifier.Verify(token, time.Now())
+}
+
+// StrategyForUser 用戶套用的選價策略：個別設定優先，其次為角色設定
+// 返回空字串表示使用商品的預設策略
+func (s *PlatformService) StrategyForUser(userID string) string {
+	if name, ok := s.cfg.UserStrategies[userID]; ok {
+		return name
+	}
+
+	if u, ok := s.userManager.GetUser(userID); ok {
+		if name, ok := s.cfg.RoleStrategies[u.Role]; ok {
+			return name
+		}
+	}
+
+	return ""
+}
+
 // GetLatestPrice 獲取最新處理過的價格
 func (s *PlatformService) GetLatestPrice(symbol string) (*model.Price, error) {
 	s.mu.RLock()
@@ -158,15 +398,139 @@ func (s *PlatformService) GetCurrentPricesFromService(ctx context.Context, symbo
 	return s.grpcClient.GetCurrentPrices(ctx, symbols)
 }
 
-// GetKlines 獲取 K 線資料（用於圖表）
-func (s *PlatformService) GetKlines(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int32) ([]*model.Kline, error) {
-	klines, err := s.grpcClient.GetKlines(ctx, symbol, interval, startTime, endTime, limit)
+// GetKlines 獲取 K 線資料（用於圖表），啟用緩存時優先從緩存獲取
+// 未收盤和剛收盤的 K 線以推送的價格組成，併入歷史資料的最新一端
+// 返回的結果可能由多個請求共用，不可修改
+func (s *PlatformService) GetKlines(ctx context.Context, query model.KlineQuery) (*model.KlinePage, error) {
+	var page *model.KlinePage
+	var err error
+	if s.klines != nil {
+		page, err = s.klines.Get(ctx, query)
+	} else {
+		page, err = s.loadKlines(ctx, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.candles.Merge(query, page), nil
+}
+
+// GetKlineCacheStats 獲取 K 線緩存的命中統計，未啟用緩存時 ok 為 false
+func (s *PlatformService) GetKlineCacheStats() (model.KlineCacheStats, bool) {
+	if s.klines == nil {
+		return model.KlineCacheStats{}, false
+	}
+	return s.klines.Stats(), true
+}
+
+// loadKlines 從 Price Service 獲取 K 線資料
+func (s *PlatformService) loadKlines(ctx context.Context, query model.KlineQuery) (*model.KlinePage, error) {
+	page, err := s.grpcClient.GetKlines(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get klines: %w", err)
 	}
 
-	log.Printf("📈 Retrieved %d klines for %s (%s interval)", len(klines), symbol, interval)
-	return klines, nil
+	log.Printf("📈 Retrieved %d/%d klines for %s (%s interval)", len(page.Klines), page.Total, query.Symbol, query.Interval)
+	return page, nil
+}
+
+// GetStatistics 獲取市場統計
+func (s *PlatformService) GetStatistics(ctx context.Context, query model.StatisticsQuery) (*model.Statistics, error) {
+	stats, err := s.grpcClient.GetStatistics(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetIndicators 獲取技術指標（用於圖表疊加）
+func (s *PlatformService) GetIndicators(ctx context.Context, query model.IndicatorQuery) (*model.IndicatorSet, error) {
+	set, err := s.grpcClient.GetIndicators(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indicators: %w", err)
+	}
+
+	return set, nil
+}
+
+// GetTicks 分頁獲取原始 tick（用於價格爭議查詢）
+func (s *PlatformService) GetTicks(ctx context.Context, query model.TickQuery) (*model.TickPage, error) {
+	page, err := s.grpcClient.GetTicks(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticks: %w", err)
+	}
+
+	return page, nil
+}
+
+// GetPriceAudit 獲取指定商品在某個時間點（Unix 毫秒）所屬聚合窗口的價格審計記錄
+func (s *PlatformService) GetPriceAudit(ctx context.Context, symbol string, timestamp int64) (*model.PriceAudit, error) {
+	if s.auditStore == nil {
+		return nil, audit.ErrDisabled
+	}
+
+	// 依商品目前的聚合窗口對齊到窗口開始時間
+	window := s.subscriber.WindowFor(symbol).Milliseconds()
+	return s.auditStore.Get(ctx, symbol, timestamp-timestamp%window)
+}
+
+// GetPriceSource 目前的價格來源：MESSAGE_BUS 的名稱，或訊息匯流排中斷期間為 grpc
+func (s *PlatformService) GetPriceSource() string {
+	return s.subscriber.Source()
+}
+
+// GetPriceServiceState 獲取 Price Service 斷路器的狀態：closed、open 或 half_open
+func (s *PlatformService) GetPriceServiceState() string {
+	return s.grpcClient.BreakerState()
+}
+
+// GetFeedStatus 獲取商品價格來源的狀態，尚未收到過價格或心跳時 ok 為 false
+func (s *PlatformService) GetFeedStatus(symbol string) (model.FeedStatus, bool) {
+	return s.feed.Status(symbol)
+}
+
+// GetFeedStatuses 獲取所有商品價格來源的狀態
+func (s *PlatformService) GetFeedStatuses() []model.FeedStatus {
+	return s.feed.Statuses()
+}
+
+// GetHaltStatus 獲取商品的暫停狀態，尚未檢查過價格或未啟用價格檢查時 ok 為 false
+func (s *PlatformService) GetHaltStatus(symbol string) (model.HaltStatus, bool) {
+	if s.guard == nil {
+		return model.HaltStatus{}, false
+	}
+	return s.guard.Status(symbol)
+}
+
+// GetHaltStatuses 獲取所有商品的暫停狀態
+func (s *PlatformService) GetHaltStatuses() []model.HaltStatus {
+	if s.guard == nil {
+		return []model.HaltStatus{}
+	}
+	return s.guard.Statuses()
+}
+
+// HaltSymbol 手動暫停商品的價格推送，需手動恢復
+func (s *PlatformService) HaltSymbol(symbol, reason string) (model.HaltStatus, error) {
+	if s.guard == nil {
+		return model.HaltStatus{}, guard.ErrDisabled
+	}
+	return s.guard.Halt(symbol, reason), nil
+}
+
+// ResumeSymbol 手動恢復暫停的商品
+func (s *PlatformService) ResumeSymbol(symbol string) (model.HaltStatus, error) {
+	if s.guard == nil {
+		return model.HaltStatus{}, guard.ErrDisabled
+	}
+	return s.guard.Resume(symbol)
+}
+
+// GetAggregationStats 獲取各商品事件時間聚合的狀態
+func (s *PlatformService) GetAggregationStats() []model.AggregationStats {
+	return s.subscriber.Stats()
 }
 
 // Stop 停止服務
@@ -186,6 +550,13 @@ func (s *PlatformService) Stop() error {
 	// 等待所有 goroutines 結束
 	s.wg.Wait()
 
+	// 關閉審計存儲
+	if s.auditStore != nil {
+		if err := s.auditStore.Close(); err != nil {
+			log.Printf("❌ Error closing audit store: %v", err)
+		}
+	}
+
 	// 關閉 gRPC 客戶端
 	if err := s.grpcClient.Close(); err != nil {
 		log.Printf("❌ Error closing grpc client: %v", err)
@@ -0,0 +1,139 @@
+package strategy
+
+import (
+	"sort"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// selectFirst 窗口內時間最早的價格
+func selectFirst(b *model.PriceBuffer) *model.Price {
+	first := b.Prices[0]
+	for _, p := range b.Prices[1:] {
+		if p.Timestamp < first.Timestamp {
+			first = p
+		}
+	}
+	return &first
+}
+
+// selectLast 窗口內時間最晚的價格
+func selectLast(b *model.PriceBuffer) *model.Price {
+	last := b.Prices[0]
+	for _, p := range b.Prices[1:] {
+		if p.Timestamp >= last.Timestamp {
+			last = p
+		}
+	}
+	return &last
+}
+
+// selectMean 算術平均
+func selectMean(b *model.PriceBuffer) *model.Price {
+	var sum float64
+	for _, p := range b.Prices {
+		sum += p.Price
+	}
+	return derive(b, sum/float64(len(b.Prices)))
+}
+
+// selectMedian 中位數，偶數筆時取中間兩筆的平均
+func selectMedian(b *model.PriceBuffer) *model.Price {
+	values := make([]float64, len(b.Prices))
+	for i, p := range b.Prices {
+		values[i] = p.Price
+	}
+	sort.Float64s(values)
+
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return derive(b, values[mid])
+	}
+	return derive(b, (values[mid-1]+values[mid])/2)
+}
+
+// selectTWAP 時間加權平均：每筆價格的權重為持續到下一筆價格（最後一筆持續到窗口結束）的時間
+// 所有價格時間相同時退化為算術平均
+func selectTWAP(b *model.PriceBuffer) *model.Price {
+	prices := sortedByTime(b.Prices)
+	windowEnd := b.End()
+
+	var sum, weight float64
+	for i, p := range prices {
+		end := windowEnd
+		if i+1 < len(prices) {
+			end = prices[i+1].Timestamp
+		}
+		if end <= p.Timestamp {
+			continue
+		}
+
+		w := float64(end - p.Timestamp)
+		sum += p.Price * w
+		weight += w
+	}
+
+	if weight == 0 {
+		return selectMean(b)
+	}
+	return derive(b, sum/weight)
+}
+
+// SpreadAdjusted 依窗口內價差調整的中間價：mid + factor × spread / 2
+// mid 為最高價和最低價的中點，spread 為兩者之差；
+// factor 為 -1 時等同 best，0 為中間價，1 等同 worst，介於其間可調整對用戶的有利程度
+type SpreadAdjusted struct {
+	factor float64
+}
+
+// NewSpreadAdjusted 創建價差調整策略，factor 超出 [-1, 1] 時會被截斷
+func NewSpreadAdjusted(factor float64) *SpreadAdjusted {
+	if factor < -1 {
+		factor = -1
+	} else if factor > 1 {
+		factor = 1
+	}
+	return &SpreadAdjusted{factor: factor}
+}
+
+// Name 策略名稱
+func (s *SpreadAdjusted) Name() string { return Spread }
+
+// Select 選出價差調整後的價格
+func (s *SpreadAdjusted) Select(b *model.PriceBuffer) *model.Price {
+	if b == nil || len(b.Prices) == 0 {
+		return nil
+	}
+
+	low, high := b.GetBestPrice().Price, b.GetWorstPrice().Price
+	mid := (low + high) / 2
+	return derive(b, mid+s.factor*(high-low)/2)
+}
+
+// derive 以窗口內最後一筆價格為基準產生計算出的價格
+// 漲跌幅相對於最後一筆價格的前一價格（Price - Change）重新計算
+func derive(b *model.PriceBuffer, value float64) *model.Price {
+	base := selectLast(b)
+	previous := base.Price - base.Change
+
+	price := &model.Price{
+		Symbol:    base.Symbol,
+		Price:     value,
+		Timestamp: base.Timestamp,
+		Change:    value - previous,
+	}
+	if previous != 0 {
+		price.ChangePercent = price.Change / previous * 100
+	}
+	return price
+}
+
+// sortedByTime 依時間排序的副本
+func sortedByTime(prices []model.Price) []model.Price {
+	sorted := make([]model.Price, len(prices))
+	copy(sorted, prices)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+	return sorted
+}
@@ -0,0 +1,119 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// 內建策略名稱
+const (
+	Best   = "best"   // 最低價格（對用戶最有利的買入價）
+	Worst  = "worst"  // 最高價格（對用戶最不利的買入價）
+	First  = "first"  // 窗口內第一筆價格
+	Last   = "last"   // 窗口內最後一筆價格
+	Mean   = "mean"   // 算術平均
+	Median = "median" // 中位數
+	TWAP   = "twap"   // 時間加權平均
+	Spread = "spread" // 依窗口內價差調整的中間價
+)
+
+// SelectionStrategy 從一個聚合窗口的價格中選出推送給用戶的價格
+type SelectionStrategy interface {
+	// Name 策略名稱，用於設定和審計記錄
+	Name() string
+	// Select 從緩衝區選出價格，緩衝區為空時返回 nil
+	Select(buffer *model.PriceBuffer) *model.Price
+}
+
+// Registry 依名稱註冊的策略
+type Registry struct {
+	mu         sync.RWMutex
+	strategies map[string]SelectionStrategy
+}
+
+// NewRegistry 創建包含所有內建策略的註冊表
+// spreadFactor 為 spread 策略的價差係數，見 NewSpreadAdjusted
+func NewRegistry(spreadFactor float64) *Registry {
+	r := &Registry{strategies: make(map[string]SelectionStrategy)}
+
+	r.Register(Func(Best, func(b *model.PriceBuffer) *model.Price { return b.GetBestPrice() }))
+	r.Register(Func(Worst, func(b *model.PriceBuffer) *model.Price { return b.GetWorstPrice() }))
+	r.Register(Func(First, selectFirst))
+	r.Register(Func(Last, selectLast))
+	r.Register(Func(Mean, selectMean))
+	r.Register(Func(Median, selectMedian))
+	r.Register(Func(TWAP, selectTWAP))
+	r.Register(NewSpreadAdjusted(spreadFactor))
+
+	return r
+}
+
+// Register 註冊策略，同名策略會被取代
+func (r *Registry) Register(s SelectionStrategy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.strategies[s.Name()] = s
+}
+
+// Get 依名稱獲取策略
+func (r *Registry) Get(name string) (SelectionStrategy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown price strategy: %s", name)
+	}
+	return s, nil
+}
+
+// SelectAll 以所有已註冊的策略選價，返回策略名稱 -> 價格，選不出價格的策略不包含在內
+func (r *Registry) SelectAll(buffer *model.PriceBuffer) map[string]*model.Price {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prices := make(map[string]*model.Price, len(r.strategies))
+	for name, s := range r.strategies {
+		if price := s.Select(buffer); price != nil {
+			prices[name] = price
+		}
+	}
+	return prices
+}
+
+// Names 已註冊的策略名稱（排序）
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.strategies))
+	for name := range r.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// funcStrategy 以函式實作的策略
+type funcStrategy struct {
+	name     string
+	selectFn func(*model.PriceBuffer) *model.Price
+}
+
+// Func 以函式建立策略
+func Func(name string, fn func(*model.PriceBuffer) *model.Price) SelectionStrategy {
+	return &funcStrategy{name: name, selectFn: fn}
+}
+
+func (s *funcStrategy) Name() string { return s.name }
+
+func (s *funcStrategy) Select(buffer *model.PriceBuffer) *model.Price {
+	if buffer == nil || len(buffer.Prices) == 0 {
+		return nil
+	}
+	return s.selectFn(buffer)
+}
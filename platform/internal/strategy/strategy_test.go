@@ -0,0 +1,117 @@
+package strategy
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mike/golden-buy/platform/internal/model"
+)
+
+// testBuffer 窗口 [1000, 2000) 內的三筆價格，順序未依時間排列
+// 最後一筆為 14（前一價格 12）
+func testBuffer() *model.PriceBuffer {
+	return &model.PriceBuffer{
+		Symbol:    "GOLD",
+		Timestamp: 1000,
+		Window:    1000,
+		Prices: []model.Price{
+			{Symbol: "GOLD", Price: 10, Timestamp: 1000},
+			{Symbol: "GOLD", Price: 14, Timestamp: 1500, Change: 2},
+			{Symbol: "GOLD", Price: 12, Timestamp: 1250, Change: 2},
+		},
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		name          string
+		strategy      SelectionStrategy
+		wantPrice     float64
+		wantTimestamp int64
+		wantChange    float64
+	}{
+		{name: Best, wantPrice: 10, wantTimestamp: 1000},
+		{name: Worst, wantPrice: 14, wantTimestamp: 1500, wantChange: 2},
+		{name: First, wantPrice: 10, wantTimestamp: 1000},
+		{name: Last, wantPrice: 14, wantTimestamp: 1500, wantChange: 2},
+		{name: Mean, wantPrice: 12, wantTimestamp: 1500, wantChange: 0},
+		{name: Median, wantPrice: 12, wantTimestamp: 1500, wantChange: 0},
+		{name: TWAP, wantPrice: 12.5, wantTimestamp: 1500, wantChange: 0.5},
+		{name: "spread mid", strategy: NewSpreadAdjusted(0), wantPrice: 12, wantTimestamp: 1500},
+		{name: "spread best", strategy: NewSpreadAdjusted(-1), wantPrice: 10, wantTimestamp: 1500, wantChange: -2},
+		{name: "spread clamped", strategy: NewSpreadAdjusted(5), wantPrice: 14, wantTimestamp: 1500, wantChange: 2},
+	}
+
+	registry := NewRegistry(0)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.strategy
+			if s == nil {
+				var err error
+				if s, err = registry.Get(tt.name); err != nil {
+					t.Fatalf("Get(%q) error = %v", tt.name, err)
+				}
+			}
+
+			got := s.Select(testBuffer())
+			if got == nil {
+				t.Fatal("Select() = nil")
+			}
+			if math.Abs(got.Price-tt.wantPrice) > 1e-9 || got.Timestamp != tt.wantTimestamp || math.Abs(got.Change-tt.wantChange) > 1e-9 {
+				t.Errorf("Select() = {price=%v ts=%d change=%v}, want {price=%v ts=%d change=%v}",
+					got.Price, got.Timestamp, got.Change, tt.wantPrice, tt.wantTimestamp, tt.wantChange)
+			}
+			if got.Symbol != "GOLD" {
+				t.Errorf("Select() symbol = %q, want GOLD", got.Symbol)
+			}
+		})
+	}
+}
+
+func TestTWAPWithEqualTimestamps(t *testing.T) {
+	buffer := &model.PriceBuffer{
+		Symbol:    "GOLD",
+		Timestamp: 1000,
+		Window:    1000,
+		Prices: []model.Price{
+			{Symbol: "GOLD", Price: 10, Timestamp: 2000},
+			{Symbol: "GOLD", Price: 20, Timestamp: 2000},
+		},
+	}
+
+	// 所有價格都在窗口結束時間上，沒有持續時間，退化為算術平均
+	if got := selectTWAP(buffer); got.Price != 15 {
+		t.Errorf("selectTWAP() = %v, want 15", got.Price)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(0)
+
+	if _, err := r.Get("unknown"); err == nil {
+		t.Error("Get(unknown) error = nil")
+	}
+
+	want := []string{Best, First, Last, Mean, Median, Spread, TWAP, Worst}
+	if got := r.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+
+	// 同名策略被取代
+	r.Register(Func(Best, func(b *model.PriceBuffer) *model.Price {
+		return &model.Price{Symbol: b.Symbol, Price: -1}
+	}))
+	if got := r.SelectAll(testBuffer())[Best]; got == nil || got.Price != -1 {
+		t.Errorf("SelectAll()[best] = %v, want the replacement strategy", got)
+	}
+	if got := len(r.Names()); got != len(want) {
+		t.Errorf("len(Names()) = %d after replacing, want %d", got, len(want))
+	}
+
+	// 空窗口沒有任何策略選出價格
+	if got := r.SelectAll(&model.PriceBuffer{Symbol: "GOLD"}); len(got) != 0 {
+		t.Errorf("SelectAll(empty) = %v, want none", got)
+	}
+}
@@ -31,6 +31,12 @@ type Client struct {
 
 	// 發送緩衝區
 	send chan []byte
+
+	// 連線的用戶 ID（demo 階段由 user_id 查詢參數指定），可為空
+	userID string
+
+	// 用戶套用的選價策略，空字串表示商品的預設策略
+	strategy string
 }
 
 // readPump 從 WebSocket 連接讀取消息
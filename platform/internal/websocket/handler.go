@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gorilla/websocket"
+	"github.com/mike/golden-buy/platform/internal/auth"
 )
 
 var upgrader = websocket.Upgrader{
@@ -16,20 +17,38 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// Authenticator 驗證 token 並返回已認證的用戶 ID
+type Authenticator func(token string) (string, error)
+
 // Handler WebSocket 處理器
 type Handler struct {
-	hub *Hub
+	hub          *Hub
+	authenticate Authenticator
 }
 
 // NewHandler 創建新的 WebSocket 處理器
-func NewHandler(hub *Hub) *Handler {
+func NewHandler(hub *Hub, authenticate Authenticator) *Handler {
 	return &Handler{
-		hub: hub,
+		hub:          hub,
+		authenticate: authenticate,
 	}
 }
 
 // ServeWS 處理 WebSocket 連接
+// 用戶身分只取自驗證過的 token（Authorization: Bearer 或 access_token 查詢參數），
+// 沒有 token 的連線為匿名，收到商品預設策略的價格；token 無效時拒絕連線
 func (h *Handler) ServeWS(w http.ResponseWriter, r *http.Request) {
+	var userID string
+	if token := auth.TokenFromRequest(r); token != "" {
+		id, err := h.authenticate(token)
+		if err != nil {
+			log.Printf("🚫 WebSocket authentication failed from %s: %v", r.RemoteAddr, err)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		userID = id
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("❌ Failed to upgrade connection: %v", err)
@@ -37,9 +56,11 @@ func (h *Handler) ServeWS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := &Client{
-		hub:  h.hub,
-		conn: conn,
-		send: make(chan []byte, 256),
+		hub:      h.hub,
+		conn:     conn,
+		send:     make(chan []byte, 256),
+		userID:   userID,
+		strategy: h.hub.strategyFor(userID),
 	}
 
 	client.hub.register <- client
@@ -31,10 +31,36 @@ type Hub struct {
 	// 取消訂閱請求
 	unsubscribe chan *Subscription
 
+	// 商品關注狀態變化的回調，為 nil 時不通知
+	onSymbol SymbolListener
+
+	// 依用戶 ID 決定選價策略，為 nil 時所有客戶端使用商品的預設策略
+	resolveStrategy StrategyResolver
+
+	// 商品目前的價格來源狀態，訂閱時發送給客戶端，為 nil 時不發送
+	feedStatus FeedStatusProvider
+
+	// 商品目前的暫停狀態，訂閱時發送給客戶端，為 nil 時不發送
+	haltStatus HaltStatusProvider
+
 	// 保護 clients 和 subscriptions 的互斥鎖
 	mu sync.RWMutex
 }
 
+// SymbolListener 商品關注狀態變化的回調
+// active 為 true 表示商品從沒有客戶端關注變為有人關注，false 表示最後一個客戶端已取消關注
+// 在 Hub 的 goroutine 中調用，不可進行網路請求等耗時操作
+type SymbolListener func(symbol string, active bool)
+
+// StrategyResolver 返回用戶套用的選價策略名稱，空字串表示使用商品的預設策略
+type StrategyResolver func(userID string) string
+
+// FeedStatusProvider 返回商品目前的價格來源狀態，尚未收到過價格或心跳時 ok 為 false
+type FeedStatusProvider func(symbol string) (status model.FeedStatus, ok bool)
+
+// HaltStatusProvider 返回商品目前的暫停狀態，尚未檢查過價格時 ok 為 false
+type HaltStatusProvider func(symbol string) (status model.HaltStatus, ok bool)
+
 // Subscription 訂閱請求
 type Subscription struct {
 	Client *Client
@@ -43,7 +69,7 @@ type Subscription struct {
 
 // Message WebSocket 消息格式
 type Message struct {
-	Type    string      `json:"type"` // "subscribe", "unsubscribe", "price_update", "error"
+	Type    string      `json:"type"` // "subscribe", "unsubscribe", "price_update", "feed_status", "halt_status", "error"
 	Symbol  string      `json:"symbol,omitempty"`
 	Symbols []string    `json:"symbols,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
@@ -56,6 +82,10 @@ type PriceUpdate struct {
 	Price         float64 `json:"price"`
 	ChangePercent float64 `json:"change_percent"`
 	Timestamp     int64   `json:"timestamp"`
+	Strategy      string  `json:"strategy,omitempty"`     // 選出此價格的策略
+	WindowStart   int64   `json:"window_start,omitempty"` // 聚合窗口開始時間（Unix 毫秒），K 線依此分桶
+	Window        int64   `json:"window,omitempty"`       // 聚合窗口長度（毫秒）
+	Stale         bool    `json:"stale"`                  // 窗口結束後超過門檻時間才推送的延遲價格
 }
 
 // NewHub 創建新的 Hub
@@ -94,6 +124,7 @@ func (h *Hub) Run() {
 			}
 
 		case client := <-h.unregister:
+			var inactive []string
 			h.mu.Lock()
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
@@ -101,17 +132,26 @@ func (h *Hub) Run() {
 
 				// 從所有訂閱中移除
 				for symbol, clients := range h.subscriptions {
+					if !clients[client] {
+						continue
+					}
 					delete(clients, client)
 					if len(clients) == 0 {
 						delete(h.subscriptions, symbol)
+						inactive = append(inactive, symbol)
 					}
 				}
 			}
 			h.mu.Unlock()
 			log.Printf("🔌 WebSocket client disconnected (total: %d)", len(h.clients))
 
+			for _, symbol := range inactive {
+				h.notifySymbol(symbol, false)
+			}
+
 		case sub := <-h.subscribe:
 			h.mu.Lock()
+			first := len(h.subscriptions[sub.Symbol]) == 0
 			if h.subscriptions[sub.Symbol] == nil {
 				h.subscriptions[sub.Symbol] = make(map[*Client]bool)
 			}
@@ -119,6 +159,10 @@ func (h *Hub) Run() {
 			h.mu.Unlock()
 			log.Printf("📊 Client subscribed to %s", sub.Symbol)
 
+			if first {
+				h.notifySymbol(sub.Symbol, true)
+			}
+
 			// 發送訂閱確認
 			confirmMsg := Message{
 				Type:   "subscribed",
@@ -128,17 +172,41 @@ func (h *Hub) Run() {
 				sub.Client.send <- data
 			}
 
+			// 發送商品目前的價格來源狀態，客戶端不需等到下一次狀態變化
+			if h.feedStatus != nil {
+				if status, ok := h.feedStatus(sub.Symbol); ok {
+					if data, err := feedStatusMessage(status); err == nil {
+						sub.Client.send <- data
+					}
+				}
+			}
+
+			// 發送商品目前的暫停狀態，暫停中的商品不會收到價格更新
+			if h.haltStatus != nil {
+				if status, ok := h.haltStatus(sub.Symbol); ok {
+					if data, err := haltStatusMessage(status); err == nil {
+						sub.Client.send <- data
+					}
+				}
+			}
+
 		case sub := <-h.unsubscribe:
+			last := false
 			h.mu.Lock()
 			if clients, ok := h.subscriptions[sub.Symbol]; ok {
 				delete(clients, sub.Client)
 				if len(clients) == 0 {
 					delete(h.subscriptions, sub.Symbol)
+					last = true
 				}
 			}
 			h.mu.Unlock()
 			log.Printf("📊 Client unsubscribed from %s", sub.Symbol)
 
+			if last {
+				h.notifySymbol(sub.Symbol, false)
+			}
+
 		case message := <-h.broadcast:
 			h.mu.RLock()
 			for client := range h.clients {
@@ -154,6 +222,57 @@ func (h *Hub) Run() {
 	}
 }
 
+// SetSymbolListener 設置商品關注狀態變化的回調，需在 Run 之前調用
+func (h *Hub) SetSymbolListener(listener SymbolListener) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.onSymbol = listener
+}
+
+// SetStrategyResolver 設置用戶選價策略的解析函式，需在接受連線之前調用
+func (h *Hub) SetStrategyResolver(resolver StrategyResolver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.resolveStrategy = resolver
+}
+
+// SetFeedStatusProvider 設置商品價格來源狀態的查詢函式，需在 Run 之前調用
+func (h *Hub) SetFeedStatusProvider(provider FeedStatusProvider) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.feedStatus = provider
+}
+
+// SetHaltStatusProvider 設置商品暫停狀態的查詢函式，需在 Run 之前調用
+func (h *Hub) SetHaltStatusProvider(provider HaltStatusProvider) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.haltStatus = provider
+}
+
+// strategyFor 用戶套用的選價策略
+func (h *Hub) strategyFor(userID string) string {
+	h.mu.RLock()
+	resolver := h.resolveStrategy
+	h.mu.RUnlock()
+
+	if resolver == nil || userID == "" {
+		return ""
+	}
+	return resolver(userID)
+}
+
+// notifySymbol 通知商品關注狀態變化，在 Hub 的 goroutine 中依序調用
+func (h *Hub) notifySymbol(symbol string, active bool) {
+	if h.onSymbol != nil {
+		h.onSymbol(symbol, active)
+	}
+}
+
 // BroadcastPrice 廣播價格更新到訂閱的客戶端
 func (h *Hub) BroadcastPrice(price *model.Price) {
 	h.mu.RLock()
@@ -169,20 +288,12 @@ func (h *Hub) BroadcastPrice(price *model.Price) {
 	}
 
 	// 構建價格更新消息
-	priceUpdate := PriceUpdate{
+	data, err := priceUpdateMessage(PriceUpdate{
 		Symbol:        price.Symbol,
 		Price:         price.Price,
 		ChangePercent: price.ChangePercent,
 		Timestamp:     price.Timestamp,
-	}
-
-	msg := Message{
-		Type:   "price_update",
-		Symbol: price.Symbol,
-		Data:   priceUpdate,
-	}
-
-	data, err := json.Marshal(msg)
+	})
 	if err != nil {
 		log.Printf("❌ Failed to marshal price update: %v", err)
 		return
@@ -200,6 +311,119 @@ func (h *Hub) BroadcastPrice(price *model.Price) {
 	h.mu.RUnlock()
 }
 
+// BroadcastSelection 廣播價格更新，每個客戶端收到其用戶選價策略的價格
+func (h *Hub) BroadcastSelection(selection *model.PriceSelection) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clients := h.subscriptions[selection.Symbol]
+	if len(clients) == 0 {
+		return
+	}
+
+	// 同一策略的客戶端共用同一則消息
+	messages := make(map[string][]byte)
+	for client := range clients {
+		data, ok := messages[client.strategy]
+		if !ok {
+			price, name := selection.Get(client.strategy)
+			if price == nil {
+				continue
+			}
+
+			var err error
+			data, err = priceUpdateMessage(PriceUpdate{
+				Symbol:        price.Symbol,
+				Price:         price.Price,
+				ChangePercent: price.ChangePercent,
+				Timestamp:     price.Timestamp,
+				Strategy:      name,
+				WindowStart:   selection.WindowStart,
+				Window:        selection.Window,
+				Stale:         selection.Stale,
+			})
+			if err != nil {
+				log.Printf("❌ Failed to marshal price update: %v", err)
+				continue
+			}
+			messages[client.strategy] = data
+		}
+
+		select {
+		case client.send <- data:
+		default:
+			// 客戶端發送緩衝區已滿，跳過
+		}
+	}
+}
+
+// BroadcastFeedStatus 廣播商品價格來源狀態變化（live <-> stale）到訂閱的客戶端
+func (h *Hub) BroadcastFeedStatus(status model.FeedStatus) {
+	data, err := feedStatusMessage(status)
+	if err != nil {
+		log.Printf("❌ Failed to marshal feed status: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.subscriptions[status.Symbol] {
+		select {
+		case client.send <- data:
+		default:
+			// 客戶端發送緩衝區已滿，跳過
+		}
+	}
+}
+
+// BroadcastHaltStatus 廣播商品暫停或恢復到訂閱的客戶端
+func (h *Hub) BroadcastHaltStatus(status model.HaltStatus) {
+	data, err := haltStatusMessage(status)
+	if err != nil {
+		log.Printf("❌ Failed to marshal halt status: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.subscriptions[status.Symbol] {
+		select {
+		case client.send <- data:
+		default:
+			// 客戶端發送緩衝區已滿，跳過
+		}
+	}
+}
+
+// feedStatusMessage 構建價格來源狀態消息
+func feedStatusMessage(status model.FeedStatus) ([]byte, error) {
+	return json.Marshal(Message{
+		Type:   "feed_status",
+		Symbol: status.Symbol,
+		Data:   status,
+	})
+}
+
+// haltStatusMessage 構建商品暫停狀態消息
+func haltStatusMessage(status model.HaltStatus) ([]byte, error) {
+	return json.Marshal(Message{
+		Type:   "halt_status",
+		Symbol: status.Symbol,
+		Data:   status,
+	})
+}
+
+// priceUpdateMessage 構建價格更新消息
+func priceUpdateMessage(update PriceUpdate) ([]byte, error) {
+	return json.Marshal(Message{
+		Type:   "price_update",
+		Symbol: update.Symbol,
+		Data:   update,
+	})
+}
+
 // GetClientCount 獲取當前連接的客戶端數量
 func (h *Hub) GetClientCount() int {
 	h.mu.RLock()
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/mike/golden-buy/platform/internal/config"
+	"github.com/mike/golden-buy/platform/internal/model"
 	httpserver "github.com/mike/golden-buy/platform/internal/http"
 	"github.com/mike/golden-buy/platform/internal/service"
 )
@@ -64,6 +65,16 @@ func main() {
 		}
 	}()
 
+	// 管理用 HTTP 服務器（獨立的監聽位址，不對外公開）
+	if cfg.AdminHTTPAddr != "" {
+		adminServer := httpserver.NewAdminServer(cfg.AdminHTTPAddr, svc)
+		go func() {
+			if err := adminServer.Start(); err != nil {
+				log.Fatalf("❌ Failed to start admin HTTP server: %v", err)
+			}
+		}()
+	}
+
 	// 測試：獲取 K 線資料
 	go testKlines(svc)
 
@@ -104,16 +115,23 @@ func testKlines(svc *service.PlatformService) {
 		endTime := time.Now().UnixMilli()
 		startTime := endTime - (60 * 60 * 1000) // 1 小時前
 
-		klines, err := svc.GetKlines(ctx, symbol, "1m", startTime, endTime, 10)
+		page, err := svc.GetKlines(ctx, model.KlineQuery{
+			Symbol:    symbol,
+			Interval:  "1m",
+			StartTime: startTime,
+			EndTime:   endTime,
+			Limit:     10,
+		})
 		if err != nil {
 			log.Printf("❌ Failed to get klines for %s: %v", symbol, err)
 			continue
 		}
 
-		if len(klines) > 0 {
-			log.Printf("✅ [%s] Retrieved %d klines", symbol, len(klines))
+		if klines := page.Klines; len(klines) > 0 {
+			latest := klines[len(klines)-1]
+			log.Printf("✅ [%s] Retrieved %d of %d klines", symbol, len(klines), page.Total)
 			log.Printf("   Latest kline: Open=%.2f, High=%.2f, Low=%.2f, Close=%.2f",
-				klines[0].Open, klines[0].High, klines[0].Low, klines[0].Close)
+				latest.Open, latest.High, latest.Low, latest.Close)
 		} else {
 			log.Printf("⚠️  [%s] No klines available yet", symbol)
 		}
@@ -156,10 +156,15 @@ func (x *SubscribeRequest) GetSymbols() []string {
 type GetKlinesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`                     // 1m, 5m, 15m, 30m, 1h, 4h, 1d
-	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix 毫秒
-	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix 毫秒
-	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                          // 限制數量，預設 100，最大 1000
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`                              // 1s, 1m, 3m, 5m, 15m, 30m, 1h, 2h, 4h, 1d, 1w, 1M
+	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`          // Unix 毫秒
+	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                // Unix 毫秒
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                                   // 限制數量，預設 100，最大 1000
+	Direction     string                 `protobuf:"bytes,6,opt,name=direction,proto3" json:"direction,omitempty"`                            // latest（預設，取範圍內最新的 N 根）或 oldest（取最早的 N 根）
+	Cursor        string                 `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"`                                  // 分頁游標，來自上一頁的 next_cursor
+	Fill          string                 `protobuf:"bytes,8,opt,name=fill,proto3" json:"fill,omitempty"`                                      // 缺口填補模式：none（預設）、previous（前一收盤價）、null（空 K 線）
+	Timezone      string                 `protobuf:"bytes,9,opt,name=timezone,proto3" json:"timezone,omitempty"`                              // 日線以上 K 線對齊的 IANA 時區，例如 Asia/Taipei，預設 UTC
+	SessionStart  string                 `protobuf:"bytes,10,opt,name=session_start,json=sessionStart,proto3" json:"session_start,omitempty"` // 交易日開始時間 HH:MM（當地時間），例如 America/New_York 的 17:00，預設 00:00
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -229,32 +234,937 @@ func (x *GetKlinesRequest) GetLimit() int32 {
 	return 0
 }
 
+func (x *GetKlinesRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *GetKlinesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetKlinesRequest) GetFill() string {
+	if x != nil {
+		return x.Fill
+	}
+	return ""
+}
+
+func (x *GetKlinesRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GetKlinesRequest) GetSessionStart() string {
+	if x != nil {
+		return x.SessionStart
+	}
+	return ""
+}
+
+type GetStatisticsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Range         string                 `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`                           // 統計範圍：30m、24h（預設）、7d、4w 或 ytd，指定 start_time 時忽略
+	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix 毫秒，選填
+	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix 毫秒，選填，預設為現在
+	Timezone      string                 `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"`                     // ytd 年初使用的 IANA 時區，預設 UTC
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatisticsRequest) Reset() {
+	*x = GetStatisticsRequest{}
+	mi := &file_proto_price_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatisticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatisticsRequest) ProtoMessage() {}
+
+func (x *GetStatisticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatisticsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatisticsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetStatisticsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetStatisticsRequest) GetRange() string {
+	if x != nil {
+		return x.Range
+	}
+	return ""
+}
+
+func (x *GetStatisticsRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GetStatisticsRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *GetStatisticsRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type IndicatorSpec struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`              // sma、ema、bollinger、rsi、macd
+	Params        []float64              `protobuf:"fixed64,2,rep,packed,name=params,proto3" json:"params,omitempty"` // 指標參數，省略時使用預設值：sma/ema [20]、bollinger [20, 2]、rsi [14]、macd [12, 26, 9]
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndicatorSpec) Reset() {
+	*x = IndicatorSpec{}
+	mi := &file_proto_price_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndicatorSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndicatorSpec) ProtoMessage() {}
+
+func (x *IndicatorSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndicatorSpec.ProtoReflect.Descriptor instead.
+func (*IndicatorSpec) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IndicatorSpec) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IndicatorSpec) GetParams() []float64 {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type GetIndicatorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`                             // 同 GetKlinesRequest.interval，預設 1m
+	Indicators    []*IndicatorSpec       `protobuf:"bytes,3,rep,name=indicators,proto3" json:"indicators,omitempty"`                         // 最多 10 個
+	StartTime     int64                  `protobuf:"varint,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`         // Unix 毫秒
+	EndTime       int64                  `protobuf:"varint,5,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`               // Unix 毫秒
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`                                  // 計算範圍內最新的 N 根 K 線，預設 100，最大 1000
+	Timezone      string                 `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`                             // 同 GetKlinesRequest.timezone
+	SessionStart  string                 `protobuf:"bytes,8,opt,name=session_start,json=sessionStart,proto3" json:"session_start,omitempty"` // 同 GetKlinesRequest.session_start
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIndicatorsRequest) Reset() {
+	*x = GetIndicatorsRequest{}
+	mi := &file_proto_price_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIndicatorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIndicatorsRequest) ProtoMessage() {}
+
+func (x *GetIndicatorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIndicatorsRequest.ProtoReflect.Descriptor instead.
+func (*GetIndicatorsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetIndicatorsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetIndicatorsRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetIndicatorsRequest) GetIndicators() []*IndicatorSpec {
+	if x != nil {
+		return x.Indicators
+	}
+	return nil
+}
+
+func (x *GetIndicatorsRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GetIndicatorsRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *GetIndicatorsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetIndicatorsRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GetIndicatorsRequest) GetSessionStart() string {
+	if x != nil {
+		return x.SessionStart
+	}
+	return ""
+}
+
+type GetTicksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	StartTime     int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix 毫秒，預設為 end_time 前 1 分鐘
+	EndTime       int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix 毫秒，預設為現在，與 start_time 相差最多 1 小時
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`  // 分頁標記，來自上一頁的 next_page_token，沿用第一頁的查詢範圍
+	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`    // 每頁筆數，預設 500，最大 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTicksRequest) Reset() {
+	*x = GetTicksRequest{}
+	mi := &file_proto_price_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTicksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTicksRequest) ProtoMessage() {}
+
+func (x *GetTicksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTicksRequest.ProtoReflect.Descriptor instead.
+func (*GetTicksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetTicksRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetTicksRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GetTicksRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *GetTicksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetTicksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
 type PriceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                               // Unix 毫秒
-	Change        float64                `protobuf:"fixed64,4,opt,name=change,proto3" json:"change,omitempty"`                                    // 變化量
-	ChangePercent float64                `protobuf:"fixed64,5,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"` // 變化百分比
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                               // Unix 毫秒
+	Change        float64                `protobuf:"fixed64,4,opt,name=change,proto3" json:"change,omitempty"`                                    // 變化量
+	ChangePercent float64                `protobuf:"fixed64,5,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"` // 變化百分比
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceResponse) Reset() {
+	*x = PriceResponse{}
+	mi := &file_proto_price_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceResponse) ProtoMessage() {}
+
+func (x *PriceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceResponse.ProtoReflect.Descriptor instead.
+func (*PriceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PriceResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PriceResponse) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PriceResponse) GetChange() float64 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+func (x *PriceResponse) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+type PricesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prices        []*PriceResponse       `protobuf:"bytes,1,rep,name=prices,proto3" json:"prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PricesResponse) Reset() {
+	*x = PricesResponse{}
+	mi := &file_proto_price_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricesResponse) ProtoMessage() {}
+
+func (x *PricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricesResponse.ProtoReflect.Descriptor instead.
+func (*PricesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PricesResponse) GetPrices() []*PriceResponse {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+type PriceUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Change        float64                `protobuf:"fixed64,4,opt,name=change,proto3" json:"change,omitempty"`
+	ChangePercent float64                `protobuf:"fixed64,5,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	// 以下欄位用於 Redis / NATS 傳輸的價格事件，gRPC 串流不填寫
+	SchemaVersion int32   `protobuf:"varint,6,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"` // 傳輸格式版本，0 表示舊版無版本的 JSON
+	SourceId      string  `protobuf:"bytes,7,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`                 // 發布價格的 Price Service 實例
+	Heartbeat     bool    `protobuf:"varint,8,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`                              // 心跳訊息：price 為最後價格、timestamp 為發布時間，訂閱者不應當作 tick
+	Volume        float64 `protobuf:"fixed64,9,opt,name=volume,proto3" json:"volume,omitempty"`                                   // 此 tick 的成交量
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceUpdate) Reset() {
+	*x = PriceUpdate{}
+	mi := &file_proto_price_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceUpdate) ProtoMessage() {}
+
+func (x *PriceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceUpdate.ProtoReflect.Descriptor instead.
+func (*PriceUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PriceUpdate) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PriceUpdate) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetChange() float64 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+func (x *PriceUpdate) GetHeartbeat() bool {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return false
+}
+
+func (x *PriceUpdate) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type Kline struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // K 線開始時間
+	Open          float64                `protobuf:"fixed64,2,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,4,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,5,opt,name=close,proto3" json:"close,omitempty"`
+	Volume        float64                `protobuf:"fixed64,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	Filled        bool                   `protobuf:"varint,7,opt,name=filled,proto3" json:"filled,omitempty"` // 缺口填補產生的 K 線
+	Empty         bool                   `protobuf:"varint,8,opt,name=empty,proto3" json:"empty,omitempty"`   // fill=null 產生的空 K 線，OHLC 無意義，應視為 null
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Kline) Reset() {
+	*x = Kline{}
+	mi := &file_proto_price_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Kline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Kline) ProtoMessage() {}
+
+func (x *Kline) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Kline.ProtoReflect.Descriptor instead.
+func (*Kline) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Kline) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Kline) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Kline) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Kline) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Kline) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Kline) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Kline) GetFilled() bool {
+	if x != nil {
+		return x.Filled
+	}
+	return false
+}
+
+func (x *Kline) GetEmpty() bool {
+	if x != nil {
+		return x.Empty
+	}
+	return false
+}
+
+type KlinesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	Klines        []*Kline               `protobuf:"bytes,3,rep,name=klines,proto3" json:"klines,omitempty"`
+	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`                            // 查詢範圍內的 K 線總數
+	NextCursor    string                 `protobuf:"bytes,5,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // 下一頁游標，沒有更多資料時為空
+	Partial       bool                   `protobuf:"varint,6,opt,name=partial,proto3" json:"partial,omitempty"`                        // 部分資料來源失敗，結果可能不完整
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KlinesResponse) Reset() {
+	*x = KlinesResponse{}
+	mi := &file_proto_price_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KlinesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KlinesResponse) ProtoMessage() {}
+
+func (x *KlinesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KlinesResponse.ProtoReflect.Descriptor instead.
+func (*KlinesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *KlinesResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *KlinesResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *KlinesResponse) GetKlines() []*Kline {
+	if x != nil {
+		return x.Klines
+	}
+	return nil
+}
+
+func (x *KlinesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *KlinesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *KlinesResponse) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+type StatisticsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	StartTime     int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix 毫秒
+	EndTime       int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix 毫秒
+	Open          float64                `protobuf:"fixed64,4,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,5,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,6,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,7,opt,name=close,proto3" json:"close,omitempty"`
+	Mean          float64                `protobuf:"fixed64,8,opt,name=mean,proto3" json:"mean,omitempty"`                                         // 範圍內 tick 價格的算術平均
+	Twap          float64                `protobuf:"fixed64,9,opt,name=twap,proto3" json:"twap,omitempty"`                                         // 時間加權平均價
+	Change        float64                `protobuf:"fixed64,10,opt,name=change,proto3" json:"change,omitempty"`                                    // 收盤價相對開盤價的變化量
+	ChangePercent float64                `protobuf:"fixed64,11,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"` // 變化百分比
+	TickCount     int64                  `protobuf:"varint,12,opt,name=tick_count,json=tickCount,proto3" json:"tick_count,omitempty"`              // 範圍內的 tick 數量
+	Volatility    float64                `protobuf:"fixed64,13,opt,name=volatility,proto3" json:"volatility,omitempty"`                            // 1 分鐘對數報酬的已實現波動率（未年化）
+	Vwap          float64                `protobuf:"fixed64,14,opt,name=vwap,proto3" json:"vwap,omitempty"`                                        // 成交量加權平均價，範圍內沒有成交量時為 0
+	Volume        float64                `protobuf:"fixed64,15,opt,name=volume,proto3" json:"volume,omitempty"`                                    // 範圍內的總成交量
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatisticsResponse) Reset() {
+	*x = StatisticsResponse{}
+	mi := &file_proto_price_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatisticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatisticsResponse) ProtoMessage() {}
+
+func (x *StatisticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatisticsResponse.ProtoReflect.Descriptor instead.
+func (*StatisticsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StatisticsResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StatisticsResponse) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetTwap() float64 {
+	if x != nil {
+		return x.Twap
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetChange() float64 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetTickCount() int64 {
+	if x != nil {
+		return x.TickCount
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetVolatility() float64 {
+	if x != nil {
+		return x.Volatility
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetVwap() float64 {
+	if x != nil {
+		return x.Vwap
+	}
+	return 0
+}
+
+func (x *StatisticsResponse) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type IndicatorPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // 對應 K 線的開始時間
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PriceResponse) Reset() {
-	*x = PriceResponse{}
-	mi := &file_proto_price_proto_msgTypes[4]
+func (x *IndicatorPoint) Reset() {
+	*x = IndicatorPoint{}
+	mi := &file_proto_price_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PriceResponse) String() string {
+func (x *IndicatorPoint) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PriceResponse) ProtoMessage() {}
+func (*IndicatorPoint) ProtoMessage() {}
 
-func (x *PriceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_price_proto_msgTypes[4]
+func (x *IndicatorPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -265,68 +1175,101 @@ func (x *PriceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PriceResponse.ProtoReflect.Descriptor instead.
-func (*PriceResponse) Descriptor() ([]byte, []int) {
-	return file_proto_price_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use IndicatorPoint.ProtoReflect.Descriptor instead.
+func (*IndicatorPoint) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *PriceResponse) GetSymbol() string {
+func (x *IndicatorPoint) GetTimestamp() int64 {
 	if x != nil {
-		return x.Symbol
+		return x.Timestamp
 	}
-	return ""
+	return 0
 }
 
-func (x *PriceResponse) GetPrice() float64 {
+func (x *IndicatorPoint) GetValue() float64 {
 	if x != nil {
-		return x.Price
+		return x.Value
 	}
 	return 0
 }
 
-func (x *PriceResponse) GetTimestamp() int64 {
+type IndicatorLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`     // 例如 sma、upper、signal
+	Points        []*IndicatorPoint      `protobuf:"bytes,2,rep,name=points,proto3" json:"points,omitempty"` // 暖機不足的 K 線沒有數值
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndicatorLine) Reset() {
+	*x = IndicatorLine{}
+	mi := &file_proto_price_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndicatorLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndicatorLine) ProtoMessage() {}
+
+func (x *IndicatorLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[15]
 	if x != nil {
-		return x.Timestamp
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *PriceResponse) GetChange() float64 {
+// Deprecated: Use IndicatorLine.ProtoReflect.Descriptor instead.
+func (*IndicatorLine) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *IndicatorLine) GetName() string {
 	if x != nil {
-		return x.Change
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *PriceResponse) GetChangePercent() float64 {
+func (x *IndicatorLine) GetPoints() []*IndicatorPoint {
 	if x != nil {
-		return x.ChangePercent
+		return x.Points
 	}
-	return 0
+	return nil
 }
 
-type PricesResponse struct {
+type IndicatorSeries struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Prices        []*PriceResponse       `protobuf:"bytes,1,rep,name=prices,proto3" json:"prices,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Params        []float64              `protobuf:"fixed64,2,rep,packed,name=params,proto3" json:"params,omitempty"` // 補上預設值後實際使用的參數
+	Lines         []*IndicatorLine       `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PricesResponse) Reset() {
-	*x = PricesResponse{}
-	mi := &file_proto_price_proto_msgTypes[5]
+func (x *IndicatorSeries) Reset() {
+	*x = IndicatorSeries{}
+	mi := &file_proto_price_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PricesResponse) String() string {
+func (x *IndicatorSeries) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PricesResponse) ProtoMessage() {}
+func (*IndicatorSeries) ProtoMessage() {}
 
-func (x *PricesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_price_proto_msgTypes[5]
+func (x *IndicatorSeries) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -337,44 +1280,57 @@ func (x *PricesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PricesResponse.ProtoReflect.Descriptor instead.
-func (*PricesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_price_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use IndicatorSeries.ProtoReflect.Descriptor instead.
+func (*IndicatorSeries) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *PricesResponse) GetPrices() []*PriceResponse {
+func (x *IndicatorSeries) GetName() string {
 	if x != nil {
-		return x.Prices
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IndicatorSeries) GetParams() []float64 {
+	if x != nil {
+		return x.Params
 	}
 	return nil
 }
 
-type PriceUpdate struct {
+func (x *IndicatorSeries) GetLines() []*IndicatorLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type IndicatorsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Change        float64                `protobuf:"fixed64,4,opt,name=change,proto3" json:"change,omitempty"`
-	ChangePercent float64                `protobuf:"fixed64,5,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	Indicators    []*IndicatorSeries     `protobuf:"bytes,3,rep,name=indicators,proto3" json:"indicators,omitempty"`
+	Partial       bool                   `protobuf:"varint,4,opt,name=partial,proto3" json:"partial,omitempty"` // 部分 K 線資料來源失敗，結果可能不完整
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PriceUpdate) Reset() {
-	*x = PriceUpdate{}
-	mi := &file_proto_price_proto_msgTypes[6]
+func (x *IndicatorsResponse) Reset() {
+	*x = IndicatorsResponse{}
+	mi := &file_proto_price_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PriceUpdate) String() string {
+func (x *IndicatorsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PriceUpdate) ProtoMessage() {}
+func (*IndicatorsResponse) ProtoMessage() {}
 
-func (x *PriceUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_price_proto_msgTypes[6]
+func (x *IndicatorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,73 +1341,64 @@ func (x *PriceUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PriceUpdate.ProtoReflect.Descriptor instead.
-func (*PriceUpdate) Descriptor() ([]byte, []int) {
-	return file_proto_price_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use IndicatorsResponse.ProtoReflect.Descriptor instead.
+func (*IndicatorsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *PriceUpdate) GetSymbol() string {
+func (x *IndicatorsResponse) GetSymbol() string {
 	if x != nil {
 		return x.Symbol
 	}
 	return ""
 }
 
-func (x *PriceUpdate) GetPrice() float64 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
-func (x *PriceUpdate) GetTimestamp() int64 {
+func (x *IndicatorsResponse) GetInterval() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Interval
 	}
-	return 0
+	return ""
 }
 
-func (x *PriceUpdate) GetChange() float64 {
+func (x *IndicatorsResponse) GetIndicators() []*IndicatorSeries {
 	if x != nil {
-		return x.Change
+		return x.Indicators
 	}
-	return 0
+	return nil
 }
 
-func (x *PriceUpdate) GetChangePercent() float64 {
+func (x *IndicatorsResponse) GetPartial() bool {
 	if x != nil {
-		return x.ChangePercent
+		return x.Partial
 	}
-	return 0
+	return false
 }
 
-type Kline struct {
+type Tick struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // K 線開始時間
-	Open          float64                `protobuf:"fixed64,2,opt,name=open,proto3" json:"open,omitempty"`
-	High          float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`
-	Low           float64                `protobuf:"fixed64,4,opt,name=low,proto3" json:"low,omitempty"`
-	Close         float64                `protobuf:"fixed64,5,opt,name=close,proto3" json:"close,omitempty"`
-	Volume        float64                `protobuf:"fixed64,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix 毫秒
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Change        float64                `protobuf:"fixed64,3,opt,name=change,proto3" json:"change,omitempty"`
+	ChangePercent float64                `protobuf:"fixed64,4,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Kline) Reset() {
-	*x = Kline{}
-	mi := &file_proto_price_proto_msgTypes[7]
+func (x *Tick) Reset() {
+	*x = Tick{}
+	mi := &file_proto_price_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Kline) String() string {
+func (x *Tick) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Kline) ProtoMessage() {}
+func (*Tick) ProtoMessage() {}
 
-func (x *Kline) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_price_proto_msgTypes[7]
+func (x *Tick) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -462,78 +1409,63 @@ func (x *Kline) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Kline.ProtoReflect.Descriptor instead.
-func (*Kline) Descriptor() ([]byte, []int) {
-	return file_proto_price_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use Tick.ProtoReflect.Descriptor instead.
+func (*Tick) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *Kline) GetTimestamp() int64 {
+func (x *Tick) GetTimestamp() int64 {
 	if x != nil {
 		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *Kline) GetOpen() float64 {
-	if x != nil {
-		return x.Open
-	}
-	return 0
-}
-
-func (x *Kline) GetHigh() float64 {
-	if x != nil {
-		return x.High
-	}
-	return 0
-}
-
-func (x *Kline) GetLow() float64 {
+func (x *Tick) GetPrice() float64 {
 	if x != nil {
-		return x.Low
+		return x.Price
 	}
 	return 0
 }
 
-func (x *Kline) GetClose() float64 {
+func (x *Tick) GetChange() float64 {
 	if x != nil {
-		return x.Close
+		return x.Change
 	}
 	return 0
 }
 
-func (x *Kline) GetVolume() float64 {
+func (x *Tick) GetChangePercent() float64 {
 	if x != nil {
-		return x.Volume
+		return x.ChangePercent
 	}
 	return 0
 }
 
-type KlinesResponse struct {
+type TicksResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
-	Klines        []*Kline               `protobuf:"bytes,3,rep,name=klines,proto3" json:"klines,omitempty"`
-	Total         int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"` // 總數
+	Ticks         []*Tick                `protobuf:"bytes,2,rep,name=ticks,proto3" json:"ticks,omitempty"`                                        // 依時間升序
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 下一頁標記，沒有更多資料時為空
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *KlinesResponse) Reset() {
-	*x = KlinesResponse{}
-	mi := &file_proto_price_proto_msgTypes[8]
+func (x *TicksResponse) Reset() {
+	*x = TicksResponse{}
+	mi := &file_proto_price_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *KlinesResponse) String() string {
+func (x *TicksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KlinesResponse) ProtoMessage() {}
+func (*TicksResponse) ProtoMessage() {}
 
-func (x *KlinesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_price_proto_msgTypes[8]
+func (x *TicksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_price_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,37 +1476,30 @@ func (x *KlinesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KlinesResponse.ProtoReflect.Descriptor instead.
-func (*KlinesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_price_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use TicksResponse.ProtoReflect.Descriptor instead.
+func (*TicksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_price_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *KlinesResponse) GetSymbol() string {
+func (x *TicksResponse) GetSymbol() string {
 	if x != nil {
 		return x.Symbol
 	}
 	return ""
 }
 
-func (x *KlinesResponse) GetInterval() string {
-	if x != nil {
-		return x.Interval
-	}
-	return ""
-}
-
-func (x *KlinesResponse) GetKlines() []*Kline {
+func (x *TicksResponse) GetTicks() []*Tick {
 	if x != nil {
-		return x.Klines
+		return x.Ticks
 	}
 	return nil
 }
 
-func (x *KlinesResponse) GetTotal() int32 {
+func (x *TicksResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Total
+		return x.NextPageToken
 	}
-	return 0
+	return ""
 }
 
 var File_proto_price_proto protoreflect.FileDescriptor
@@ -587,14 +1512,50 @@ const file_proto_price_proto_rawDesc = "" +
 	"\x10GetPricesRequest\x12\x18\n" +
 	"\asymbols\x18\x01 \x03(\tR\asymbols\",\n" +
 	"\x10SubscribeRequest\x12\x18\n" +
-	"\asymbols\x18\x01 \x03(\tR\asymbols\"\x96\x01\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\"\xa1\x02\n" +
 	"\x10GetKlinesRequest\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
 	"\binterval\x18\x02 \x01(\tR\binterval\x12\x1d\n" +
 	"\n" +
 	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
 	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x14\n" +
-	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\x9a\x01\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x1c\n" +
+	"\tdirection\x18\x06 \x01(\tR\tdirection\x12\x16\n" +
+	"\x06cursor\x18\a \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04fill\x18\b \x01(\tR\x04fill\x12\x1a\n" +
+	"\btimezone\x18\t \x01(\tR\btimezone\x12#\n" +
+	"\rsession_start\x18\n" +
+	" \x01(\tR\fsessionStart\"\x9a\x01\n" +
+	"\x14GetStatisticsRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05range\x18\x02 \x01(\tR\x05range\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x1a\n" +
+	"\btimezone\x18\x05 \x01(\tR\btimezone\";\n" +
+	"\rIndicatorSpec\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06params\x18\x02 \x03(\x01R\x06params\"\x91\x02\n" +
+	"\x14GetIndicatorsRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x124\n" +
+	"\n" +
+	"indicators\x18\x03 \x03(\v2\x14.price.IndicatorSpecR\n" +
+	"indicators\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x04 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x05 \x01(\x03R\aendTime\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x1a\n" +
+	"\btimezone\x18\a \x01(\tR\btimezone\x12#\n" +
+	"\rsession_start\x18\b \x01(\tR\fsessionStart\"\x9f\x01\n" +
+	"\x0fGetTicksRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x03 \x01(\x03R\aendTime\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\"\x9a\x01\n" +
 	"\rPriceResponse\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
 	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x1c\n" +
@@ -602,30 +1563,89 @@ const file_proto_price_proto_rawDesc = "" +
 	"\x06change\x18\x04 \x01(\x01R\x06change\x12%\n" +
 	"\x0echange_percent\x18\x05 \x01(\x01R\rchangePercent\">\n" +
 	"\x0ePricesResponse\x12,\n" +
-	"\x06prices\x18\x01 \x03(\v2\x14.price.PriceResponseR\x06prices\"\x98\x01\n" +
+	"\x06prices\x18\x01 \x03(\v2\x14.price.PriceResponseR\x06prices\"\x92\x02\n" +
 	"\vPriceUpdate\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
 	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x1c\n" +
 	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x16\n" +
 	"\x06change\x18\x04 \x01(\x01R\x06change\x12%\n" +
-	"\x0echange_percent\x18\x05 \x01(\x01R\rchangePercent\"\x8d\x01\n" +
+	"\x0echange_percent\x18\x05 \x01(\x01R\rchangePercent\x12%\n" +
+	"\x0eschema_version\x18\x06 \x01(\x05R\rschemaVersion\x12\x1b\n" +
+	"\tsource_id\x18\a \x01(\tR\bsourceId\x12\x1c\n" +
+	"\theartbeat\x18\b \x01(\bR\theartbeat\x12\x16\n" +
+	"\x06volume\x18\t \x01(\x01R\x06volume\"\xbb\x01\n" +
 	"\x05Kline\x12\x1c\n" +
 	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x12\n" +
 	"\x04open\x18\x02 \x01(\x01R\x04open\x12\x12\n" +
 	"\x04high\x18\x03 \x01(\x01R\x04high\x12\x10\n" +
 	"\x03low\x18\x04 \x01(\x01R\x03low\x12\x14\n" +
 	"\x05close\x18\x05 \x01(\x01R\x05close\x12\x16\n" +
-	"\x06volume\x18\x06 \x01(\x01R\x06volume\"\x80\x01\n" +
+	"\x06volume\x18\x06 \x01(\x01R\x06volume\x12\x16\n" +
+	"\x06filled\x18\a \x01(\bR\x06filled\x12\x14\n" +
+	"\x05empty\x18\b \x01(\bR\x05empty\"\xbb\x01\n" +
 	"\x0eKlinesResponse\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
 	"\binterval\x18\x02 \x01(\tR\binterval\x12$\n" +
 	"\x06klines\x18\x03 \x03(\v2\f.price.KlineR\x06klines\x12\x14\n" +
-	"\x05total\x18\x04 \x01(\x05R\x05total2\x92\x02\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\x12\x1f\n" +
+	"\vnext_cursor\x18\x05 \x01(\tR\n" +
+	"nextCursor\x12\x18\n" +
+	"\apartial\x18\x06 \x01(\bR\apartial\"\x88\x03\n" +
+	"\x12StatisticsResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x03 \x01(\x03R\aendTime\x12\x12\n" +
+	"\x04open\x18\x04 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x05 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x06 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\a \x01(\x01R\x05close\x12\x12\n" +
+	"\x04mean\x18\b \x01(\x01R\x04mean\x12\x12\n" +
+	"\x04twap\x18\t \x01(\x01R\x04twap\x12\x16\n" +
+	"\x06change\x18\n" +
+	" \x01(\x01R\x06change\x12%\n" +
+	"\x0echange_percent\x18\v \x01(\x01R\rchangePercent\x12\x1d\n" +
+	"\n" +
+	"tick_count\x18\f \x01(\x03R\ttickCount\x12\x1e\n" +
+	"\n" +
+	"volatility\x18\r \x01(\x01R\n" +
+	"volatility\x12\x12\n" +
+	"\x04vwap\x18\x0e \x01(\x01R\x04vwap\x12\x16\n" +
+	"\x06volume\x18\x0f \x01(\x01R\x06volume\"D\n" +
+	"\x0eIndicatorPoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"R\n" +
+	"\rIndicatorLine\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\x06points\x18\x02 \x03(\v2\x15.price.IndicatorPointR\x06points\"i\n" +
+	"\x0fIndicatorSeries\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06params\x18\x02 \x03(\x01R\x06params\x12*\n" +
+	"\x05lines\x18\x03 \x03(\v2\x14.price.IndicatorLineR\x05lines\"\x9a\x01\n" +
+	"\x12IndicatorsResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x126\n" +
+	"\n" +
+	"indicators\x18\x03 \x03(\v2\x16.price.IndicatorSeriesR\n" +
+	"indicators\x12\x18\n" +
+	"\apartial\x18\x04 \x01(\bR\apartial\"y\n" +
+	"\x04Tick\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06change\x18\x03 \x01(\x01R\x06change\x12%\n" +
+	"\x0echange_percent\x18\x04 \x01(\x01R\rchangePercent\"r\n" +
+	"\rTicksResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12!\n" +
+	"\x05ticks\x18\x02 \x03(\v2\v.price.TickR\x05ticks\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken2\xde\x03\n" +
 	"\fPriceService\x12?\n" +
 	"\x0fGetCurrentPrice\x12\x16.price.GetPriceRequest\x1a\x14.price.PriceResponse\x12B\n" +
 	"\x10GetCurrentPrices\x12\x17.price.GetPricesRequest\x1a\x15.price.PricesResponse\x12@\n" +
 	"\x0fSubscribePrices\x12\x17.price.SubscribeRequest\x1a\x12.price.PriceUpdate0\x01\x12;\n" +
-	"\tGetKlines\x12\x17.price.GetKlinesRequest\x1a\x15.price.KlinesResponseB+Z)github.com/mike/golden-buy/platform/protob\x06proto3"
+	"\tGetKlines\x12\x17.price.GetKlinesRequest\x1a\x15.price.KlinesResponse\x12G\n" +
+	"\rGetStatistics\x12\x1b.price.GetStatisticsRequest\x1a\x19.price.StatisticsResponse\x12G\n" +
+	"\rGetIndicators\x12\x1b.price.GetIndicatorsRequest\x1a\x19.price.IndicatorsResponse\x128\n" +
+	"\bGetTicks\x12\x16.price.GetTicksRequest\x1a\x14.price.TicksResponseB+Z)github.com/mike/golden-buy/platform/protob\x06proto3"
 
 var (
 	file_proto_price_proto_rawDescOnce sync.Once
@@ -639,34 +1659,56 @@ func file_proto_price_proto_rawDescGZIP() []byte {
 	return file_proto_price_proto_rawDescData
 }
 
-var file_proto_price_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_price_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_price_proto_goTypes = []any{
-	(*GetPriceRequest)(nil),  // 0: price.GetPriceRequest
-	(*GetPricesRequest)(nil), // 1: price.GetPricesRequest
-	(*SubscribeRequest)(nil), // 2: price.SubscribeRequest
-	(*GetKlinesRequest)(nil), // 3: price.GetKlinesRequest
-	(*PriceResponse)(nil),    // 4: price.PriceResponse
-	(*PricesResponse)(nil),   // 5: price.PricesResponse
-	(*PriceUpdate)(nil),      // 6: price.PriceUpdate
-	(*Kline)(nil),            // 7: price.Kline
-	(*KlinesResponse)(nil),   // 8: price.KlinesResponse
+	(*GetPriceRequest)(nil),      // 0: price.GetPriceRequest
+	(*GetPricesRequest)(nil),     // 1: price.GetPricesRequest
+	(*SubscribeRequest)(nil),     // 2: price.SubscribeRequest
+	(*GetKlinesRequest)(nil),     // 3: price.GetKlinesRequest
+	(*GetStatisticsRequest)(nil), // 4: price.GetStatisticsRequest
+	(*IndicatorSpec)(nil),        // 5: price.IndicatorSpec
+	(*GetIndicatorsRequest)(nil), // 6: price.GetIndicatorsRequest
+	(*GetTicksRequest)(nil),      // 7: price.GetTicksRequest
+	(*PriceResponse)(nil),        // 8: price.PriceResponse
+	(*PricesResponse)(nil),       // 9: price.PricesResponse
+	(*PriceUpdate)(nil),          // 10: price.PriceUpdate
+	(*Kline)(nil),                // 11: price.Kline
+	(*KlinesResponse)(nil),       // 12: price.KlinesResponse
+	(*StatisticsResponse)(nil),   // 13: price.StatisticsResponse
+	(*IndicatorPoint)(nil),       // 14: price.IndicatorPoint
+	(*IndicatorLine)(nil),        // 15: price.IndicatorLine
+	(*IndicatorSeries)(nil),      // 16: price.IndicatorSeries
+	(*IndicatorsResponse)(nil),   // 17: price.IndicatorsResponse
+	(*Tick)(nil),                 // 18: price.Tick
+	(*TicksResponse)(nil),        // 19: price.TicksResponse
 }
 var file_proto_price_proto_depIdxs = []int32{
-	4, // 0: price.PricesResponse.prices:type_name -> price.PriceResponse
-	7, // 1: price.KlinesResponse.klines:type_name -> price.Kline
-	0, // 2: price.PriceService.GetCurrentPrice:input_type -> price.GetPriceRequest
-	1, // 3: price.PriceService.GetCurrentPrices:input_type -> price.GetPricesRequest
-	2, // 4: price.PriceService.SubscribePrices:input_type -> price.SubscribeRequest
-	3, // 5: price.PriceService.GetKlines:input_type -> price.GetKlinesRequest
-	4, // 6: price.PriceService.GetCurrentPrice:output_type -> price.PriceResponse
-	5, // 7: price.PriceService.GetCurrentPrices:output_type -> price.PricesResponse
-	6, // 8: price.PriceService.SubscribePrices:output_type -> price.PriceUpdate
-	8, // 9: price.PriceService.GetKlines:output_type -> price.KlinesResponse
-	6, // [6:10] is the sub-list for method output_type
-	2, // [2:6] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	5,  // 0: price.GetIndicatorsRequest.indicators:type_name -> price.IndicatorSpec
+	8,  // 1: price.PricesResponse.prices:type_name -> price.PriceResponse
+	11, // 2: price.KlinesResponse.klines:type_name -> price.Kline
+	14, // 3: price.IndicatorLine.points:type_name -> price.IndicatorPoint
+	15, // 4: price.IndicatorSeries.lines:type_name -> price.IndicatorLine
+	16, // 5: price.IndicatorsResponse.indicators:type_name -> price.IndicatorSeries
+	18, // 6: price.TicksResponse.ticks:type_name -> price.Tick
+	0,  // 7: price.PriceService.GetCurrentPrice:input_type -> price.GetPriceRequest
+	1,  // 8: price.PriceService.GetCurrentPrices:input_type -> price.GetPricesRequest
+	2,  // 9: price.PriceService.SubscribePrices:input_type -> price.SubscribeRequest
+	3,  // 10: price.PriceService.GetKlines:input_type -> price.GetKlinesRequest
+	4,  // 11: price.PriceService.GetStatistics:input_type -> price.GetStatisticsRequest
+	6,  // 12: price.PriceService.GetIndicators:input_type -> price.GetIndicatorsRequest
+	7,  // 13: price.PriceService.GetTicks:input_type -> price.GetTicksRequest
+	8,  // 14: price.PriceService.GetCurrentPrice:output_type -> price.PriceResponse
+	9,  // 15: price.PriceService.GetCurrentPrices:output_type -> price.PricesResponse
+	10, // 16: price.PriceService.SubscribePrices:output_type -> price.PriceUpdate
+	12, // 17: price.PriceService.GetKlines:output_type -> price.KlinesResponse
+	13, // 18: price.PriceService.GetStatistics:output_type -> price.StatisticsResponse
+	17, // 19: price.PriceService.GetIndicators:output_type -> price.IndicatorsResponse
+	19, // 20: price.PriceService.GetTicks:output_type -> price.TicksResponse
+	14, // [14:21] is the sub-list for method output_type
+	7,  // [7:14] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_proto_price_proto_init() }
@@ -680,7 +1722,7 @@ func file_proto_price_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_price_proto_rawDesc), len(file_proto_price_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
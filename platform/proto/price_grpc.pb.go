@@ -23,6 +23,9 @@ const (
 	PriceService_GetCurrentPrices_FullMethodName = "/price.PriceService/GetCurrentPrices"
 	PriceService_SubscribePrices_FullMethodName  = "/price.PriceService/SubscribePrices"
 	PriceService_GetKlines_FullMethodName        = "/price.PriceService/GetKlines"
+	PriceService_GetStatistics_FullMethodName    = "/price.PriceService/GetStatistics"
+	PriceService_GetIndicators_FullMethodName    = "/price.PriceService/GetIndicators"
+	PriceService_GetTicks_FullMethodName         = "/price.PriceService/GetTicks"
 )
 
 // PriceServiceClient is the client API for PriceService service.
@@ -39,6 +42,12 @@ type PriceServiceClient interface {
 	SubscribePrices(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceUpdate], error)
 	// 獲取 K 線資料
 	GetKlines(ctx context.Context, in *GetKlinesRequest, opts ...grpc.CallOption) (*KlinesResponse, error)
+	// 獲取市場統計（開高低收、VWAP、TWAP、已實現波動率）
+	GetStatistics(ctx context.Context, in *GetStatisticsRequest, opts ...grpc.CallOption) (*StatisticsResponse, error)
+	// 獲取技術指標（SMA、EMA、布林通道、RSI、MACD）
+	GetIndicators(ctx context.Context, in *GetIndicatorsRequest, opts ...grpc.CallOption) (*IndicatorsResponse, error)
+	// 分頁獲取原始 tick（每 333ms 一筆），單次查詢範圍最多 1 小時
+	GetTicks(ctx context.Context, in *GetTicksRequest, opts ...grpc.CallOption) (*TicksResponse, error)
 }
 
 type priceServiceClient struct {
@@ -98,6 +107,36 @@ func (c *priceServiceClient) GetKlines(ctx context.Context, in *GetKlinesRequest
 	return out, nil
 }
 
+func (c *priceServiceClient) GetStatistics(ctx context.Context, in *GetStatisticsRequest, opts ...grpc.CallOption) (*StatisticsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatisticsResponse)
+	err := c.cc.Invoke(ctx, PriceService_GetStatistics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceServiceClient) GetIndicators(ctx context.Context, in *GetIndicatorsRequest, opts ...grpc.CallOption) (*IndicatorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IndicatorsResponse)
+	err := c.cc.Invoke(ctx, PriceService_GetIndicators_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceServiceClient) GetTicks(ctx context.Context, in *GetTicksRequest, opts ...grpc.CallOption) (*TicksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TicksResponse)
+	err := c.cc.Invoke(ctx, PriceService_GetTicks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PriceServiceServer is the server API for PriceService service.
 // All implementations must embed UnimplementedPriceServiceServer
 // for forward compatibility.
@@ -112,6 +151,12 @@ type PriceServiceServer interface {
 	SubscribePrices(*SubscribeRequest, grpc.ServerStreamingServer[PriceUpdate]) error
 	// 獲取 K 線資料
 	GetKlines(context.Context, *GetKlinesRequest) (*KlinesResponse, error)
+	// 獲取市場統計（開高低收、VWAP、TWAP、已實現波動率）
+	GetStatistics(context.Context, *GetStatisticsRequest) (*StatisticsResponse, error)
+	// 獲取技術指標（SMA、EMA、布林通道、RSI、MACD）
+	GetIndicators(context.Context, *GetIndicatorsRequest) (*IndicatorsResponse, error)
+	// 分頁獲取原始 tick（每 333ms 一筆），單次查詢範圍最多 1 小時
+	GetTicks(context.Context, *GetTicksRequest) (*TicksResponse, error)
 	mustEmbedUnimplementedPriceServiceServer()
 }
 
@@ -134,6 +179,15 @@ func (UnimplementedPriceServiceServer) SubscribePrices(*SubscribeRequest, grpc.S
 func (UnimplementedPriceServiceServer) GetKlines(context.Context, *GetKlinesRequest) (*KlinesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetKlines not implemented")
 }
+func (UnimplementedPriceServiceServer) GetStatistics(context.Context, *GetStatisticsRequest) (*StatisticsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatistics not implemented")
+}
+func (UnimplementedPriceServiceServer) GetIndicators(context.Context, *GetIndicatorsRequest) (*IndicatorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIndicators not implemented")
+}
+func (UnimplementedPriceServiceServer) GetTicks(context.Context, *GetTicksRequest) (*TicksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicks not implemented")
+}
 func (UnimplementedPriceServiceServer) mustEmbedUnimplementedPriceServiceServer() {}
 func (UnimplementedPriceServiceServer) testEmbeddedByValue()                      {}
 
@@ -220,6 +274,60 @@ func _PriceService_GetKlines_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PriceService_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatisticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceServiceServer).GetStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceService_GetStatistics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceServiceServer).GetStatistics(ctx, req.(*GetStatisticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceService_GetIndicators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIndicatorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceServiceServer).GetIndicators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceService_GetIndicators_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceServiceServer).GetIndicators(ctx, req.(*GetIndicatorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceService_GetTicks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTicksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceServiceServer).GetTicks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceService_GetTicks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceServiceServer).GetTicks(ctx, req.(*GetTicksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PriceService_ServiceDesc is the grpc.ServiceDesc for PriceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -239,6 +347,18 @@ var PriceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetKlines",
 			Handler:    _PriceService_GetKlines_Handler,
 		},
+		{
+			MethodName: "GetStatistics",
+			Handler:    _PriceService_GetStatistics_Handler,
+		},
+		{
+			MethodName: "GetIndicators",
+			Handler:    _PriceService_GetIndicators_Handler,
+		},
+		{
+			MethodName: "GetTicks",
+			Handler:    _PriceService_GetTicks_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
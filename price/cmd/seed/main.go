@@ -9,6 +9,7 @@ import (
 
 	"golden-buy/price/internal/model"
 	"golden-buy/price/internal/repository"
+	"golden-buy/price/internal/simulator"
 )
 
 const (
@@ -31,7 +32,7 @@ func main() {
 	log.Println("🌱 開始生成歷史數據...")
 
 	// 連接 InfluxDB
-	repo, err := repository.NewInfluxDBRepository(influxURL, influxToken, influxOrg, influxBucket)
+	repo, err := repository.NewInfluxDBRepository(influxURL, influxToken, influxOrg, influxBucket, repository.DefaultQueryOptions)
 	if err != nil {
 		log.Fatalf("連接 InfluxDB 失敗: %v", err)
 	}
@@ -94,6 +95,7 @@ func main() {
 				Timestamp:     currentTime,
 				Change:        change,
 				ChangePercent: changePercentValue,
+				Volume:        simulator.RandomVolume(),
 			}
 
 			// 寫入 InfluxDB
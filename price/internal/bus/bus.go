@@ -0,0 +1,40 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"golden-buy/price/internal/codec"
+	"golden-buy/price/internal/model"
+)
+
+// 支援的訊息匯流排
+const (
+	KindRedis = "redis" // Redis Pub/Sub 或 Stream
+	KindNATS  = "nats"  // NATS，可選用 JetStream
+)
+
+// Publisher 價格事件發布者
+type Publisher interface {
+	Publish(ctx context.Context, price *model.Price) error
+	Close() error
+}
+
+// Options 訊息匯流排設定，只需填寫所選匯流排的部分
+type Options struct {
+	Codec *codec.Codec // 價格事件的編碼方式
+	Redis RedisOptions
+	NATS  NATSOptions
+}
+
+// NewPublisher 依 kind 創建價格事件發布者
+func NewPublisher(kind string, opts Options) (Publisher, error) {
+	switch kind {
+	case KindRedis:
+		return NewRedisPublisher(opts.Codec, opts.Redis)
+	case KindNATS:
+		return NewNATSPublisher(opts.Codec, opts.NATS)
+	default:
+		return nil, fmt.Errorf("不支援的訊息匯流排: %s", kind)
+	}
+}
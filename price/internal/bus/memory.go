@@ -0,0 +1,60 @@
+package bus
+
+import (
+	"context"
+	"sync"
+
+	"golden-buy/price/internal/model"
+)
+
+// Memory 行程內訊息匯流排
+// Publish 會同步調用所有訂閱者的 handler，只用於測試：Platform 在另一個行程，收不到行程內發布的價格，
+// 因此不能以 MESSAGE_BUS 選用
+type Memory struct {
+	mu       sync.RWMutex
+	handlers map[int]func(*model.Price)
+	nextID   int
+}
+
+// NewMemory 創建行程內訊息匯流排
+func NewMemory() *Memory {
+	return &Memory{
+		handlers: make(map[int]func(*model.Price)),
+	}
+}
+
+// Publish 發布價格事件給所有訂閱者
+func (m *Memory) Publish(ctx context.Context, price *model.Price) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, handler := range m.handlers {
+		p := *price // 每個訂閱者收到獨立的副本
+		handler(&p)
+	}
+
+	return nil
+}
+
+// Subscribe 註冊 handler，阻塞直到 ctx 取消
+func (m *Memory) Subscribe(ctx context.Context, handler func(*model.Price)) error {
+	m.mu.Lock()
+	id := m.nextID
+	m.nextID++
+	m.handlers[id] = handler
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.handlers, id)
+		m.mu.Unlock()
+	}()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Close 行程內匯流排沒有需要關閉的連線
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,98 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golden-buy/price/internal/codec"
+	"golden-buy/price/internal/model"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsStreamMaxAge JetStream stream 保留訊息的時間，需與 Platform Service 一致
+const natsStreamMaxAge = time.Hour
+
+// NATSOptions NATS 發布者設定
+type NATSOptions struct {
+	URL       string // 伺服器位址，多個以逗號分隔
+	Subject   string // 價格更新 subject
+	JetStream bool   // 發布到 JetStream，訂閱者可持久化讀取並 ack
+	Stream    string // JetStream stream 名稱
+}
+
+// NATSPublisher 透過 NATS（可選 JetStream）發布價格事件
+type NATSPublisher struct {
+	conn  *nats.Conn
+	js    jetstream.JetStream // 未啟用 JetStream 時為 nil
+	codec *codec.Codec
+	opts  NATSOptions
+}
+
+// NewNATSPublisher 創建 NATS 發布者，啟用 JetStream 時建立或更新 stream
+func NewNATSPublisher(c *codec.Codec, opts NATSOptions) (*NATSPublisher, error) {
+	conn, err := nats.Connect(opts.URL,
+		nats.Name("golden-buy-price"),
+		nats.Timeout(5*time.Second),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("連接 NATS 失敗: %w", err)
+	}
+
+	p := &NATSPublisher{
+		conn:  conn,
+		codec: c,
+		opts:  opts,
+	}
+
+	if opts.JetStream {
+		js, err := jetstream.New(conn)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("創建 JetStream 失敗: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:     opts.Stream,
+			Subjects: []string{opts.Subject},
+			MaxAge:   natsStreamMaxAge,
+		}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("建立 JetStream stream %s 失敗: %w", opts.Stream, err)
+		}
+
+		p.js = js
+	}
+
+	return p, nil
+}
+
+// Publish 發布價格更新，JetStream 模式下等待伺服器確認寫入
+func (p *NATSPublisher) Publish(ctx context.Context, price *model.Price) error {
+	data, err := p.codec.Encode(price)
+	if err != nil {
+		return err
+	}
+
+	if p.js != nil {
+		_, err := p.js.Publish(ctx, p.opts.Subject, data)
+		return err
+	}
+
+	return p.conn.Publish(p.opts.Subject, data)
+}
+
+// Close 送出緩衝中的訊息後關閉連接
+func (p *NATSPublisher) Close() error {
+	if err := p.conn.Drain(); err != nil {
+		p.conn.Close()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+
+	"golden-buy/price/internal/codec"
+	"golden-buy/price/internal/model"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// PriceUpdatesChannel Redis Pub/Sub 彙總頻道名稱，包含所有商品（需開啟 AggregateChannel）
+	PriceUpdatesChannel = "price:updates"
+
+	// PriceUpdatesChannelPrefix 每個商品的 Pub/Sub 頻道前綴，完整格式為 price:updates:{SYMBOL}
+	PriceUpdatesChannelPrefix = "price:updates:"
+
+	// PriceUpdatesStream Redis Stream key，使用 stream 傳輸時價格更新寫入此 Stream
+	PriceUpdatesStream = "price:updates:stream"
+)
+
+// 價格更新的傳輸方式
+const (
+	// TransportPubSub Redis Pub/Sub，訂閱者離線期間的訊息會遺失
+	TransportPubSub = "pubsub"
+	// TransportStream Redis Stream，訂閱者以 consumer group 讀取並 ack，重啟後從上次處理的位置繼續
+	TransportStream = "stream"
+)
+
+// RedisOptions Redis 發布者設定
+type RedisOptions struct {
+	Addr         string
+	Password     string
+	DB           int
+	Transport    string // pubsub 或 stream
+	StreamMaxLen int64  // Stream 保留的最大訊息數（近似修剪）
+
+	AggregateChannel bool // pubsub 傳輸時同時發布到彙總頻道 price:updates，供尚未改用商品頻道的訂閱者使用
+}
+
+// RedisPublisher 透過 Redis Pub/Sub 或 Stream 發布價格事件
+type RedisPublisher struct {
+	client *redis.Client
+	codec  *codec.Codec
+	opts   RedisOptions
+}
+
+// NewRedisPublisher 創建 Redis 發布者
+func NewRedisPublisher(c *codec.Codec, opts RedisOptions) (*RedisPublisher, error) {
+	if opts.Transport != TransportPubSub && opts.Transport != TransportStream {
+		return nil, fmt.Errorf("不支援的傳輸方式: %s", opts.Transport)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     opts.Addr,
+		Password: opts.Password,
+		DB:       opts.DB,
+	})
+
+	// 測試連接
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &RedisPublisher{
+		client: client,
+		codec:  c,
+		opts:   opts,
+	}, nil
+}
+
+// Publish 發布價格更新
+func (p *RedisPublisher) Publish(ctx context.Context, price *model.Price) error {
+	data, err := p.codec.Encode(price)
+	if err != nil {
+		return err
+	}
+
+	if p.opts.Transport == TransportStream {
+		// 寫入 Stream，近似修剪到 StreamMaxLen 筆
+		return p.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: PriceUpdatesStream,
+			MaxLen: p.opts.StreamMaxLen,
+			Approx: true,
+			Values: map[string]interface{}{"data": data},
+		}).Err()
+	}
+
+	// 發布到商品頻道，需要時同時發布到彙總頻道
+	if !p.opts.AggregateChannel {
+		return p.client.Publish(ctx, PriceUpdatesChannelPrefix+string(price.Symbol), data).Err()
+	}
+
+	pipe := p.client.Pipeline()
+	pipe.Publish(ctx, PriceUpdatesChannelPrefix+string(price.Symbol), data)
+	pipe.Publish(ctx, PriceUpdatesChannel, data)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Close 關閉連接
+func (p *RedisPublisher) Close() error {
+	return p.client.Close()
+}
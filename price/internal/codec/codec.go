@@ -0,0 +1,155 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golden-buy/price/internal/model"
+	pb "golden-buy/price/proto"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// SchemaVersion 目前的價格傳輸格式版本
+// 版本 0 為舊版無版本的 JSON（僅包含 symbol、price、timestamp、change、change_percent）
+// 版本 2 新增 heartbeat 心跳訊息
+const SchemaVersion = 2
+
+// 價格事件的編碼方式
+const (
+	EncodingProtobuf = "protobuf" // pb.PriceUpdate 二進位格式
+	EncodingJSON     = "json"     // 與 pb.PriceUpdate 欄位相同的 JSON，供除錯和舊版訂閱者使用
+)
+
+// ErrInvalidPayload 無法解析或缺少必要欄位的價格資料
+var ErrInvalidPayload = errors.New("invalid price payload")
+
+// Meta 價格事件的傳輸資訊
+type Meta struct {
+	SchemaVersion int32
+	SourceID      string
+}
+
+// jsonPayload JSON 編碼的價格事件，欄位名稱與 pb.PriceUpdate 的 proto 欄位名稱一致
+// 時間戳使用 JSON 數字（Unix 毫秒），與舊版格式相容
+type jsonPayload struct {
+	SchemaVersion int32   `json:"schema_version,omitempty"`
+	SourceID      string  `json:"source_id,omitempty"`
+	Symbol        string  `json:"symbol"`
+	Price         float64 `json:"price"`
+	Timestamp     int64   `json:"timestamp"` // Unix 毫秒
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	Heartbeat     bool    `json:"heartbeat,omitempty"`
+	Volume        float64 `json:"volume,omitempty"`
+}
+
+// Codec 價格事件編碼器
+type Codec struct {
+	encoding string
+	sourceID string
+}
+
+// New 創建編碼器
+func New(encoding, sourceID string) (*Codec, error) {
+	if encoding != EncodingProtobuf && encoding != EncodingJSON {
+		return nil, fmt.Errorf("不支援的編碼方式: %s", encoding)
+	}
+
+	return &Codec{
+		encoding: encoding,
+		sourceID: sourceID,
+	}, nil
+}
+
+// Encode 將價格編碼為目前版本的傳輸格式
+func (c *Codec) Encode(price *model.Price) ([]byte, error) {
+	update := &pb.PriceUpdate{
+		Symbol:        string(price.Symbol),
+		Price:         price.Price,
+		Timestamp:     price.Timestamp.UnixMilli(),
+		Change:        price.Change,
+		ChangePercent: price.ChangePercent,
+		SchemaVersion: SchemaVersion,
+		SourceId:      c.sourceID,
+		Heartbeat:     price.Heartbeat,
+		Volume:        price.Volume,
+	}
+
+	if c.encoding == EncodingJSON {
+		return json.Marshal(jsonPayload{
+			SchemaVersion: update.SchemaVersion,
+			SourceID:      update.SourceId,
+			Symbol:        update.Symbol,
+			Price:         update.Price,
+			Timestamp:     update.Timestamp,
+			Change:        update.Change,
+			ChangePercent: update.ChangePercent,
+			Heartbeat:     update.Heartbeat,
+			Volume:        update.Volume,
+		})
+	}
+
+	return proto.Marshal(update)
+}
+
+// Decode 解析價格事件，自動辨識 protobuf 和 JSON（包含舊版無版本的 JSON）
+// 較新版本的資料只讀取已知欄位，未知欄位直接忽略；缺少商品代碼或時間戳時返回 ErrInvalidPayload
+func Decode(data []byte) (*model.Price, Meta, error) {
+	update, err := decodeUpdate(data)
+	if err != nil {
+		return nil, Meta{}, err
+	}
+
+	if update.Symbol == "" || update.Timestamp <= 0 {
+		return nil, Meta{}, fmt.Errorf("%w: missing symbol or timestamp", ErrInvalidPayload)
+	}
+
+	price := &model.Price{
+		Symbol:        model.Symbol(update.Symbol),
+		Price:         update.Price,
+		Timestamp:     time.UnixMilli(update.Timestamp),
+		Change:        update.Change,
+		ChangePercent: update.ChangePercent,
+		Heartbeat:     update.Heartbeat,
+		Volume:        update.Volume,
+	}
+
+	return price, Meta{SchemaVersion: update.SchemaVersion, SourceID: update.SourceId}, nil
+}
+
+// decodeUpdate 依第一個位元組判斷編碼方式
+// protobuf 的 PriceUpdate 不可能以 '{' 開頭（對應未使用的 group 欄位 15）
+func decodeUpdate(data []byte) (*pb.PriceUpdate, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidPayload)
+	}
+
+	if data[0] == '{' {
+		var payload jsonPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+
+		return &pb.PriceUpdate{
+			Symbol:        payload.Symbol,
+			Price:         payload.Price,
+			Timestamp:     payload.Timestamp,
+			Change:        payload.Change,
+			ChangePercent: payload.ChangePercent,
+			SchemaVersion: payload.SchemaVersion,
+			SourceId:      payload.SourceID,
+			Heartbeat:     payload.Heartbeat,
+			Volume:        payload.Volume,
+		}, nil
+	}
+
+	var update pb.PriceUpdate
+	if err := proto.Unmarshal(data, &update); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	return &update, nil
+}
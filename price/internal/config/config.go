@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -10,30 +12,65 @@ type Config struct {
 	// InfluxDB 配置
 	InfluxDB InfluxDBConfig
 
+	// 訊息匯流排配置
+	Bus BusConfig
+
 	// Redis 配置
 	Redis RedisConfig
 
+	// NATS 配置
+	NATS NATSConfig
+
 	// gRPC 配置
 	GRPC GRPCConfig
 
 	// 模擬器配置
 	Simulator SimulatorConfig
 
+	// K 線降採樣配置
+	Downsample DownsampleConfig
+
+	// 領導者選舉配置
+	Election ElectionConfig
+
 	// 日誌配置
 	LogLevel string
 }
 
 type InfluxDBConfig struct {
-	URL    string
-	Token  string
-	Org    string
-	Bucket string
+	URL          string
+	Token        string
+	Org          string
+	Bucket       string
+	QueryTimeout time.Duration // 單次查詢逾時
+	MaxPoints    int           // 單次查詢最多涵蓋的資料點（K 線根數）
+	MaxRawRange  time.Duration // 單次查詢最多掃描的原始 tick 時間範圍
+	ServerParams bool          // 使用伺服器端參數化查詢（僅 InfluxDB Cloud 支援）
 }
 
 type RedisConfig struct {
-	Addr     string
-	Password string
-	DB       int
+	Addr          string
+	Password      string
+	DB            int
+	TickRetention time.Duration // 每個商品 tick Sorted Set 保留的時間範圍
+	Transport     string        // 價格更新傳輸方式：pubsub 或 stream
+	StreamMaxLen  int64         // stream 傳輸時 Stream 保留的最大訊息數
+	Aggregate     bool          // pubsub 傳輸時同時發布到彙總頻道 price:updates
+}
+
+type BusConfig struct {
+	Kind     string // 價格事件的訊息匯流排：redis 或 nats
+	Encoding string // 價格事件、快取和 tick 的編碼方式：protobuf 或 json
+	SourceID string // 寫入價格事件的來源 ID，預設為主機名稱
+
+	HeartbeatInterval time.Duration // 每個商品發布心跳訊息的間隔，0 表示不發布
+}
+
+type NATSConfig struct {
+	URL       string
+	Subject   string // 價格更新 subject
+	JetStream bool   // 是否發布到 JetStream
+	Stream    string // JetStream stream 名稱
 }
 
 type GRPCConfig struct {
@@ -45,19 +82,52 @@ type SimulatorConfig struct {
 	Volatility float64
 }
 
+type DownsampleConfig struct {
+	Enabled  bool
+	Period   time.Duration // 降採樣執行間隔
+	Backfill time.Duration // 啟動時回補的最大範圍
+}
+
+type ElectionConfig struct {
+	Enabled       bool
+	LeaseTTL      time.Duration // 領導者租約期限
+	RenewInterval time.Duration // 續約和競選間隔
+}
+
 // Load 從環境變量載入配置
 func Load() *Config {
 	return &Config{
 		InfluxDB: InfluxDBConfig{
-			URL:    getEnv("INFLUXDB_URL", "http://localhost:8086"),
-			Token:  getEnv("INFLUXDB_TOKEN", "my-super-secret-auth-token"),
-			Org:    getEnv("INFLUXDB_ORG", "golden-buy"),
-			Bucket: getEnv("INFLUXDB_BUCKET", "golden_buy"),
+			URL:          getEnv("INFLUXDB_URL", "http://localhost:8086"),
+			Token:        getEnv("INFLUXDB_TOKEN", "my-super-secret-auth-token"),
+			Org:          getEnv("INFLUXDB_ORG", "golden-buy"),
+			Bucket:       getEnv("INFLUXDB_BUCKET", "golden_buy"),
+			QueryTimeout: parseDuration(getEnv("INFLUXDB_QUERY_TIMEOUT", "10s")),
+			MaxPoints:    parseInt(getEnv("INFLUXDB_MAX_POINTS", "100000"), 100000),
+			MaxRawRange:  parseDuration(getEnv("INFLUXDB_MAX_RAW_RANGE", "8784h")),
+			ServerParams: parseBool(getEnv("INFLUXDB_SERVER_PARAMS", "false")),
+		},
+		Bus: BusConfig{
+			Kind:     getEnv("MESSAGE_BUS", "redis"),
+			Encoding: getEnv("PAYLOAD_ENCODING", "protobuf"),
+			SourceID: getEnv("SOURCE_ID", defaultSourceID()),
+
+			HeartbeatInterval: parseDuration(getEnv("HEARTBEAT_INTERVAL", "1s")),
 		},
 		Redis: RedisConfig{
-			Addr:     getEnv("REDIS_ADDR", "localhost:6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       0,
+			Addr:          getEnv("REDIS_ADDR", "localhost:6379"),
+			Password:      getEnv("REDIS_PASSWORD", ""),
+			DB:            0,
+			TickRetention: parseDuration(getEnv("REDIS_TICK_RETENTION", "10m")),
+			Transport:     getEnv("REDIS_TRANSPORT", "pubsub"),
+			StreamMaxLen:  int64(parseInt(getEnv("REDIS_STREAM_MAXLEN", "100000"), 100000)),
+			Aggregate:     parseBool(getEnv("REDIS_AGGREGATE_CHANNEL", "false")),
+		},
+		NATS: NATSConfig{
+			URL:       getEnv("NATS_URL", "nats://localhost:4222"),
+			Subject:   getEnv("NATS_SUBJECT", "price.updates"),
+			JetStream: parseBool(getEnv("NATS_JETSTREAM", "false")),
+			Stream:    getEnv("NATS_STREAM", "PRICES"),
 		},
 		GRPC: GRPCConfig{
 			Port: getEnv("GRPC_PORT", "50051"),
@@ -66,10 +136,38 @@ func Load() *Config {
 			Interval:   parseDuration(getEnv("SIMULATOR_INTERVAL", "1s")),
 			Volatility: 0.01, // 1% 波動率
 		},
+		Downsample: DownsampleConfig{
+			Enabled:  parseBool(getEnv("DOWNSAMPLE_ENABLED", "true")),
+			Period:   parseDuration(getEnv("DOWNSAMPLE_PERIOD", "10s")),
+			Backfill: parseDuration(getEnv("DOWNSAMPLE_BACKFILL", "168h")),
+		},
+		Election: ElectionConfig{
+			Enabled:       parseBool(getEnv("LEADER_ELECTION", "true")),
+			LeaseTTL:      parseDuration(getEnv("LEADER_LEASE_TTL", "10s")),
+			RenewInterval: parseDuration(getEnv("LEADER_RENEW_INTERVAL", "3s")),
+		},
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 	}
 }
 
+// Validate 驗證配置
+func (c *Config) Validate() error {
+	// 行程內匯流排只用於測試：Platform 是另一個行程，發布的價格不會送達任何訂閱者
+	if c.Bus.Kind != "redis" && c.Bus.Kind != "nats" {
+		return fmt.Errorf("MESSAGE_BUS 必須是 redis 或 nats，目前為: %s", c.Bus.Kind)
+	}
+
+	return nil
+}
+
+// defaultSourceID 預設來源 ID，使用主機名稱（容器重啟後不變）
+func defaultSourceID() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "price"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -85,3 +183,21 @@ func parseDuration(s string) time.Duration {
 	}
 	return d
 }
+
+func parseBool(s string) bool {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		log.Printf("解析布林值失敗，使用預設值: %v", err)
+		return false
+	}
+	return b
+}
+
+func parseInt(s string, defaultValue int) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		log.Printf("解析整數失敗，使用預設值: %v", err)
+		return defaultValue
+	}
+	return i
+}
@@ -0,0 +1,23 @@
+package config
+
+import "testing"
+
+func TestValidateMessageBus(t *testing.T) {
+	tests := []struct {
+		kind    string
+		wantErr bool
+	}{
+		{kind: "redis"},
+		{kind: "nats"},
+		{kind: "memory", wantErr: true}, // 行程內匯流排不會送達 Platform
+		{kind: "kafka", wantErr: true},
+		{kind: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		cfg := &Config{Bus: BusConfig{Kind: tt.kind}}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with MESSAGE_BUS=%q error = %v, wantErr %v", tt.kind, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package downsampler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"golden-buy/price/internal/model"
+	"golden-buy/price/internal/repository"
+)
+
+// settleDelay 等待最後幾筆 tick 寫入完成後才視為 K 線已收盤
+const settleDelay = 2 * time.Second
+
+// KlineDownsampler K 線降採樣器
+// 定期將已收盤的 K 線從原始 tick 聚合後寫入 klines_<interval> measurement
+type KlineDownsampler struct {
+	repo      *repository.InfluxDBRepository
+	intervals []model.Interval
+	period    time.Duration
+	backfill  time.Duration
+}
+
+// NewKlineDownsampler 創建 K 線降採樣器
+func NewKlineDownsampler(repo *repository.InfluxDBRepository, period, backfill time.Duration) *KlineDownsampler {
+	return &KlineDownsampler{
+		repo:      repo,
+		intervals: model.StoredIntervals,
+		period:    period,
+		backfill:  backfill,
+	}
+}
+
+// Start 啟動降採樣器
+func (d *KlineDownsampler) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.period)
+	defer ticker.Stop()
+
+	log.Printf("K 線降採樣器已啟動，間隔: %s，回補範圍: %s", d.period, d.backfill)
+
+	// 啟動時先執行一次，回補停機期間的 K 線
+	d.runOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("K 線降採樣器停止")
+			return
+		case <-ticker.C:
+			d.runOnce(ctx)
+		}
+	}
+}
+
+// runOnce 對所有商品和時間週期執行一次降採樣
+func (d *KlineDownsampler) runOnce(ctx context.Context) {
+	now := time.Now()
+
+	for _, symbol := range model.AllSymbols {
+		for _, interval := range d.intervals {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err := d.downsample(ctx, symbol, interval, now); err != nil {
+				log.Printf("降採樣 %s %s 失敗: %v", symbol, interval, err)
+			}
+		}
+	}
+}
+
+// downsample 聚合並寫入指定商品和時間週期尚未存儲的已收盤 K 線
+func (d *KlineDownsampler) downsample(ctx context.Context, symbol model.Symbol, interval model.Interval, now time.Time) error {
+	step := interval.Duration()
+
+	// 只處理已收盤的 K 線
+	end := now.Add(-settleDelay).Truncate(step)
+
+	last, err := d.repo.GetLastStoredKlineTime(ctx, symbol, string(interval), d.backfill)
+	if err != nil {
+		return err
+	}
+
+	start := now.Add(-d.backfill).Truncate(step)
+	if !last.IsZero() {
+		start = last.Add(step)
+	}
+
+	if !start.Before(end) {
+		return nil
+	}
+
+	klines, err := d.repo.AggregateKlines(ctx, symbol, string(interval), start.UnixMilli(), end.UnixMilli(), false, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := d.repo.WriteKlines(ctx, symbol, string(interval), klines); err != nil {
+		return err
+	}
+
+	if len(klines) > 0 {
+		log.Printf("降採樣 %s %s: 寫入 %d 根 K 線", symbol, interval, len(klines))
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package election
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LeaderKey 領導者租約的 Redis key，值為目前領導者的 ID
+const LeaderKey = "price:leader"
+
+// renewScript 只有仍持有租約時才延長期限
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript 只有仍持有租約時才刪除，避免刪掉其他實例剛取得的租約
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Options 領導者選舉設定
+type Options struct {
+	ID            string        // 本實例的唯一 ID
+	LeaseTTL      time.Duration // 租約期限，領導者停止續約後其他實例最多等待此時間接手
+	RenewInterval time.Duration // 續約和競選的間隔，需小於 LeaseTTL
+}
+
+// Elector 以 Redis 鎖實作的領導者選舉
+// 同一時間只有一個實例持有租約，持有者定期續約；續約失敗時立即卸任，由其他實例在租約到期後接手
+type Elector struct {
+	client *redis.Client
+	opts   Options
+	leader atomic.Bool
+}
+
+// NewElector 創建領導者選舉
+func NewElector(addr, password string, db int, opts Options) (*Elector, error) {
+	if opts.RenewInterval <= 0 || opts.RenewInterval >= opts.LeaseTTL {
+		return nil, fmt.Errorf("續約間隔 %s 必須大於 0 且小於租約期限 %s", opts.RenewInterval, opts.LeaseTTL)
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	// 測試連接
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &Elector{
+		client: client,
+		opts:   opts,
+	}, nil
+}
+
+// Run 參與選舉直到 ctx 取消
+// 擔任領導者期間以 lead 的 context 執行 lead，其餘時間執行 follow；角色切換時先等待前一個角色的函式返回
+func (e *Elector) Run(ctx context.Context, lead, follow func(context.Context)) {
+	log.Printf("領導者選舉已啟動，ID: %s，租約: %s，續約間隔: %s", e.opts.ID, e.opts.LeaseTTL, e.opts.RenewInterval)
+
+	for ctx.Err() == nil {
+		// 跟隨者：持續競選直到取得租約
+		stop := runRole(ctx, follow)
+		acquired := e.acquire(ctx)
+		stop()
+		if !acquired {
+			return
+		}
+
+		// 領導者：持續續約直到失去租約或 ctx 取消
+		e.leader.Store(true)
+		log.Printf("👑 成為領導者: %s", e.opts.ID)
+
+		stop = runRole(ctx, lead)
+		e.hold(ctx)
+		stop()
+
+		e.leader.Store(false)
+		e.release()
+		log.Printf("卸任領導者: %s", e.opts.ID)
+	}
+}
+
+// IsLeader 目前是否為領導者
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Close 關閉連接
+func (e *Elector) Close() error {
+	return e.client.Close()
+}
+
+// acquire 每個續約間隔嘗試取得租約，取得時返回 true，ctx 取消時返回 false
+func (e *Elector) acquire(ctx context.Context) bool {
+	ticker := time.NewTicker(e.opts.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := e.client.SetNX(ctx, LeaderKey, e.opts.ID, e.opts.LeaseTTL).Result()
+		if err != nil && ctx.Err() == nil {
+			log.Printf("競選領導者失敗: %v", err)
+		}
+		if ok {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// hold 定期續約，直到租約被其他實例取得、無法在期限內續約或 ctx 取消
+// 連線錯誤時在租約到期前一個續約間隔就卸任，避免與新的領導者同時發布
+func (e *Elector) hold(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.RenewInterval)
+	defer ticker.Stop()
+
+	lastRenew := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := renewScript.Run(ctx, e.client, []string{LeaderKey}, e.opts.ID, e.opts.LeaseTTL.Milliseconds()).Int()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("續約領導者租約失敗: %v", err)
+			if time.Since(lastRenew) >= e.opts.LeaseTTL-e.opts.RenewInterval {
+				log.Printf("⚠️  無法在租約期限內續約，卸任領導者")
+				return
+			}
+			continue
+		}
+
+		if renewed == 0 {
+			log.Printf("⚠️  領導者租約已被其他實例取得")
+			return
+		}
+		lastRenew = time.Now()
+	}
+}
+
+// release 釋放仍持有的租約，讓其他實例不必等到租約到期即可接手
+func (e *Elector) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := releaseScript.Run(ctx, e.client, []string{LeaderKey}, e.opts.ID).Err(); err != nil {
+		log.Printf("釋放領導者租約失敗: %v", err)
+	}
+}
+
+// runRole 在 goroutine 中執行角色函式，返回的 stop 會取消其 context 並等待返回
+func runRole(ctx context.Context, fn func(context.Context)) (stop func()) {
+	roleCtx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fn(roleCtx)
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
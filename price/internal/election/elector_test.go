@@ -0,0 +1,106 @@
+package election
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewElectorValidatesIntervals(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "zero renew interval", opts: Options{ID: "a", LeaseTTL: time.Second}},
+		{name: "renew interval equal to lease", opts: Options{ID: "a", LeaseTTL: time.Second, RenewInterval: time.Second}},
+		{name: "renew interval longer than lease", opts: Options{ID: "a", LeaseTTL: time.Second, RenewInterval: 2 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 設定錯誤時不應嘗試連接 Redis
+			if _, err := NewElector("127.0.0.1:0", "", 0, tt.opts); err == nil {
+				t.Fatal("NewElector() error = nil, want an interval error")
+			}
+		})
+	}
+}
+
+func TestRunRoleStopWaitsForReturn(t *testing.T) {
+	var returned atomic.Bool
+	stop := runRole(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		returned.Store(true)
+	})
+
+	stop()
+	if !returned.Load() {
+		t.Fatal("stop() returned before the role function")
+	}
+}
+
+// TestElectorFailover 需要 Redis，以 REDIS_TEST_ADDR 指定位址（會使用並清除 LeaderKey）
+func TestElectorFailover(t *testing.T) {
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR 未設定，略過需要 Redis 的測試")
+	}
+
+	newElector := func(id string) *Elector {
+		e, err := NewElector(addr, "", 0, Options{ID: id, LeaseTTL: 300 * time.Millisecond, RenewInterval: 50 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("NewElector(%s) error = %v", id, err)
+		}
+		t.Cleanup(func() { e.Close() })
+		return e
+	}
+	a, b := newElector("a"), newElector("b")
+	a.client.Del(context.Background(), LeaderKey)
+
+	var leaders atomic.Int32
+	lead := func(ctx context.Context) {
+		if leaders.Add(1) > 1 {
+			t.Error("two leaders at the same time")
+		}
+		<-ctx.Done()
+		leaders.Add(-1)
+	}
+	follow := func(ctx context.Context) { <-ctx.Done() }
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	doneA := make(chan struct{})
+	go func() {
+		a.Run(ctxA, lead, follow)
+		close(doneA)
+	}()
+	waitFor(t, a.IsLeader)
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+	go b.Run(ctxB, lead, follow)
+
+	time.Sleep(200 * time.Millisecond)
+	if b.IsLeader() {
+		t.Fatal("b became leader while a holds the lease")
+	}
+
+	// a 正常關閉時釋放租約，b 在下一個續約間隔接手
+	cancelA()
+	<-doneA
+	waitFor(t, b.IsLeader)
+}
+
+// waitFor 等待 cond 成立，最多 2 秒
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 2s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -2,15 +2,22 @@ package grpc
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
 	"time"
 
+	"golden-buy/price/internal/indicators"
 	"golden-buy/price/internal/model"
 	"golden-buy/price/internal/service"
 	pb "golden-buy/price/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// maxIndicators 單次請求最多計算的技術指標數量
+const maxIndicators = 10
+
 // PriceServiceServer gRPC 服務實現
 type PriceServiceServer struct {
 	pb.UnimplementedPriceServiceServer
@@ -28,18 +35,18 @@ func NewPriceServiceServer(priceService *service.PriceService) *PriceServiceServ
 func (s *PriceServiceServer) GetCurrentPrice(ctx context.Context, req *pb.GetPriceRequest) (*pb.PriceResponse, error) {
 	// 驗證 symbol
 	if req.Symbol == "" {
-		return nil, fmt.Errorf("symbol 不能為空")
+		return nil, status.Error(codes.InvalidArgument, "symbol 不能為空")
 	}
 
 	symbol := model.Symbol(req.Symbol)
 	if !isValidSymbol(symbol) {
-		return nil, fmt.Errorf("不支援的商品代碼: %s", req.Symbol)
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的商品代碼: %s", req.Symbol)
 	}
 
 	// 調用 service 層獲取價格
 	price, err := s.priceService.GetCurrentPrice(ctx, symbol)
 	if err != nil {
-		return nil, fmt.Errorf("獲取價格失敗: %v", err)
+		return nil, statusError(err, "獲取價格失敗")
 	}
 
 	// 轉換為 protobuf 響應
@@ -70,7 +77,7 @@ func (s *PriceServiceServer) GetCurrentPrices(ctx context.Context, req *pb.GetPr
 	// 調用 service 層獲取價格
 	prices, err := s.priceService.GetCurrentPrices(ctx, symbols)
 	if err != nil {
-		return nil, fmt.Errorf("獲取價格失敗: %v", err)
+		return nil, statusError(err, "獲取價格失敗")
 	}
 
 	// 轉換為 protobuf 響應
@@ -144,12 +151,12 @@ func (s *PriceServiceServer) SubscribePrices(req *pb.SubscribeRequest, stream pb
 func (s *PriceServiceServer) GetKlines(ctx context.Context, req *pb.GetKlinesRequest) (*pb.KlinesResponse, error) {
 	// 驗證參數
 	if req.Symbol == "" {
-		return nil, fmt.Errorf("symbol 不能為空")
+		return nil, status.Error(codes.InvalidArgument, "symbol 不能為空")
 	}
 
 	symbol := model.Symbol(req.Symbol)
 	if !isValidSymbol(symbol) {
-		return nil, fmt.Errorf("不支援的商品代碼: %s", req.Symbol)
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的商品代碼: %s", req.Symbol)
 	}
 
 	if req.Interval == "" {
@@ -157,7 +164,7 @@ func (s *PriceServiceServer) GetKlines(ctx context.Context, req *pb.GetKlinesReq
 	}
 
 	if !model.IsValidInterval(req.Interval) {
-		return nil, fmt.Errorf("不支援的時間週期: %s", req.Interval)
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的時間週期: %s", req.Interval)
 	}
 
 	// 設定預設值
@@ -168,6 +175,25 @@ func (s *PriceServiceServer) GetKlines(ctx context.Context, req *pb.GetKlinesReq
 		req.Limit = 1000
 	}
 
+	if req.Direction == "" {
+		req.Direction = string(model.KlineDirectionLatest) // 預設取最新的 K 線
+	}
+	if !model.IsValidKlineDirection(req.Direction) {
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的分頁方向: %s", req.Direction)
+	}
+
+	if req.Fill == "" {
+		req.Fill = string(model.KlineFillNone)
+	}
+	if !model.IsValidKlineFillMode(req.Fill) {
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的填補模式: %s", req.Fill)
+	}
+
+	alignment, err := model.ParseKlineAlignment(req.Timezone, req.SessionStart)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	if req.StartTime == 0 {
 		req.StartTime = time.Now().Add(-24 * time.Hour).UnixMilli()
 	}
@@ -176,14 +202,24 @@ func (s *PriceServiceServer) GetKlines(ctx context.Context, req *pb.GetKlinesReq
 	}
 
 	// 調用 service 層查詢 K 線
-	klines, err := s.priceService.GetKlines(ctx, symbol, req.Interval, req.StartTime, req.EndTime, int(req.Limit))
+	page, err := s.priceService.GetKlines(ctx, model.KlineQuery{
+		Symbol:    symbol,
+		Interval:  model.Interval(req.Interval),
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Limit:     int(req.Limit),
+		Direction: model.KlineDirection(req.Direction),
+		Cursor:    req.Cursor,
+		Fill:      model.KlineFillMode(req.Fill),
+		Alignment: alignment,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("查詢 K 線失敗: %v", err)
+		return nil, statusError(err, "查詢 K 線失敗")
 	}
 
 	// 轉換為 protobuf 響應
 	var responses []*pb.Kline
-	for _, kline := range klines {
+	for _, kline := range page.Klines {
 		responses = append(responses, &pb.Kline{
 			Timestamp: kline.Timestamp.UnixMilli(),
 			Open:      kline.Open,
@@ -191,17 +227,263 @@ func (s *PriceServiceServer) GetKlines(ctx context.Context, req *pb.GetKlinesReq
 			Low:       kline.Low,
 			Close:     kline.Close,
 			Volume:    kline.Volume,
+			Filled:    kline.Filled,
+			Empty:     kline.Empty,
 		})
 	}
 
 	return &pb.KlinesResponse{
-		Symbol:   string(symbol),
-		Interval: req.Interval,
-		Klines:   responses,
-		Total:    int32(len(responses)),
+		Symbol:     string(symbol),
+		Interval:   req.Interval,
+		Klines:     responses,
+		Total:      int32(page.Total),
+		NextCursor: page.NextCursor,
+		Partial:    page.Partial,
+	}, nil
+}
+
+// GetStatistics 獲取市場統計
+func (s *PriceServiceServer) GetStatistics(ctx context.Context, req *pb.GetStatisticsRequest) (*pb.StatisticsResponse, error) {
+	// 驗證參數
+	if req.Symbol == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol 不能為空")
+	}
+
+	symbol := model.Symbol(req.Symbol)
+	if !isValidSymbol(symbol) {
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的商品代碼: %s", req.Symbol)
+	}
+
+	end := time.Now()
+	if req.EndTime != 0 {
+		end = time.UnixMilli(req.EndTime)
+	}
+
+	var start time.Time
+	if req.StartTime != 0 {
+		start = time.UnixMilli(req.StartTime)
+	} else {
+		if req.Range == "" {
+			req.Range = "24h" // 預設最近 24 小時
+		}
+
+		loc, err := time.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "不支援的時區: %s", req.Timezone)
+		}
+
+		start, err = model.ParseStatisticsRange(req.Range, end, loc)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if !start.Before(end) {
+		return nil, status.Error(codes.InvalidArgument, "無效的統計範圍: start_time 必須早於 end_time")
+	}
+
+	// 調用 service 層計算統計
+	stats, err := s.priceService.GetStatistics(ctx, symbol, start, end)
+	if err != nil {
+		return nil, statusError(err, "查詢統計資料失敗")
+	}
+
+	// 轉換為 protobuf 響應
+	return &pb.StatisticsResponse{
+		Symbol:        string(stats.Symbol),
+		StartTime:     stats.StartTime.UnixMilli(),
+		EndTime:       stats.EndTime.UnixMilli(),
+		Open:          stats.Open,
+		High:          stats.High,
+		Low:           stats.Low,
+		Close:         stats.Close,
+		Mean:          stats.Mean,
+		Twap:          stats.TWAP,
+		Change:        stats.Change,
+		ChangePercent: stats.ChangePercent,
+		TickCount:     stats.TickCount,
+		Volatility:    stats.Volatility,
+		Vwap:          stats.VWAP,
+		Volume:        stats.Volume,
 	}, nil
 }
 
+// GetIndicators 獲取技術指標
+func (s *PriceServiceServer) GetIndicators(ctx context.Context, req *pb.GetIndicatorsRequest) (*pb.IndicatorsResponse, error) {
+	// 驗證參數
+	if req.Symbol == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol 不能為空")
+	}
+
+	symbol := model.Symbol(req.Symbol)
+	if !isValidSymbol(symbol) {
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的商品代碼: %s", req.Symbol)
+	}
+
+	if req.Interval == "" {
+		req.Interval = "1m" // 預設 1 分鐘
+	}
+
+	if !model.IsValidInterval(req.Interval) {
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的時間週期: %s", req.Interval)
+	}
+
+	if len(req.Indicators) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "indicators 不能為空")
+	}
+	if len(req.Indicators) > maxIndicators {
+		return nil, status.Errorf(codes.InvalidArgument, "indicators 最多 %d 個", maxIndicators)
+	}
+
+	specs := make([]indicators.Spec, 0, len(req.Indicators))
+	for _, ind := range req.Indicators {
+		spec, err := indicators.Spec{Name: ind.Name, Params: ind.Params}.Normalize()
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		specs = append(specs, spec)
+	}
+
+	// 設定預設值
+	if req.Limit <= 0 {
+		req.Limit = 100
+	}
+	if req.Limit > 1000 {
+		req.Limit = 1000
+	}
+
+	alignment, err := model.ParseKlineAlignment(req.Timezone, req.SessionStart)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if req.StartTime == 0 {
+		req.StartTime = time.Now().Add(-24 * time.Hour).UnixMilli()
+	}
+	if req.EndTime == 0 {
+		req.EndTime = time.Now().UnixMilli()
+	}
+
+	// 調用 service 層計算指標
+	set, err := s.priceService.GetIndicators(ctx, model.KlineQuery{
+		Symbol:    symbol,
+		Interval:  model.Interval(req.Interval),
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Limit:     int(req.Limit),
+		Alignment: alignment,
+	}, specs)
+	if err != nil {
+		return nil, statusError(err, "計算技術指標失敗")
+	}
+
+	// 轉換為 protobuf 響應
+	var responses []*pb.IndicatorSeries
+	for _, series := range set.Indicators {
+		var lines []*pb.IndicatorLine
+		for _, line := range series.Lines {
+			points := make([]*pb.IndicatorPoint, len(line.Points))
+			for i, point := range line.Points {
+				points[i] = &pb.IndicatorPoint{
+					Timestamp: point.Timestamp.UnixMilli(),
+					Value:     point.Value,
+				}
+			}
+			lines = append(lines, &pb.IndicatorLine{Name: line.Name, Points: points})
+		}
+
+		responses = append(responses, &pb.IndicatorSeries{
+			Name:   series.Name,
+			Params: series.Params,
+			Lines:  lines,
+		})
+	}
+
+	return &pb.IndicatorsResponse{
+		Symbol:     string(symbol),
+		Interval:   req.Interval,
+		Indicators: responses,
+		Partial:    set.Partial,
+	}, nil
+}
+
+// GetTicks 分頁獲取原始 tick
+func (s *PriceServiceServer) GetTicks(ctx context.Context, req *pb.GetTicksRequest) (*pb.TicksResponse, error) {
+	// 驗證參數
+	if req.Symbol == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol 不能為空")
+	}
+
+	symbol := model.Symbol(req.Symbol)
+	if !isValidSymbol(symbol) {
+		return nil, status.Errorf(codes.InvalidArgument, "不支援的商品代碼: %s", req.Symbol)
+	}
+
+	// 設定預設值
+	if req.PageSize <= 0 {
+		req.PageSize = 500
+	}
+	if req.PageSize > 1000 {
+		req.PageSize = 1000
+	}
+
+	// 後續頁沿用分頁標記中第一頁解析後的範圍，不重新以現在時間計算預設值
+	if req.PageToken == "" {
+		if req.EndTime == 0 {
+			req.EndTime = time.Now().UnixMilli()
+		}
+		if req.StartTime == 0 {
+			req.StartTime = time.UnixMilli(req.EndTime).Add(-time.Minute).UnixMilli()
+		}
+	}
+
+	// 調用 service 層查詢 tick
+	page, err := s.priceService.GetTicks(ctx, model.TickQuery{
+		Symbol:    symbol,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		PageToken: req.PageToken,
+		PageSize:  int(req.PageSize),
+	})
+	if err != nil {
+		return nil, statusError(err, "查詢原始 tick 失敗")
+	}
+
+	// 轉換為 protobuf 響應
+	ticks := make([]*pb.Tick, len(page.Ticks))
+	for i, tick := range page.Ticks {
+		ticks[i] = &pb.Tick{
+			Timestamp:     tick.Timestamp.UnixMilli(),
+			Price:         tick.Price,
+			Change:        tick.Change,
+			ChangePercent: tick.ChangePercent,
+		}
+	}
+
+	return &pb.TicksResponse{
+		Symbol:        string(symbol),
+		Ticks:         ticks,
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
+// statusError 將 service 層的錯誤轉為 gRPC 狀態碼，讓客戶端區分呼叫端錯誤和服務異常
+// 參數錯誤為 InvalidArgument，查無資料為 NotFound，其餘（InfluxDB、Redis 失敗等）為 Internal
+func statusError(err error, msg string) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, model.ErrInvalidQuery):
+		code = codes.InvalidArgument
+	case errors.Is(err, model.ErrNoData):
+		code = codes.NotFound
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	return status.Errorf(code, "%s: %v", msg, err)
+}
+
 // isValidSymbol 驗證商品代碼是否有效
 func isValidSymbol(symbol model.Symbol) bool {
 	for _, validSymbol := range model.AllSymbols {
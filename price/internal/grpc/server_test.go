@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"golden-buy/price/internal/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "invalid query", err: fmt.Errorf("%w: 游標格式錯誤", model.ErrInvalidQuery), want: codes.InvalidArgument},
+		{name: "wrapped invalid query", err: fmt.Errorf("查詢 K 線失敗: %w", fmt.Errorf("%w: 查詢範圍過大", model.ErrInvalidQuery)), want: codes.InvalidArgument},
+		{name: "no data", err: fmt.Errorf("%w: 範圍內沒有 GOLD 的價格資料", model.ErrNoData), want: codes.NotFound},
+		{name: "canceled", err: fmt.Errorf("查詢 K 線失敗: %w", context.Canceled), want: codes.Canceled},
+		{name: "deadline", err: fmt.Errorf("查詢 K 線失敗: %w", context.DeadlineExceeded), want: codes.DeadlineExceeded},
+		{name: "internal", err: errors.New("InfluxDB 連接失敗"), want: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(statusError(tt.err, "查詢失敗")); got != tt.want {
+				t.Errorf("statusError() code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
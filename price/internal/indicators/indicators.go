@@ -0,0 +1,274 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// 支援的技術指標
+const (
+	SMA       = "sma"       // 簡單移動平均，參數 [period]
+	EMA       = "ema"       // 指數移動平均，參數 [period]
+	Bollinger = "bollinger" // 布林通道，參數 [period, stddev 倍數]
+	RSI       = "rsi"       // 相對強弱指標（Wilder 平滑），參數 [period]
+	MACD      = "macd"      // 指數平滑異同移動平均，參數 [fast, slow, signal]
+)
+
+// maxPeriod 單一指標允許的最大週期
+const maxPeriod = 500
+
+// defaultParams 各指標的預設參數
+var defaultParams = map[string][]float64{
+	SMA:       {20},
+	EMA:       {20},
+	Bollinger: {20, 2},
+	RSI:       {14},
+	MACD:      {12, 26, 9},
+}
+
+// Spec 指標規格
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+// Line 指標輸出的一條線，與輸入的 K 線一一對應，暖機期間的值為 NaN
+type Line struct {
+	Name   string
+	Values []float64
+}
+
+// Result 單一指標的計算結果
+type Result struct {
+	Spec  Spec
+	Lines []Line
+}
+
+// Normalize 驗證規格並補上預設參數
+func (s Spec) Normalize() (Spec, error) {
+	defaults, ok := defaultParams[s.Name]
+	if !ok {
+		return Spec{}, fmt.Errorf("不支援的技術指標: %s", s.Name)
+	}
+
+	if len(s.Params) > len(defaults) {
+		return Spec{}, fmt.Errorf("%s 最多 %d 個參數", s.Name, len(defaults))
+	}
+
+	params := make([]float64, len(defaults))
+	copy(params, defaults)
+	copy(params, s.Params)
+
+	for i, p := range params {
+		// 布林通道的標準差倍數可為小數，其餘參數皆為週期
+		if s.Name == Bollinger && i == 1 {
+			if p <= 0 || p > 10 {
+				return Spec{}, fmt.Errorf("無效的 %s 標準差倍數: %v", s.Name, p)
+			}
+			continue
+		}
+		if p < 1 || p > maxPeriod || p != math.Trunc(p) {
+			return Spec{}, fmt.Errorf("無效的 %s 週期: %v（需為 1 ~ %d 的整數）", s.Name, p, maxPeriod)
+		}
+	}
+
+	if s.Name == MACD && params[0] >= params[1] {
+		return Spec{}, fmt.Errorf("無效的 %s 參數: fast 必須小於 slow", s.Name)
+	}
+
+	return Spec{Name: s.Name, Params: params}, nil
+}
+
+// Warmup 產生穩定數值所需的前置 K 線數量
+// 指數平滑類指標取週期的 3 倍，讓初始值的影響衰減到可忽略
+func (s Spec) Warmup() int {
+	switch s.Name {
+	case SMA, Bollinger:
+		return int(s.Params[0])
+	case EMA, RSI:
+		return 3 * int(s.Params[0])
+	case MACD:
+		return 3*int(s.Params[1]) + int(s.Params[2])
+	default:
+		return 0
+	}
+}
+
+// Compute 以收盤價計算指標，spec 必須已經 Normalize
+func Compute(spec Spec, closes []float64) (*Result, error) {
+	var lines []Line
+
+	switch spec.Name {
+	case SMA:
+		lines = []Line{{Name: SMA, Values: sma(closes, int(spec.Params[0]))}}
+	case EMA:
+		lines = []Line{{Name: EMA, Values: ema(closes, int(spec.Params[0]))}}
+	case Bollinger:
+		middle, upper, lower := bollinger(closes, int(spec.Params[0]), spec.Params[1])
+		lines = []Line{
+			{Name: "middle", Values: middle},
+			{Name: "upper", Values: upper},
+			{Name: "lower", Values: lower},
+		}
+	case RSI:
+		lines = []Line{{Name: RSI, Values: rsi(closes, int(spec.Params[0]))}}
+	case MACD:
+		macdLine, signal, histogram := macd(closes, int(spec.Params[0]), int(spec.Params[1]), int(spec.Params[2]))
+		lines = []Line{
+			{Name: MACD, Values: macdLine},
+			{Name: "signal", Values: signal},
+			{Name: "histogram", Values: histogram},
+		}
+	default:
+		return nil, fmt.Errorf("不支援的技術指標: %s", spec.Name)
+	}
+
+	return &Result{Spec: spec, Lines: lines}, nil
+}
+
+// newSeries 創建全部為 NaN 的序列
+func newSeries(n int) []float64 {
+	series := make([]float64, n)
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	return series
+}
+
+// sma 簡單移動平均
+func sma(values []float64, period int) []float64 {
+	out := newSeries(len(values))
+
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+
+	return out
+}
+
+// ema 指數移動平均，以第一段有效值的 SMA 作為初始值，輸入開頭的 NaN 會被跳過
+func ema(values []float64, period int) []float64 {
+	out := newSeries(len(values))
+
+	first := 0
+	for first < len(values) && math.IsNaN(values[first]) {
+		first++
+	}
+
+	seed := first + period - 1
+	if seed >= len(values) {
+		return out
+	}
+
+	var sum float64
+	for _, v := range values[first : seed+1] {
+		sum += v
+	}
+	out[seed] = sum / float64(period)
+
+	alpha := 2 / float64(period+1)
+	for i := seed + 1; i < len(values); i++ {
+		out[i] = alpha*values[i] + (1-alpha)*out[i-1]
+	}
+
+	return out
+}
+
+// bollinger 布林通道，中軌為 SMA，上下軌為中軌加減 k 倍母體標準差
+func bollinger(values []float64, period int, k float64) (middle, upper, lower []float64) {
+	middle = sma(values, period)
+	upper = newSeries(len(values))
+	lower = newSeries(len(values))
+
+	for i := period - 1; i < len(values); i++ {
+		var variance float64
+		for _, v := range values[i-period+1 : i+1] {
+			d := v - middle[i]
+			variance += d * d
+		}
+		stddev := math.Sqrt(variance / float64(period))
+
+		upper[i] = middle[i] + k*stddev
+		lower[i] = middle[i] - k*stddev
+	}
+
+	return middle, upper, lower
+}
+
+// rsi 相對強弱指標，使用 Wilder 平滑
+func rsi(values []float64, period int) []float64 {
+	out := newSeries(len(values))
+	if len(values) <= period {
+		return out
+	}
+
+	var avgGain, avgLoss float64
+	for i := 1; i <= period; i++ {
+		gain, loss := change(values[i-1], values[i])
+		avgGain += gain
+		avgLoss += loss
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+	out[period] = rsiValue(avgGain, avgLoss)
+
+	for i := period + 1; i < len(values); i++ {
+		gain, loss := change(values[i-1], values[i])
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		out[i] = rsiValue(avgGain, avgLoss)
+	}
+
+	return out
+}
+
+// change 拆分漲跌幅
+func change(prev, curr float64) (gain, loss float64) {
+	d := curr - prev
+	if d > 0 {
+		return d, 0
+	}
+	return 0, -d
+}
+
+// rsiValue 由平均漲跌幅計算 RSI，沒有下跌時為 100，完全沒有波動時為 50
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+avgGain/avgLoss)
+}
+
+// macd MACD 線為快慢 EMA 之差，訊號線為 MACD 線的 EMA，柱狀圖為兩者之差
+func macd(values []float64, fast, slow, signalPeriod int) (macdLine, signal, histogram []float64) {
+	fastEMA := ema(values, fast)
+	slowEMA := ema(values, slow)
+
+	macdLine = newSeries(len(values))
+	for i := range values {
+		if !math.IsNaN(slowEMA[i]) {
+			macdLine[i] = fastEMA[i] - slowEMA[i]
+		}
+	}
+
+	signal = ema(macdLine, signalPeriod)
+
+	histogram = newSeries(len(values))
+	for i := range values {
+		if !math.IsNaN(signal[i]) {
+			histogram[i] = macdLine[i] - signal[i]
+		}
+	}
+
+	return macdLine, signal, histogram
+}
@@ -0,0 +1,162 @@
+package indicators
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+var nan = math.NaN()
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    Spec
+		want    []float64
+		wantErr bool
+	}{
+		{name: "defaults", spec: Spec{Name: MACD}, want: []float64{12, 26, 9}},
+		{name: "partial params", spec: Spec{Name: Bollinger, Params: []float64{10}}, want: []float64{10, 2}},
+		{name: "fractional stddev", spec: Spec{Name: Bollinger, Params: []float64{20, 2.5}}, want: []float64{20, 2.5}},
+		{name: "unknown", spec: Spec{Name: "vwap"}, wantErr: true},
+		{name: "too many params", spec: Spec{Name: SMA, Params: []float64{5, 10}}, wantErr: true},
+		{name: "fractional period", spec: Spec{Name: EMA, Params: []float64{2.5}}, wantErr: true},
+		{name: "period too large", spec: Spec{Name: RSI, Params: []float64{maxPeriod + 1}}, wantErr: true},
+		{name: "stddev out of range", spec: Spec{Name: Bollinger, Params: []float64{20, 0}}, wantErr: true},
+		{name: "fast not below slow", spec: Spec{Name: MACD, Params: []float64{26, 12}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Normalize()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Params, tt.want) {
+				t.Errorf("Normalize() params = %v, want %v", got.Params, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   Spec
+		closes []float64
+		want   map[string][]float64
+	}{
+		{
+			name:   "sma",
+			spec:   Spec{Name: SMA, Params: []float64{3}},
+			closes: []float64{1, 2, 3, 4, 5},
+			want:   map[string][]float64{SMA: {nan, nan, 2, 3, 4}},
+		},
+		{
+			name:   "ema seeded with sma",
+			spec:   Spec{Name: EMA, Params: []float64{3}},
+			closes: []float64{1, 2, 3, 4, 6},
+			want:   map[string][]float64{EMA: {nan, nan, 2, 3, 4.5}},
+		},
+		{
+			name:   "bollinger",
+			spec:   Spec{Name: Bollinger, Params: []float64{2, 1}},
+			closes: []float64{1, 3, 5},
+			want: map[string][]float64{
+				"middle": {nan, 2, 4},
+				"upper":  {nan, 3, 5},
+				"lower":  {nan, 1, 3},
+			},
+		},
+		{
+			name:   "rsi with wilder smoothing",
+			spec:   Spec{Name: RSI, Params: []float64{2}},
+			closes: []float64{1, 2, 3, 2},
+			want:   map[string][]float64{RSI: {nan, nan, 100, 50}},
+		},
+		{
+			name:   "rsi without movement",
+			spec:   Spec{Name: RSI, Params: []float64{2}},
+			closes: []float64{1, 1, 1},
+			want:   map[string][]float64{RSI: {nan, nan, 50}},
+		},
+		{
+			name:   "macd",
+			spec:   Spec{Name: MACD, Params: []float64{1, 2, 1}},
+			closes: []float64{1, 2, 3},
+			want: map[string][]float64{
+				MACD:        {nan, 0.5, 0.5},
+				"signal":    {nan, 0.5, 0.5},
+				"histogram": {nan, 0, 0},
+			},
+		},
+		{
+			name:   "shorter than period",
+			spec:   Spec{Name: SMA, Params: []float64{5}},
+			closes: []float64{1, 2},
+			want:   map[string][]float64{SMA: {nan, nan}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compute(tt.spec, tt.closes)
+			if err != nil {
+				t.Fatalf("Compute() error = %v", err)
+			}
+			if len(result.Lines) != len(tt.want) {
+				t.Fatalf("Compute() returned %d lines, want %d", len(result.Lines), len(tt.want))
+			}
+
+			for _, line := range result.Lines {
+				want, ok := tt.want[line.Name]
+				if !ok {
+					t.Fatalf("unexpected line %q", line.Name)
+				}
+				if !equalSeries(line.Values, want) {
+					t.Errorf("line %q = %v, want %v", line.Name, line.Values, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	tests := []struct {
+		spec Spec
+		want int
+	}{
+		{spec: Spec{Name: SMA, Params: []float64{20}}, want: 20},
+		{spec: Spec{Name: Bollinger, Params: []float64{20, 2}}, want: 20},
+		{spec: Spec{Name: EMA, Params: []float64{10}}, want: 30},
+		{spec: Spec{Name: RSI, Params: []float64{14}}, want: 42},
+		{spec: Spec{Name: MACD, Params: []float64{12, 26, 9}}, want: 87},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec.Name, func(t *testing.T) {
+			if got := tt.spec.Warmup(); got != tt.want {
+				t.Errorf("Warmup() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// equalSeries 比較兩條序列，NaN 視為相等
+func equalSeries(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got[i]) {
+				return false
+			}
+			continue
+		}
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
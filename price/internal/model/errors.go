@@ -0,0 +1,9 @@
+package model
+
+import "errors"
+
+// ErrInvalidQuery 查詢參數錯誤（範圍、游標、分頁標記等），呼叫端修正後才能成功
+var ErrInvalidQuery = errors.New("無效的查詢")
+
+// ErrNoData 查詢範圍內沒有資料
+var ErrNoData = errors.New("沒有資料")
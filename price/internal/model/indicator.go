@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// IndicatorPoint 指標在某根 K 線上的數值
+type IndicatorPoint struct {
+	Timestamp time.Time `json:"timestamp"` // 對應 K 線的開始時間
+	Value     float64   `json:"value"`
+}
+
+// IndicatorLine 指標輸出的一條線，例如布林通道的 upper
+type IndicatorLine struct {
+	Name   string           `json:"name"`
+	Points []IndicatorPoint `json:"points"` // 依時間升序，暖機不足的 K 線沒有數值
+}
+
+// IndicatorSeries 單一指標的計算結果
+type IndicatorSeries struct {
+	Name   string          `json:"name"`
+	Params []float64       `json:"params"`
+	Lines  []IndicatorLine `json:"lines"`
+}
+
+// IndicatorSet 技術指標查詢結果
+type IndicatorSet struct {
+	Indicators []*IndicatorSeries `json:"indicators"`
+	Partial    bool               `json:"partial"` // 部分 K 線資料來源失敗，結果可能不完整
+}
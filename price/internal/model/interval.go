@@ -0,0 +1,192 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Interval K 線時間週期
+type Interval string
+
+const (
+	Interval1s  Interval = "1s"
+	Interval1m  Interval = "1m"
+	Interval3m  Interval = "3m"
+	Interval5m  Interval = "5m"
+	Interval15m Interval = "15m"
+	Interval30m Interval = "30m"
+	Interval1h  Interval = "1h"
+	Interval2h  Interval = "2h"
+	Interval4h  Interval = "4h"
+	Interval1d  Interval = "1d"
+	Interval1w  Interval = "1w" // 週線，從週一開始
+	Interval1M  Interval = "1M" // 月線，從每月 1 日開始
+)
+
+// AllIntervals 所有支援的時間週期
+var AllIntervals = []Interval{
+	Interval1s,
+	Interval1m,
+	Interval3m,
+	Interval5m,
+	Interval15m,
+	Interval30m,
+	Interval1h,
+	Interval2h,
+	Interval4h,
+	Interval1d,
+	Interval1w,
+	Interval1M,
+}
+
+// StoredIntervals 由降採樣器預先聚合存儲的時間週期
+// 1s 直接從原始 tick 聚合，1w / 1M 由 1d 彙總而成
+var StoredIntervals = []Interval{
+	Interval1m,
+	Interval3m,
+	Interval5m,
+	Interval15m,
+	Interval30m,
+	Interval1h,
+	Interval2h,
+	Interval4h,
+	Interval1d,
+}
+
+// Duration 時間週期對應的長度，月線為 30 天的近似值
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case Interval1s:
+		return time.Second
+	case Interval1m:
+		return time.Minute
+	case Interval3m:
+		return 3 * time.Minute
+	case Interval5m:
+		return 5 * time.Minute
+	case Interval15m:
+		return 15 * time.Minute
+	case Interval30m:
+		return 30 * time.Minute
+	case Interval1h:
+		return time.Hour
+	case Interval2h:
+		return 2 * time.Hour
+	case Interval4h:
+		return 4 * time.Hour
+	case Interval1d:
+		return 24 * time.Hour
+	case Interval1w:
+		return 7 * 24 * time.Hour
+	case Interval1M:
+		return 30 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// IsCalendar 是否為依日曆對齊（受時區和交易日開始時間影響）的時間週期
+func (i Interval) IsCalendar() bool {
+	return i == Interval1d || i == Interval1w || i == Interval1M
+}
+
+// Truncate 返回 t 所屬 K 線的開始時間
+func (i Interval) Truncate(t time.Time, align KlineAlignment) time.Time {
+	if !i.IsCalendar() {
+		return t.Truncate(i.Duration())
+	}
+
+	loc := align.location()
+	hour, minute := align.sessionClock()
+
+	// 找出 t 所屬交易日的開始時間
+	y, m, d := t.In(loc).Date()
+	start := time.Date(y, m, d, hour, minute, 0, 0, loc)
+	if start.After(t) {
+		start = time.Date(y, m, d-1, hour, minute, 0, 0, loc)
+	}
+
+	y, m, d = start.Date()
+	switch i {
+	case Interval1w:
+		d -= (int(start.Weekday()) + 6) % 7 // 回到週一
+	case Interval1M:
+		d = 1
+	}
+
+	return time.Date(y, m, d, hour, minute, 0, 0, loc)
+}
+
+// Next 返回下一根 K 線的開始時間，start 必須是 Truncate 的結果
+func (i Interval) Next(start time.Time, align KlineAlignment) time.Time {
+	if !i.IsCalendar() {
+		return start.Add(i.Duration())
+	}
+
+	loc := align.location()
+	hour, minute := align.sessionClock()
+
+	y, m, d := start.In(loc).Date()
+	switch i {
+	case Interval1d:
+		d++
+	case Interval1w:
+		d += 7
+	case Interval1M:
+		m++
+	}
+
+	return time.Date(y, m, d, hour, minute, 0, 0, loc)
+}
+
+// IsValidInterval 驗證時間週期是否有效
+func IsValidInterval(interval string) bool {
+	return Interval(interval).Duration() > 0
+}
+
+// KlineAlignment 日線以上 K 線的對齊方式
+type KlineAlignment struct {
+	Location     *time.Location // 時區，nil 表示 UTC
+	SessionStart time.Duration  // 交易日開始時間（當地時間距午夜的長度），例如紐約 17:00 換日
+}
+
+// ParseKlineAlignment 解析時區名稱（IANA，例如 Asia/Taipei）和交易日開始時間（HH:MM），空字串表示使用預設值
+func ParseKlineAlignment(timezone, sessionStart string) (KlineAlignment, error) {
+	var align KlineAlignment
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return align, fmt.Errorf("不支援的時區: %s", timezone)
+		}
+		align.Location = loc
+	}
+
+	if sessionStart != "" {
+		t, err := time.Parse("15:04", sessionStart)
+		if err != nil {
+			return align, fmt.Errorf("交易日開始時間格式錯誤，應為 HH:MM: %s", sessionStart)
+		}
+		align.SessionStart = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+
+	return align, nil
+}
+
+// IsDefault 是否為預設對齊方式（UTC 午夜）
+func (a KlineAlignment) IsDefault() bool {
+	return a.location() == time.UTC && a.SessionStart == 0
+}
+
+// location 返回對齊使用的時區
+func (a KlineAlignment) location() *time.Location {
+	if a.Location == nil {
+		return time.UTC
+	}
+	return a.Location
+}
+
+// sessionClock 返回交易日開始的時和分
+func (a KlineAlignment) sessionClock() (int, int) {
+	return int(a.SessionStart / time.Hour), int(a.SessionStart % time.Hour / time.Minute)
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalTruncateAndNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	session := KlineAlignment{Location: newYork, SessionStart: 17 * time.Hour}
+
+	tests := []struct {
+		name     string
+		interval Interval
+		align    KlineAlignment
+		t        time.Time
+		want     time.Time
+		next     time.Time
+	}{
+		{
+			name:     "fixed length",
+			interval: Interval15m,
+			t:        time.Date(2025, 3, 5, 10, 29, 59, 0, time.UTC),
+			want:     time.Date(2025, 3, 5, 10, 15, 0, 0, time.UTC),
+			next:     time.Date(2025, 3, 5, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "utc day",
+			interval: Interval1d,
+			t:        time.Date(2025, 3, 5, 23, 59, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "week starts on monday",
+			interval: Interval1w,
+			t:        time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC), // 週日
+			want:     time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "month",
+			interval: Interval1M,
+			t:        time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			next:     time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "session before the local start belongs to the previous day",
+			interval: Interval1d,
+			align:    session,
+			t:        time.Date(2025, 3, 5, 16, 59, 0, 0, newYork),
+			want:     time.Date(2025, 3, 4, 17, 0, 0, 0, newYork),
+			next:     time.Date(2025, 3, 5, 17, 0, 0, 0, newYork),
+		},
+		{
+			name:     "session across daylight saving",
+			interval: Interval1d,
+			align:    session,
+			t:        time.Date(2025, 3, 8, 18, 0, 0, 0, newYork),
+			want:     time.Date(2025, 3, 8, 17, 0, 0, 0, newYork),
+			next:     time.Date(2025, 3, 9, 17, 0, 0, 0, newYork), // 23 小時後
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.interval.Truncate(tt.t, tt.align)
+			if !got.Equal(tt.want) {
+				t.Fatalf("Truncate() = %s, want %s", got, tt.want)
+			}
+			if next := tt.interval.Next(got, tt.align); !next.Equal(tt.next) {
+				t.Errorf("Next() = %s, want %s", next, tt.next)
+			}
+		})
+	}
+}
+
+func TestParseKlineAlignment(t *testing.T) {
+	tests := []struct {
+		name         string
+		timezone     string
+		sessionStart string
+		wantSession  time.Duration
+		wantDefault  bool
+		wantErr      bool
+	}{
+		{name: "defaults", wantDefault: true},
+		{name: "utc name", timezone: "UTC", wantDefault: true},
+		{name: "session start", sessionStart: "17:30", wantSession: 17*time.Hour + 30*time.Minute},
+		{name: "unknown zone", timezone: "Mars/Olympus", wantErr: true},
+		{name: "bad session", sessionStart: "5pm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKlineAlignment(tt.timezone, tt.sessionStart)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKlineAlignment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.SessionStart != tt.wantSession || got.IsDefault() != tt.wantDefault {
+				t.Errorf("ParseKlineAlignment() = %+v (default %v), want session %s default %v",
+					got, got.IsDefault(), tt.wantSession, tt.wantDefault)
+			}
+		})
+	}
+}
@@ -10,25 +10,67 @@ type Kline struct {
 	Low       float64   `json:"low"`
 	Close     float64   `json:"close"`
 	Volume    float64   `json:"volume"`
+	Filled    bool      `json:"filled,omitempty"` // 缺口填補產生的 K 線
+	Empty     bool      `json:"empty,omitempty"`  // fill=null 產生的空 K 線，OHLC 無意義
 }
 
-// Interval K 線時間週期
-type Interval string
+// KlineQuery K 線查詢條件
+type KlineQuery struct {
+	Symbol    Symbol
+	Interval  Interval
+	StartTime int64 // Unix 毫秒
+	EndTime   int64 // Unix 毫秒
+	Limit     int
+	Direction KlineDirection
+	Cursor    string
+	Fill      KlineFillMode
+	Alignment KlineAlignment // 日線以上 K 線的時區和交易日對齊方式
+}
+
+// KlinePage K 線分頁結果
+type KlinePage struct {
+	Klines     []*Kline `json:"klines"`      // 依時間升序排列
+	Total      int      `json:"total"`       // 查詢範圍內的 K 線總數
+	NextCursor string   `json:"next_cursor"` // 下一頁游標，沒有更多資料時為空
+	Partial    bool     `json:"partial"`     // 部分資料來源失敗，結果可能不完整
+}
+
+// KlineDirection K 線分頁方向
+type KlineDirection string
+
+const (
+	// KlineDirectionLatest 從範圍結尾往前取最新的 K 線（向前捲動）
+	KlineDirectionLatest KlineDirection = "latest"
+	// KlineDirectionOldest 從範圍開頭往後取最早的 K 線
+	KlineDirectionOldest KlineDirection = "oldest"
+)
+
+// IsValidKlineDirection 驗證分頁方向是否有效
+func IsValidKlineDirection(direction string) bool {
+	switch KlineDirection(direction) {
+	case KlineDirectionLatest, KlineDirectionOldest:
+		return true
+	default:
+		return false
+	}
+}
+
+// KlineFillMode K 線缺口填補模式
+type KlineFillMode string
 
 const (
-	Interval1m  Interval = "1m"
-	Interval5m  Interval = "5m"
-	Interval15m Interval = "15m"
-	Interval30m Interval = "30m"
-	Interval1h  Interval = "1h"
-	Interval4h  Interval = "4h"
-	Interval1d  Interval = "1d"
+	// KlineFillNone 不填補，缺口直接跳過
+	KlineFillNone KlineFillMode = "none"
+	// KlineFillPrevious 以前一根 K 線的收盤價填補
+	KlineFillPrevious KlineFillMode = "previous"
+	// KlineFillNull 以空 K 線填補，由客戶端顯示為空白
+	KlineFillNull KlineFillMode = "null"
 )
 
-// IsValidInterval 驗證時間週期是否有效
-func IsValidInterval(interval string) bool {
-	switch Interval(interval) {
-	case Interval1m, Interval5m, Interval15m, Interval30m, Interval1h, Interval4h, Interval1d:
+// IsValidKlineFillMode 驗證填補模式是否有效
+func IsValidKlineFillMode(mode string) bool {
+	switch KlineFillMode(mode) {
+	case KlineFillNone, KlineFillPrevious, KlineFillNull:
 		return true
 	default:
 		return false
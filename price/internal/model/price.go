@@ -27,6 +27,8 @@ type Price struct {
 	Timestamp     time.Time `json:"timestamp"`
 	Change        float64   `json:"change"`         // 變化量
 	ChangePercent float64   `json:"change_percent"` // 變化百分比
+	Volume        float64   `json:"volume"`         // 此 tick 的成交量
+	Heartbeat     bool      `json:"-"`              // 心跳訊息，只表示價格來源仍在運作，不是新的價格
 }
 
 // InitialPrices 初始價格配置
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// StatisticsRangeYTD 年初至今的統計範圍
+const StatisticsRangeYTD = "ytd"
+
+// statisticsRangePattern 相對統計範圍，例如 30m、24h、7d、4w
+var statisticsRangePattern = regexp.MustCompile(`^([0-9]{1,4})(m|h|d|w)$`)
+
+// Statistics 市場統計資料
+type Statistics struct {
+	Symbol        Symbol    `json:"symbol"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Open          float64   `json:"open"`
+	High          float64   `json:"high"`
+	Low           float64   `json:"low"`
+	Close         float64   `json:"close"`
+	Mean          float64   `json:"mean"`           // tick 價格的算術平均
+	VWAP          float64   `json:"vwap"`           // 成交量加權平均價，範圍內沒有成交量時為 0
+	TWAP          float64   `json:"twap"`           // 時間加權平均價
+	Change        float64   `json:"change"`         // 收盤價相對開盤價的變化量
+	ChangePercent float64   `json:"change_percent"` // 變化百分比
+	TickCount     int64     `json:"tick_count"`     // 範圍內的 tick 數量
+	Volatility    float64   `json:"volatility"`     // 1 分鐘對數報酬的已實現波動率（未年化）
+	Volume        float64   `json:"volume"`         // 範圍內的總成交量
+}
+
+// ParseStatisticsRange 解析統計範圍，返回範圍開始時間
+// 支援相對範圍（30m、24h、7d、4w）和 ytd（依 loc 時區的年初）
+func ParseStatisticsRange(value string, end time.Time, loc *time.Location) (time.Time, error) {
+	if value == StatisticsRangeYTD {
+		if loc == nil {
+			loc = time.UTC
+		}
+		local := end.In(loc)
+		return time.Date(local.Year(), time.January, 1, 0, 0, 0, 0, loc), nil
+	}
+
+	matches := statisticsRangePattern.FindStringSubmatch(value)
+	if matches == nil {
+		return time.Time{}, fmt.Errorf("不支援的統計範圍: %s", value)
+	}
+
+	n, err := strconv.Atoi(matches[1])
+	if err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("不支援的統計範圍: %s", value)
+	}
+
+	var unit time.Duration
+	switch matches[2] {
+	case "m":
+		unit = time.Minute
+	case "h":
+		unit = time.Hour
+	case "d":
+		unit = 24 * time.Hour
+	case "w":
+		unit = 7 * 24 * time.Hour
+	}
+
+	return end.Add(-time.Duration(n) * unit), nil
+}
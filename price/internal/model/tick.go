@@ -0,0 +1,16 @@
+package model
+
+// TickQuery 原始 tick 查詢條件
+type TickQuery struct {
+	Symbol    Symbol
+	StartTime int64 // Unix 毫秒
+	EndTime   int64 // Unix 毫秒
+	PageToken string
+	PageSize  int
+}
+
+// TickPage 原始 tick 分頁結果
+type TickPage struct {
+	Ticks         []*Price // 依時間升序排列
+	NextPageToken string   // 下一頁標記，沒有更多資料時為空
+}
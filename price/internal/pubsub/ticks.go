@@ -0,0 +1,76 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"golden-buy/price/internal/codec"
+	"golden-buy/price/internal/model"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AddTick 將 tick 寫入商品的 Sorted Set（score 為 Unix 毫秒），並刪除超過保留範圍的舊 tick
+func (p *Publisher) AddTick(ctx context.Context, price *model.Price) error {
+	ts := price.Timestamp.UnixMilli()
+
+	data, err := p.opts.Codec.Encode(price)
+	if err != nil {
+		return err
+	}
+
+	key := ticksKey(price.Symbol)
+	cutoff := price.Timestamp.Add(-p.opts.TickRetention).UnixMilli()
+
+	pipe := p.client.Pipeline()
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(ts), Member: data})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+	pipe.Expire(ctx, key, p.opts.TickRetention) // 停止發布後整個 key 自動過期
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetTicks 獲取範圍 [start, end) 內依時間升序的 tick
+func (p *Publisher) GetTicks(ctx context.Context, symbol model.Symbol, start, end time.Time) ([]*model.Price, error) {
+	results, err := p.client.ZRangeByScore(ctx, ticksKey(symbol), &redis.ZRangeBy{
+		Min: strconv.FormatInt(start.UnixMilli(), 10),
+		Max: "(" + strconv.FormatInt(end.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("查詢 %s tick 失敗: %v", symbol, err)
+	}
+
+	prices := make([]*model.Price, 0, len(results))
+	for _, result := range results {
+		price, _, err := codec.Decode([]byte(result))
+		if err != nil {
+			log.Printf("解析 tick 失敗: %v", err)
+			continue
+		}
+
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+}
+
+// GetRecentTicks 獲取最近 window 內的所有 tick，例如最近 5 分鐘
+func (p *Publisher) GetRecentTicks(ctx context.Context, symbol model.Symbol, window time.Duration) ([]*model.Price, error) {
+	now := time.Now()
+	return p.GetTicks(ctx, symbol, now.Add(-window), now.Add(time.Millisecond))
+}
+
+// GetSecondPrices 獲取指定秒內的所有價格
+func (p *Publisher) GetSecondPrices(ctx context.Context, symbol model.Symbol, timestamp time.Time) ([]*model.Price, error) {
+	second := timestamp.Truncate(time.Second)
+	return p.GetTicks(ctx, symbol, second, second.Add(time.Second))
+}
+
+// ticksKey 商品的 tick Sorted Set key
+func ticksKey(symbol model.Symbol) string {
+	return PriceTicksKeyPrefix + ":" + string(symbol)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"golden-buy/price/internal/model"
@@ -13,6 +14,30 @@ import (
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
 )
 
+// pricesMeasurement 原始 tick 的 measurement 名稱
+const pricesMeasurement = "prices"
+
+// maxGapQueries 讀取 K 線時單次請求最多分別聚合的缺口數
+const maxGapQueries = 4
+
+// QueryOptions 查詢限制
+type QueryOptions struct {
+	Timeout     time.Duration // 單次查詢逾時
+	MaxPoints   int           // 單次 K 線查詢最多涵蓋的資料點（範圍 / 週期），不論返回幾根
+	MaxRawRange time.Duration // 單次查詢最多掃描的原始 tick 時間範圍（從 tick 聚合 K 線、計數和統計）
+	// ServerParams 使用伺服器端參數化查詢（僅 InfluxDB Cloud 支援）
+	// 預設在查詢開頭宣告跳脫後的 params 記錄，是 InfluxDB OSS 唯一支援的方式；
+	// 兩種方式的參數值都經過格式驗證，查詢本體只引用 params，不直接拼接參數值
+	ServerParams bool
+}
+
+// DefaultQueryOptions 預設查詢限制
+var DefaultQueryOptions = QueryOptions{
+	Timeout:     10 * time.Second,
+	MaxPoints:   100000,
+	MaxRawRange: 366 * 24 * time.Hour,
+}
+
 // InfluxDBRepository InfluxDB 存儲層
 type InfluxDBRepository struct {
 	client        influxdb2.Client
@@ -21,10 +46,11 @@ type InfluxDBRepository struct {
 	queryAPI      api.QueryAPI
 	org           string
 	bucket        string
+	opts          QueryOptions
 }
 
 // NewInfluxDBRepository 創建 InfluxDB 存儲層
-func NewInfluxDBRepository(url, token, org, bucket string) (*InfluxDBRepository, error) {
+func NewInfluxDBRepository(url, token, org, bucket string, opts QueryOptions) (*InfluxDBRepository, error) {
 	// 創建 InfluxDB 客戶端
 	client := influxdb2.NewClient(url, token)
 
@@ -47,17 +73,19 @@ func NewInfluxDBRepository(url, token, org, bucket string) (*InfluxDBRepository,
 		queryAPI:      client.QueryAPI(org),
 		org:           org,
 		bucket:        bucket,
+		opts:          opts,
 	}, nil
 }
 
 // WritePrice 寫入單個價格
 func (r *InfluxDBRepository) WritePrice(ctx context.Context, price *model.Price) error {
 	// 創建資料點
-	point := write.NewPointWithMeasurement("prices").
+	point := write.NewPointWithMeasurement(pricesMeasurement).
 		AddTag("symbol", string(price.Symbol)).
 		AddField("price", price.Price).
 		AddField("change", price.Change).
 		AddField("change_percent", price.ChangePercent).
+		AddField("volume", price.Volume).
 		SetTime(price.Timestamp)
 
 	// 寫入 InfluxDB
@@ -71,82 +99,579 @@ func (r *InfluxDBRepository) WritePrice(ctx context.Context, price *model.Price)
 
 // GetLatestPrice 獲取最新價格
 func (r *InfluxDBRepository) GetLatestPrice(ctx context.Context, symbol model.Symbol) (*model.Price, error) {
-	// Flux 查詢語句
-	query := fmt.Sprintf(`
-		from(bucket: "%s")
-		|> range(start: -1h)
-		|> filter(fn: (r) => r["_measurement"] == "prices")
-		|> filter(fn: (r) => r["symbol"] == "%s")
-		|> filter(fn: (r) => r["_field"] == "price")
-		|> last()
-	`, r.bucket, string(symbol))
+	q := newFluxQuery().
+		From(r.bucket).
+		RangeSince(time.Hour).
+		Measurement(pricesMeasurement).
+		Symbol(symbol).
+		Field("price").
+		Pipe("last()")
+
+	var price *model.Price
+	err := r.query(ctx, q, func(result *api.QueryTableResult) error {
+		for result.Next() {
+			record := result.Record()
+			if value, ok := record.Value().(float64); ok {
+				price = &model.Price{
+					Symbol:    symbol,
+					Price:     value,
+					Timestamp: record.Time(),
+				}
+				return nil
+			}
+		}
+		return result.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查詢最新價格失敗: %w", err)
+	}
+
+	if price == nil {
+		return nil, fmt.Errorf("%w: 未找到 %s 的最新價格", model.ErrNoData, symbol)
+	}
+
+	return price, nil
+}
 
-	// 執行查詢
-	result, err := r.queryAPI.Query(ctx, query)
+// GetKlines 獲取範圍內的 K 線資料（依時間升序）
+// desc 為 true 時取範圍結尾最新的 limit 根，否則取開頭最舊的 limit 根；limit <= 0 表示不限制數量
+// 不論 limit，範圍都不可超過 MaxPoints 根 K 線
+// 優先讀取降採樣後的 klines_<interval> measurement，沒有存儲 K 線的區段（範圍開頭、中間的缺口和未收盤的 K 線）從原始 tick 聚合
+// 若原始 tick 聚合失敗但已有存儲的 K 線，返回已有資料並將 partial 設為 true
+func (r *InfluxDBRepository) GetKlines(ctx context.Context, symbol model.Symbol, interval string, startTime, endTime int64, desc bool, limit int) ([]*model.Kline, bool, error) {
+	stored, err := r.GetStoredKlines(ctx, symbol, interval, startTime, endTime, desc, limit)
 	if err != nil {
-		return nil, fmt.Errorf("查詢最新價格失敗: %v", err)
+		return nil, false, err
+	}
+	sortKlines(stored)
+
+	// 已取滿 limit 根時，範圍另一端超出最外側那根的區段不會出現在結果中，不需要讀取
+	step := model.Interval(interval).Duration()
+	spanStart, spanEnd := startTime, endTime
+	if limit > 0 && len(stored) >= limit {
+		if desc {
+			spanStart = stored[0].Timestamp.UnixMilli()
+		} else {
+			spanEnd = stored[len(stored)-1].Timestamp.Add(step).UnixMilli()
+		}
 	}
 
-	// 解析結果
-	for result.Next() {
-		record := result.Record()
-		if record.Table() == 0 {
-			price := &model.Price{
-				Symbol:    symbol,
-				Price:     record.Value().(float64),
-				Timestamp: record.Time(),
+	gaps := klineGaps(stored, spanStart, spanEnd, step)
+	if len(gaps) > maxGapQueries {
+		// 缺口太多時以一次查詢涵蓋所有缺口，與存儲 K 線重疊的部分在合併時捨棄
+		gaps = []timeRange{{start: gaps[0].start, end: gaps[len(gaps)-1].end}}
+	}
+
+	var raw []*model.Kline
+	for _, gap := range gaps {
+		klines, err := r.AggregateKlines(ctx, symbol, interval, gap.start, gap.end, desc, limit)
+		if err != nil {
+			if len(stored) == 0 {
+				return nil, false, err
 			}
-			return price, nil
+			log.Printf("聚合 %s %s 未降採樣區段失敗，返回部分資料: %v", symbol, interval, err)
+			return trimKlines(stored, desc, limit), true, nil
 		}
+		raw = append(raw, klines...)
 	}
 
-	return nil, fmt.Errorf("未找到 %s 的最新價格", symbol)
+	return trimKlines(mergeKlines(stored, raw), desc, limit), false, nil
 }
 
-// GetKlines 獲取 K 線資料
-func (r *InfluxDBRepository) GetKlines(ctx context.Context, symbol model.Symbol, interval string, startTime, endTime int64, limit int) ([]*model.Kline, error) {
-	// 轉換時間戳為 RFC3339 格式
-	start := time.UnixMilli(startTime).Format(time.RFC3339)
-	end := time.UnixMilli(endTime).Format(time.RFC3339)
+// CountKlines 計算範圍內的 K 線數量
+// 已存儲的 K 線直接在 klines_<interval> 計數，只有第一根存儲 K 線之前和最後一根之後的區段從原始 tick 聚合計數，
+// 不掃描整個範圍的原始 tick；存儲 K 線之間的缺口（降採樣失敗的區段）不計入
+func (r *InfluxDBRepository) CountKlines(ctx context.Context, symbol model.Symbol, interval string, startTime, endTime int64) (int, error) {
+	count, first, last, err := r.countStoredKlines(ctx, symbol, interval, startTime, endTime)
+	if err != nil {
+		return 0, err
+	}
+
+	gaps := []timeRange{{start: startTime, end: endTime}}
+	if count > 0 {
+		gaps = nil
+		if first > startTime {
+			gaps = append(gaps, timeRange{start: startTime, end: first})
+		}
+		if next := last + model.Interval(interval).Duration().Milliseconds(); next < endTime {
+			gaps = append(gaps, timeRange{start: next, end: endTime})
+		}
+	}
+
+	for _, gap := range gaps {
+		n, err := r.countRawKlines(ctx, symbol, interval, gap.start, gap.end)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+
+	return count, nil
+}
+
+// countStoredKlines 計算範圍內已存儲的 K 線數量，以及第一根和最後一根的開始時間（Unix 毫秒）
+func (r *InfluxDBRepository) countStoredKlines(ctx context.Context, symbol model.Symbol, interval string, startTime, endTime int64) (count int, first, last int64, err error) {
+	if err := r.checkPoints(startTime, endTime, model.Interval(interval).Duration()); err != nil {
+		return 0, 0, 0, err
+	}
+
+	q := newFluxQuery().
+		Raw("data = ").
+		From(r.bucket).
+		Range(time.UnixMilli(startTime), time.UnixMilli(endTime)).
+		Measurement(klineMeasurement(interval)).
+		Symbol(symbol).
+		Field("close")
+
+	q.Raw(`
+data |> count() |> yield(name: "count")
+data |> first() |> yield(name: "first")
+data |> last() |> yield(name: "last")
+`)
+
+	err = r.query(ctx, q, func(result *api.QueryTableResult) error {
+		for result.Next() {
+			record := result.Record()
+			switch record.Result() {
+			case "count":
+				if value, ok := record.Value().(int64); ok {
+					count = int(value)
+				}
+			case "first":
+				first = record.Time().UnixMilli()
+			case "last":
+				last = record.Time().UnixMilli()
+			}
+		}
+		return result.Err()
+	})
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("查詢已存儲 K 線數量失敗: %w", err)
+	}
+
+	return count, first, last, nil
+}
+
+// countRawKlines 計算原始 tick 在範圍內可聚合出的 K 線數量，只在 InfluxDB 端計算並返回一個數值
+func (r *InfluxDBRepository) countRawKlines(ctx context.Context, symbol model.Symbol, interval string, startTime, endTime int64) (int, error) {
+	fluxInterval, err := convertIntervalToFlux(interval)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.checkRawRange(startTime, endTime); err != nil {
+		return 0, err
+	}
 
+	q := newFluxQuery().
+		From(r.bucket).
+		Range(time.UnixMilli(startTime), time.UnixMilli(endTime)).
+		Measurement(pricesMeasurement).
+		Symbol(symbol).
+		Field("price")
+
+	every := q.Duration("every", fluxInterval)
+	q.Pipe(fmt.Sprintf(`aggregateWindow(every: %s, fn: count, createEmpty: false, timeSrc: "_start")`, every)).
+		Pipe("count()")
+
+	var count int
+	err = r.query(ctx, q, func(result *api.QueryTableResult) error {
+		for result.Next() {
+			if value, ok := result.Record().Value().(int64); ok {
+				count = int(value)
+			}
+		}
+		return result.Err()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("查詢 K 線數量失敗: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetLastCloseBefore 獲取指定時間之前（lookback 範圍內）的最後成交價，用於填補範圍開頭的缺口
+func (r *InfluxDBRepository) GetLastCloseBefore(ctx context.Context, symbol model.Symbol, before int64, lookback time.Duration) (float64, bool, error) {
+	stop := time.UnixMilli(before)
+
+	q := newFluxQuery().
+		From(r.bucket).
+		Range(stop.Add(-lookback), stop).
+		Measurement(pricesMeasurement).
+		Symbol(symbol).
+		Field("price").
+		Pipe("last()")
+
+	var price float64
+	var found bool
+	err := r.query(ctx, q, func(result *api.QueryTableResult) error {
+		for result.Next() {
+			if value, ok := result.Record().Value().(float64); ok {
+				price, found = value, true
+				return nil
+			}
+		}
+		return result.Err()
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("查詢前一收盤價失敗: %w", err)
+	}
+
+	return price, found, nil
+}
+
+// GetTicks 讀取範圍 [start, end) 內依時間升序的前 limit 筆原始 tick
+func (r *InfluxDBRepository) GetTicks(ctx context.Context, symbol model.Symbol, start, end time.Time, limit int) ([]*model.Price, error) {
+	if limit <= 0 || (r.opts.MaxPoints > 0 && limit > r.opts.MaxPoints) {
+		return nil, fmt.Errorf("無效的查詢筆數: %d", limit)
+	}
+
+	q := newFluxQuery().
+		From(r.bucket).
+		Range(start, end).
+		Measurement(pricesMeasurement).
+		Symbol(symbol).
+		Pipe(`pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")`).
+		Pipe(`sort(columns: ["_time"])`).
+		Limit(limit)
+
+	var ticks []*model.Price
+	err := r.query(ctx, q, func(result *api.QueryTableResult) error {
+		for result.Next() {
+			record := result.Record()
+			values := record.Values()
+			ticks = append(ticks, &model.Price{
+				Symbol:        symbol,
+				Price:         getFloat64Value(values, "price"),
+				Timestamp:     record.Time(),
+				Change:        getFloat64Value(values, "change"),
+				ChangePercent: getFloat64Value(values, "change_percent"),
+			})
+		}
+		return result.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查詢原始 tick 失敗: %w", err)
+	}
+
+	return ticks, nil
+}
+
+// GetStatistics 計算範圍 [start, end) 內的市場統計
+// 所有統計在 InfluxDB 端以原始 tick 計算，只返回彙總值
+// VWAP 為 Σ(價格×成交量)/Σ成交量，沒有 volume 欄位的舊 tick 不計入；已實現波動率以 1 分鐘收盤價的對數報酬計算
+func (r *InfluxDBRepository) GetStatistics(ctx context.Context, symbol model.Symbol, start, end time.Time) (*model.Statistics, error) {
+	if err := r.checkRawRange(start.UnixMilli(), end.UnixMilli()); err != nil {
+		return nil, err
+	}
+
+	q := newFluxQuery().
+		Import("math").
+		Raw("ticks = ").
+		From(r.bucket).
+		Range(start, end).
+		Measurement(pricesMeasurement).
+		Symbol(symbol)
+
+	q.Raw(`
+data = ticks |> filter(fn: (r) => r["_field"] == "price")
+
+data |> first() |> yield(name: "open")
+data |> max() |> yield(name: "high")
+data |> min() |> yield(name: "low")
+data |> last() |> yield(name: "close")
+data |> count() |> yield(name: "count")
+data |> mean() |> yield(name: "mean")
+data |> timeWeightedAvg(unit: 1s) |> yield(name: "twap")
+
+data
+	|> aggregateWindow(every: 1m, fn: last, createEmpty: false)
+	|> map(fn: (r) => ({r with _value: math.log(x: r._value)}))
+	|> difference()
+	|> map(fn: (r) => ({r with _value: r._value * r._value}))
+	|> sum()
+	|> map(fn: (r) => ({r with _value: math.sqrt(x: r._value)}))
+	|> yield(name: "volatility")
+
+weighted = ticks
+	|> filter(fn: (r) => r["_field"] == "price" or r["_field"] == "volume")
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> filter(fn: (r) => exists r.price and exists r.volume)
+
+weighted |> map(fn: (r) => ({r with _value: r.volume})) |> sum() |> yield(name: "volume")
+weighted |> map(fn: (r) => ({r with _value: r.price * r.volume})) |> sum() |> yield(name: "turnover")
+`)
+
+	values := make(map[string]float64)
+	err := r.query(ctx, q, func(result *api.QueryTableResult) error {
+		for result.Next() {
+			record := result.Record()
+			values[record.Result()] = getFloat64Value(record.Values(), "_value")
+		}
+		return result.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查詢統計資料失敗: %w", err)
+	}
+
+	if values["count"] == 0 {
+		return nil, fmt.Errorf("%w: 範圍內沒有 %s 的價格資料", model.ErrNoData, symbol)
+	}
+
+	stats := &model.Statistics{
+		Symbol:     symbol,
+		StartTime:  start,
+		EndTime:    end,
+		Open:       values["open"],
+		High:       values["high"],
+		Low:        values["low"],
+		Close:      values["close"],
+		Mean:       values["mean"],
+		TWAP:       values["twap"],
+		TickCount:  int64(values["count"]),
+		Volatility: values["volatility"],
+		Volume:     values["volume"],
+	}
+
+	// 範圍內沒有成交量時 VWAP 無意義，保持 0
+	if stats.Volume > 0 {
+		stats.VWAP = values["turnover"] / stats.Volume
+	}
+
+	// 只有一筆 tick 時 timeWeightedAvg 沒有輸出，以該價格作為 TWAP
+	if _, ok := values["twap"]; !ok {
+		stats.TWAP = stats.Close
+	}
+
+	stats.Change = stats.Close - stats.Open
+	if stats.Open != 0 {
+		stats.ChangePercent = stats.Change / stats.Open * 100
+	}
+
+	return stats, nil
+}
+
+// AggregateKlines 從原始 tick 即時聚合 K 線資料，依 desc 決定時間排序
+// limit <= 0 表示不限制數量；不論 limit，範圍都不可超過 MaxPoints 根 K 線和 MaxRawRange
+func (r *InfluxDBRepository) AggregateKlines(ctx context.Context, symbol model.Symbol, interval string, startTime, endTime int64, desc bool, limit int) ([]*model.Kline, error) {
 	// 轉換時間間隔為 Flux 格式
-	fluxInterval := convertIntervalToFlux(interval)
+	fluxInterval, err := convertIntervalToFlux(interval)
+	if err != nil {
+		return nil, err
+	}
 
-	// Flux 查詢語句 - 分別計算 OHLC
-	query := fmt.Sprintf(`
-		data = from(bucket: "%s")
-			|> range(start: %s, stop: %s)
-			|> filter(fn: (r) => r["_measurement"] == "prices")
-			|> filter(fn: (r) => r["symbol"] == "%s")
-			|> filter(fn: (r) => r["_field"] == "price")
+	// 限制掃描的範圍而非返回的數量：limit 只在 InfluxDB 聚合整個範圍後才生效
+	if err := r.checkPoints(startTime, endTime, model.Interval(interval).Duration()); err != nil {
+		return nil, err
+	}
+	if err := r.checkRawRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > r.opts.MaxPoints {
+		limit = r.opts.MaxPoints
+	}
+
+	// Flux 查詢語句 - 分別計算 OHLC 和成交量，以視窗開始時間作為 K 線時間
+	q := newFluxQuery().
+		Raw("ticks = ").
+		From(r.bucket).
+		Range(time.UnixMilli(startTime), time.UnixMilli(endTime)).
+		Measurement(pricesMeasurement).
+		Symbol(symbol)
+
+	every := q.Duration("every", fluxInterval)
+	q.Raw(fmt.Sprintf(`
+data = ticks |> filter(fn: (r) => r["_field"] == "price")
+
+open = data
+	|> aggregateWindow(every: %[1]s, fn: first, createEmpty: false, timeSrc: "_start")
+	|> set(key: "_field", value: "open")
+
+high = data
+	|> aggregateWindow(every: %[1]s, fn: max, createEmpty: false, timeSrc: "_start")
+	|> set(key: "_field", value: "high")
+
+low = data
+	|> aggregateWindow(every: %[1]s, fn: min, createEmpty: false, timeSrc: "_start")
+	|> set(key: "_field", value: "low")
+
+close = data
+	|> aggregateWindow(every: %[1]s, fn: last, createEmpty: false, timeSrc: "_start")
+	|> set(key: "_field", value: "close")
+
+volume = ticks
+	|> filter(fn: (r) => r["_field"] == "volume")
+	|> aggregateWindow(every: %[1]s, fn: sum, createEmpty: false, timeSrc: "_start")
+
+union(tables: [open, high, low, close, volume])
+`, every)).
+		Pipe(`pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")`).
+		Pipe(sortByTime(desc))
+	if limit > 0 {
+		q.Limit(limit)
+	}
+
+	var klines []*model.Kline
+	err = r.query(ctx, q, func(result *api.QueryTableResult) (err error) {
+		klines, err = readKlines(result)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查詢 K 線失敗: %w", err)
+	}
+
+	return klines, nil
+}
 
-		open = data
-			|> aggregateWindow(every: %s, fn: first, createEmpty: false)
-			|> set(key: "_field", value: "open")
+// GetStoredKlines 讀取降採樣後已存儲的 K 線，依 desc 決定時間排序
+// limit <= 0 表示不限制數量；不論 limit，範圍都不可超過 MaxPoints 根 K 線
+func (r *InfluxDBRepository) GetStoredKlines(ctx context.Context, symbol model.Symbol, interval string, startTime, endTime int64, desc bool, limit int) ([]*model.Kline, error) {
+	if err := r.checkPoints(startTime, endTime, model.Interval(interval).Duration()); err != nil {
+		return nil, err
+	}
 
-		high = data
-			|> aggregateWindow(every: %s, fn: max, createEmpty: false)
-			|> set(key: "_field", value: "high")
+	if limit <= 0 || limit > r.opts.MaxPoints {
+		limit = r.opts.MaxPoints
+	}
 
-		low = data
-			|> aggregateWindow(every: %s, fn: min, createEmpty: false)
-			|> set(key: "_field", value: "low")
+	q := newFluxQuery().
+		From(r.bucket).
+		Range(time.UnixMilli(startTime), time.UnixMilli(endTime)).
+		Measurement(klineMeasurement(interval)).
+		Symbol(symbol).
+		Pipe(`pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")`).
+		Pipe(sortByTime(desc))
+	if limit > 0 {
+		q.Limit(limit)
+	}
 
-		close = data
-			|> aggregateWindow(every: %s, fn: last, createEmpty: false)
-			|> set(key: "_field", value: "close")
+	var klines []*model.Kline
+	err := r.query(ctx, q, func(result *api.QueryTableResult) (err error) {
+		klines, err = readKlines(result)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("查詢已存儲 K 線失敗: %w", err)
+	}
 
-		union(tables: [open, high, low, close])
-			|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
-			|> limit(n: %d)
-	`, r.bucket, start, end, string(symbol), fluxInterval, fluxInterval, fluxInterval, fluxInterval, limit)
+	return klines, nil
+}
 
-	// 執行查詢
-	result, err := r.queryAPI.Query(ctx, query)
+// GetLastStoredKlineTime 獲取最後一根已存儲 K 線的開始時間，lookback 內沒有資料時返回零值
+func (r *InfluxDBRepository) GetLastStoredKlineTime(ctx context.Context, symbol model.Symbol, interval string, lookback time.Duration) (time.Time, error) {
+	q := newFluxQuery().
+		From(r.bucket).
+		RangeSince(lookback).
+		Measurement(klineMeasurement(interval)).
+		Symbol(symbol).
+		Field("close").
+		Pipe("last()")
+
+	var last time.Time
+	err := r.query(ctx, q, func(result *api.QueryTableResult) error {
+		for result.Next() {
+			if t := result.Record().Time(); t.After(last) {
+				last = t
+			}
+		}
+		return result.Err()
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("查詢最後 K 線時間失敗: %w", err)
+	}
+
+	return last, nil
+}
+
+// WriteKlines 寫入降採樣後的 K 線
+func (r *InfluxDBRepository) WriteKlines(ctx context.Context, symbol model.Symbol, interval string, klines []*model.Kline) error {
+	if len(klines) == 0 {
+		return nil
+	}
+
+	points := make([]*write.Point, 0, len(klines))
+	for _, kline := range klines {
+		points = append(points, write.NewPointWithMeasurement(klineMeasurement(interval)).
+			AddTag("symbol", string(symbol)).
+			AddField("open", kline.Open).
+			AddField("high", kline.High).
+			AddField("low", kline.Low).
+			AddField("close", kline.Close).
+			AddField("volume", kline.Volume).
+			SetTime(kline.Timestamp))
+	}
+
+	if err := r.writeAPIBlock.WritePoint(ctx, points...); err != nil {
+		return fmt.Errorf("寫入 K 線失敗: %v", err)
+	}
+
+	return nil
+}
+
+// query 以單次查詢逾時執行 Flux 查詢，fn 在逾時內讀取結果
+func (r *InfluxDBRepository) query(ctx context.Context, q *fluxQuery, fn func(*api.QueryTableResult) error) error {
+	text, params, err := q.Build(!r.opts.ServerParams)
+	if err != nil {
+		return err
+	}
+
+	if r.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.opts.Timeout)
+		defer cancel()
+	}
+
+	var result *api.QueryTableResult
+	if params != nil {
+		result, err = r.queryAPI.QueryWithParams(ctx, text, params)
+	} else {
+		result, err = r.queryAPI.Query(ctx, text)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("查詢 K 線失敗: %v", err)
+		return err
 	}
+	defer result.Close()
 
+	return fn(result)
+}
+
+// checkPoints 檢查範圍 [startTime, endTime)（Unix 毫秒）涵蓋的 K 線數量是否超過單次查詢上限
+// 以毫秒整數計算，避免極大的範圍轉換為 time.Duration 時溢位
+func (r *InfluxDBRepository) checkPoints(startTime, endTime int64, step time.Duration) error {
+	if step.Milliseconds() <= 0 || r.opts.MaxPoints <= 0 || endTime <= startTime {
+		return nil
+	}
+
+	span := endTime - startTime
+	if span < 0 {
+		// 相減溢位，範圍遠超過任何上限
+		return fmt.Errorf("%w: 查詢範圍過大", model.ErrInvalidQuery)
+	}
+
+	if points := span / step.Milliseconds(); points > int64(r.opts.MaxPoints) {
+		return fmt.Errorf("%w: 查詢範圍過大: 預估 %d 個資料點，上限 %d", model.ErrInvalidQuery, points, r.opts.MaxPoints)
+	}
+
+	return nil
+}
+
+// checkRawRange 檢查需要掃描原始 tick 的範圍是否超過 MaxRawRange
+func (r *InfluxDBRepository) checkRawRange(startTime, endTime int64) error {
+	if r.opts.MaxRawRange <= 0 || endTime <= startTime {
+		return nil
+	}
+
+	// time.Time 相減在溢位時會飽和為最大的 Duration
+	if span := time.UnixMilli(endTime).Sub(time.UnixMilli(startTime)); span > r.opts.MaxRawRange {
+		return fmt.Errorf("%w: 原始 tick 查詢範圍 %s 超過上限 %s", model.ErrInvalidQuery, span, r.opts.MaxRawRange)
+	}
+
+	return nil
+}
+
+// readKlines 從 pivot 後的查詢結果中讀取 K 線
+func readKlines(result *api.QueryTableResult) ([]*model.Kline, error) {
 	var klines []*model.Kline
 	for result.Next() {
 		record := result.Record()
@@ -159,37 +684,98 @@ func (r *InfluxDBRepository) GetKlines(ctx context.Context, symbol model.Symbol,
 			High:      getFloat64Value(values, "high"),
 			Low:       getFloat64Value(values, "low"),
 			Close:     getFloat64Value(values, "close"),
-			Volume:    0, // 模擬器暫時不生成成交量
+			Volume:    getFloat64Value(values, "volume"), // 沒有 volume 欄位的舊 tick 為 0
 		}
 		klines = append(klines, kline)
 	}
 
 	if result.Err() != nil {
-		return nil, fmt.Errorf("讀取查詢結果失敗: %v", result.Err())
+		return nil, fmt.Errorf("讀取查詢結果失敗: %w", result.Err())
 	}
 
 	return klines, nil
 }
 
+// timeRange Unix 毫秒時間範圍 [start, end)
+type timeRange struct {
+	start int64
+	end   int64
+}
+
+// klineGaps 找出 [start, end) 內沒有已存儲 K 線的區段，klines 必須依時間升序
+func klineGaps(klines []*model.Kline, start, end int64, step time.Duration) []timeRange {
+	var gaps []timeRange
+	next := start
+	for _, kline := range klines {
+		ts := kline.Timestamp.UnixMilli()
+		if ts > next {
+			gaps = append(gaps, timeRange{start: next, end: ts})
+		}
+		next = max(next, kline.Timestamp.Add(step).UnixMilli())
+	}
+	if next < end {
+		gaps = append(gaps, timeRange{start: next, end: end})
+	}
+	return gaps
+}
+
+// mergeKlines 合併已存儲和原始 tick 聚合的 K 線並依時間升序排列，同一時間以已存儲的為準
+func mergeKlines(stored, raw []*model.Kline) []*model.Kline {
+	seen := make(map[int64]bool, len(stored))
+	for _, kline := range stored {
+		seen[kline.Timestamp.UnixMilli()] = true
+	}
+
+	merged := stored
+	for _, kline := range raw {
+		if !seen[kline.Timestamp.UnixMilli()] {
+			merged = append(merged, kline)
+		}
+	}
+	sortKlines(merged)
+	return merged
+}
+
+// sortByTime 依時間排序的管線階段
+func sortByTime(desc bool) string {
+	if desc {
+		return `sort(columns: ["_time"], desc: true)`
+	}
+	return `sort(columns: ["_time"])`
+}
+
+// sortKlines 將 K 線依時間升序排列
+func sortKlines(klines []*model.Kline) {
+	sort.Slice(klines, func(i, j int) bool {
+		return klines[i].Timestamp.Before(klines[j].Timestamp)
+	})
+}
+
+// trimKlines 從依時間升序的 K 線中保留 desc 對應一端的 limit 根
+func trimKlines(klines []*model.Kline, desc bool, limit int) []*model.Kline {
+	if limit <= 0 || len(klines) <= limit {
+		return klines
+	}
+	if desc {
+		return klines[len(klines)-limit:]
+	}
+	return klines[:limit]
+}
+
+// klineMeasurement 降採樣 K 線的 measurement 名稱
+func klineMeasurement(interval string) string {
+	return "klines_" + interval
+}
+
 // convertIntervalToFlux 將時間間隔轉換為 Flux 格式
-func convertIntervalToFlux(interval string) string {
-	switch interval {
-	case "1m":
-		return "1m"
-	case "5m":
-		return "5m"
-	case "15m":
-		return "15m"
-	case "30m":
-		return "30m"
-	case "1h":
-		return "1h"
-	case "4h":
-		return "4h"
-	case "1d":
-		return "1d"
+// 只支援可直接用 aggregateWindow 聚合的固定長度週期，週線、月線由 service 層彙總
+func convertIntervalToFlux(interval string) (string, error) {
+	switch model.Interval(interval) {
+	case model.Interval1s, model.Interval1m, model.Interval3m, model.Interval5m, model.Interval15m,
+		model.Interval30m, model.Interval1h, model.Interval2h, model.Interval4h, model.Interval1d:
+		return interval, nil
 	default:
-		return "1m" // 預設 1 分鐘
+		return "", fmt.Errorf("不支援的 Flux 聚合週期: %s", interval)
 	}
 }
 
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+// minute 以分鐘表示的 Unix 毫秒時間
+func minute(n int) int64 {
+	return int64(n) * time.Minute.Milliseconds()
+}
+
+// klinesAt 建立指定分鐘的 K 線，close 為分鐘數
+func klinesAt(minutes ...int) []*model.Kline {
+	klines := make([]*model.Kline, 0, len(minutes))
+	for _, m := range minutes {
+		klines = append(klines, &model.Kline{Timestamp: time.UnixMilli(minute(m)), Close: float64(m)})
+	}
+	return klines
+}
+
+// klineMinutes 返回 K 線的分鐘數
+func klineMinutes(klines []*model.Kline) []int {
+	minutes := make([]int, 0, len(klines))
+	for _, kline := range klines {
+		minutes = append(minutes, int(kline.Timestamp.UnixMilli()/time.Minute.Milliseconds()))
+	}
+	return minutes
+}
+
+func TestKlineGaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored []int
+		start  int64
+		end    int64
+		want   []timeRange
+	}{
+		{
+			name:  "nothing stored",
+			start: minute(0),
+			end:   minute(10),
+			want:  []timeRange{{minute(0), minute(10)}},
+		},
+		{
+			name:   "fully stored",
+			stored: []int{0, 1, 2},
+			start:  minute(0),
+			end:    minute(3),
+		},
+		{
+			name:   "head, middle and tail",
+			stored: []int{2, 3, 6},
+			start:  minute(0),
+			end:    minute(9),
+			want:   []timeRange{{minute(0), minute(2)}, {minute(4), minute(6)}, {minute(7), minute(9)}},
+		},
+		{
+			name:   "unaligned start",
+			stored: []int{1, 2},
+			start:  minute(0) + 30000,
+			end:    minute(3),
+			want:   []timeRange{{minute(0) + 30000, minute(1)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := klineGaps(klinesAt(tt.stored...), tt.start, tt.end, time.Minute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("klineGaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeKlines(t *testing.T) {
+	stored := klinesAt(1, 3)
+	raw := klinesAt(0, 1, 2, 4)
+	raw[1].Close = -1 // 與存儲 K 線同一時間
+
+	merged := mergeKlines(stored, raw)
+	if got, want := klineMinutes(merged), []int{0, 1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("mergeKlines() minutes = %v, want %v", got, want)
+	}
+	if merged[1].Close != 1 {
+		t.Errorf("duplicate minute close = %v, want the stored kline", merged[1].Close)
+	}
+}
+
+func TestTrimKlines(t *testing.T) {
+	tests := []struct {
+		name  string
+		desc  bool
+		limit int
+		want  []int
+	}{
+		{name: "unlimited", limit: 0, want: []int{0, 1, 2, 3}},
+		{name: "oldest", limit: 2, want: []int{0, 1}},
+		{name: "latest", desc: true, limit: 2, want: []int{2, 3}},
+		{name: "limit above length", desc: true, limit: 10, want: []int{0, 1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := klineMinutes(trimKlines(klinesAt(0, 1, 2, 3), tt.desc, tt.limit))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("trimKlines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPoints(t *testing.T) {
+	r := &InfluxDBRepository{opts: QueryOptions{MaxPoints: 1000, MaxRawRange: 24 * time.Hour}}
+
+	tests := []struct {
+		name    string
+		start   int64
+		end     int64
+		step    time.Duration
+		wantErr bool
+	}{
+		{name: "within the limit", start: 0, end: minute(1000), step: time.Minute},
+		{name: "over the limit", start: 0, end: minute(1001), step: time.Minute, wantErr: true},
+		{name: "multi-century range does not overflow", start: -1 << 62, end: 1 << 62, step: time.Minute, wantErr: true},
+		{name: "subtraction overflow", start: math.MinInt64 + 1, end: math.MaxInt64, step: time.Minute, wantErr: true},
+		{name: "empty range", start: minute(10), end: minute(10), step: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.checkPoints(tt.start, tt.end, tt.step)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkPoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, model.ErrInvalidQuery) {
+				t.Errorf("checkPoints() error = %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+}
+
+func TestCheckRawRange(t *testing.T) {
+	r := &InfluxDBRepository{opts: QueryOptions{MaxRawRange: 24 * time.Hour}}
+
+	tests := []struct {
+		name    string
+		start   int64
+		end     int64
+		wantErr bool
+	}{
+		{name: "within the limit", start: 0, end: minute(24 * 60)},
+		{name: "over the limit", start: 0, end: minute(24*60 + 1), wantErr: true},
+		{name: "multi-century range", start: -1 << 62, end: 1 << 62, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.checkRawRange(tt.start, tt.end); (err != nil) != tt.wantErr {
+				t.Errorf("checkRawRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+var (
+	// identifierPattern measurement / field 名稱只允許小寫英數和底線
+	identifierPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+	// symbolPattern 商品代碼只允許大寫英數和底線
+	symbolPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,31}$`)
+
+	// fluxDurationPattern Flux duration 字面值，例如 1s、15m、1d
+	fluxDurationPattern = regexp.MustCompile(`^[0-9]{1,6}(ms|s|m|h|d|w|mo)$`)
+)
+
+// fluxQuery 參數化 Flux 查詢
+// 查詢本體只包含程式碼中的常數，所有外部輸入都透過 params 傳入，並在加入前驗證
+// 預設（InfluxDB OSS）以 Build(true) 在查詢開頭宣告 params 記錄，這是支援的使用方式：
+// 參數值經過格式驗證並以 fluxString 跳脫，只出現在 params 宣告中，不會改變查詢本體的結構
+type fluxQuery struct {
+	imports []string
+	text    strings.Builder
+	params  map[string]interface{}
+	err     error
+}
+
+// newFluxQuery 創建 Flux 查詢
+func newFluxQuery() *fluxQuery {
+	return &fluxQuery{params: make(map[string]interface{})}
+}
+
+// Raw 加入程式碼中的 Flux 常數片段，不可包含任何外部輸入
+func (q *fluxQuery) Raw(text string) *fluxQuery {
+	q.text.WriteString(text)
+	return q
+}
+
+// Import 匯入 Flux 標準函式庫套件，例如 math
+func (q *fluxQuery) Import(pkg string) *fluxQuery {
+	q.imports = append(q.imports, pkg)
+	return q
+}
+
+// From 從 bucket 讀取資料
+func (q *fluxQuery) From(bucket string) *fluxQuery {
+	return q.Raw(fmt.Sprintf("from(bucket: %s)\n", q.param("bucket", bucket)))
+}
+
+// Range 限制絕對時間範圍 [start, stop)
+func (q *fluxQuery) Range(start, stop time.Time) *fluxQuery {
+	if !start.Before(stop) {
+		q.fail(fmt.Errorf("%w: 查詢範圍 %s ~ %s", model.ErrInvalidQuery, start.Format(time.RFC3339), stop.Format(time.RFC3339)))
+	}
+
+	return q.Raw(fmt.Sprintf("\t|> range(start: time(v: %s), stop: time(v: %s))\n",
+		q.param("start", start.UTC().Format(time.RFC3339Nano)),
+		q.param("stop", stop.UTC().Format(time.RFC3339Nano))))
+}
+
+// RangeSince 限制為最近 lookback 內的資料
+func (q *fluxQuery) RangeSince(lookback time.Duration) *fluxQuery {
+	if lookback <= 0 {
+		q.fail(fmt.Errorf("%w: 查詢範圍 %s", model.ErrInvalidQuery, lookback))
+	}
+
+	return q.Raw(fmt.Sprintf("\t|> range(start: duration(v: %s))\n", q.param("lookback", fmt.Sprintf("-%dms", lookback.Milliseconds()))))
+}
+
+// Measurement 過濾 measurement
+func (q *fluxQuery) Measurement(name string) *fluxQuery {
+	if !identifierPattern.MatchString(name) {
+		q.fail(fmt.Errorf("無效的 measurement 名稱: %q", name))
+	}
+
+	return q.Raw(fmt.Sprintf("\t|> filter(fn: (r) => r[\"_measurement\"] == %s)\n", q.param("measurement", name)))
+}
+
+// Symbol 過濾商品代碼
+func (q *fluxQuery) Symbol(symbol model.Symbol) *fluxQuery {
+	if !symbolPattern.MatchString(string(symbol)) {
+		q.fail(fmt.Errorf("無效的商品代碼: %q", symbol))
+	}
+
+	return q.Raw(fmt.Sprintf("\t|> filter(fn: (r) => r[\"symbol\"] == %s)\n", q.param("symbol", string(symbol))))
+}
+
+// Field 過濾欄位
+func (q *fluxQuery) Field(name string) *fluxQuery {
+	if !identifierPattern.MatchString(name) {
+		q.fail(fmt.Errorf("無效的欄位名稱: %q", name))
+	}
+
+	return q.Raw(fmt.Sprintf("\t|> filter(fn: (r) => r[\"_field\"] == %s)\n", q.param("field", name)))
+}
+
+// Pipe 加入程式碼中的常數管線階段，例如 last()
+func (q *fluxQuery) Pipe(stage string) *fluxQuery {
+	return q.Raw("\t|> " + stage + "\n")
+}
+
+// Limit 限制返回筆數
+func (q *fluxQuery) Limit(n int) *fluxQuery {
+	return q.Raw(fmt.Sprintf("\t|> limit(n: %s)\n", q.param("limit", n)))
+}
+
+// Duration 註冊 Flux duration 參數（例如 1m），返回可在查詢中引用的表達式
+func (q *fluxQuery) Duration(name, value string) string {
+	if !fluxDurationPattern.MatchString(value) {
+		q.fail(fmt.Errorf("無效的時間長度 %s: %q", name, value))
+	}
+
+	return fmt.Sprintf("duration(v: %s)", q.param(name, value))
+}
+
+// Build 產生查詢文字和參數
+// inline 為 true 時在查詢開頭宣告 params 記錄（InfluxDB OSS 不支援伺服器端參數），參數值以跳脫後的字面值寫入
+func (q *fluxQuery) Build(inline bool) (string, map[string]interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+
+	// import 必須位於查詢最前面
+	var header strings.Builder
+	for _, pkg := range q.imports {
+		header.WriteString("import " + fluxString(pkg) + "\n")
+	}
+
+	if !inline {
+		return header.String() + q.text.String(), q.params, nil
+	}
+
+	return header.String() + inlineParams(q.params) + q.text.String(), nil, nil
+}
+
+// param 註冊參數，返回 Flux 中的引用
+func (q *fluxQuery) param(name string, value interface{}) string {
+	if existing, ok := q.params[name]; ok && existing != value {
+		q.fail(fmt.Errorf("重複的查詢參數: %s", name))
+	}
+
+	q.params[name] = value
+	return "params." + name
+}
+
+// fail 記錄第一個錯誤
+func (q *fluxQuery) fail(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// inlineParams 將參數轉換為 Flux 記錄宣告
+func inlineParams(params map[string]interface{}) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]string, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, fmt.Sprintf("%s: %s", name, fluxLiteral(params[name])))
+	}
+
+	return "params = {" + strings.Join(fields, ", ") + "}\n"
+}
+
+// fluxLiteral 將參數值轉換為 Flux 字面值
+func fluxLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fluxString(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fluxString(fmt.Sprint(v))
+	}
+}
+
+// fluxString 產生跳脫後的 Flux 字串字面值，包含字串插值符號 ${
+func fluxString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\', '"', '$':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
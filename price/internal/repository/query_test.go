@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+func TestFluxQueryValidation(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		build   func(q *fluxQuery)
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			build: func(q *fluxQuery) {
+				q.From("prices").Range(start, start.Add(time.Hour)).Measurement("klines_1m").Symbol(model.SymbolGold).Field("close")
+			},
+		},
+		{name: "empty range", build: func(q *fluxQuery) { q.Range(start, start) }, wantErr: true},
+		{name: "negative lookback", build: func(q *fluxQuery) { q.RangeSince(-time.Minute) }, wantErr: true},
+		{name: "measurement injection", build: func(q *fluxQuery) { q.Measurement(`prices") or (r["_measurement"] == "x`) }, wantErr: true},
+		{name: "lowercase symbol", build: func(q *fluxQuery) { q.Symbol("gold") }, wantErr: true},
+		{name: "field with spaces", build: func(q *fluxQuery) { q.Field("close price") }, wantErr: true},
+		{name: "invalid duration", build: func(q *fluxQuery) { q.Duration("every", "1m; drop()") }, wantErr: true},
+		{name: "conflicting parameter", build: func(q *fluxQuery) { q.Measurement("prices").Measurement("klines_1m") }, wantErr: true},
+		{name: "repeated parameter with the same value", build: func(q *fluxQuery) { q.Symbol(model.SymbolGold).Symbol(model.SymbolGold) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newFluxQuery()
+			tt.build(q)
+			if _, _, err := q.Build(true); (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFluxQueryBuild(t *testing.T) {
+	q := newFluxQuery().
+		Import("math").
+		From("prices").
+		Symbol(model.SymbolGold).
+		Limit(10)
+
+	// 伺服器端參數：查詢本體只引用 params
+	text, params, err := q.Build(false)
+	if err != nil {
+		t.Fatalf("Build(false) error = %v", err)
+	}
+	if !strings.HasPrefix(text, `import "math"`) || strings.Contains(text, `"GOLD"`) {
+		t.Errorf("Build(false) text = %q, want the import first and no inlined values", text)
+	}
+	if params["symbol"] != "GOLD" || params["limit"] != 10 {
+		t.Errorf("Build(false) params = %v", params)
+	}
+
+	// 內嵌參數：在 import 之後宣告 params 記錄
+	text, params, err = q.Build(true)
+	if err != nil {
+		t.Fatalf("Build(true) error = %v", err)
+	}
+	if params != nil {
+		t.Errorf("Build(true) params = %v, want nil", params)
+	}
+	want := "import \"math\"\nparams = {bucket: \"prices\", limit: 10, symbol: \"GOLD\"}\n"
+	if !strings.HasPrefix(text, want) {
+		t.Errorf("Build(true) text = %q, want prefix %q", text, want)
+	}
+}
+
+func TestFluxString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "prices", want: `"prices"`},
+		{in: `a"b`, want: `"a\"b"`},
+		{in: `a\b`, want: `"a\\b"`},
+		{in: "${x}", want: `"\${x}"`},
+		{in: "a\nb\tc", want: `"a\nb\tc"`},
+	}
+
+	for _, tt := range tests {
+		if got := fluxString(tt.in); got != tt.want {
+			t.Errorf("fluxString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+// klineCursorVersion 游標格式版本，格式變更時遞增
+const klineCursorVersion = "v2"
+
+// klineCursor K 線分頁狀態：上一頁最外側 K 線的開始時間和第一頁計算的 K 線總數
+// 後續頁沿用總數，不重新計算整個範圍
+type klineCursor struct {
+	Boundary int64 // Unix 毫秒
+	Total    int
+}
+
+// encodeKlineCursor 將分頁方向、邊界時間和總數編碼為不透明游標
+func encodeKlineCursor(direction model.KlineDirection, cursor klineCursor) string {
+	raw := fmt.Sprintf("%s:%s:%d:%d", klineCursorVersion, direction, cursor.Boundary, cursor.Total)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeKlineCursor 解析游標
+func decodeKlineCursor(cursor string, direction model.KlineDirection) (klineCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return klineCursor{}, fmt.Errorf("%w: 游標無法解析: %v", model.ErrInvalidQuery, err)
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 || parts[0] != klineCursorVersion {
+		return klineCursor{}, fmt.Errorf("%w: 游標格式錯誤", model.ErrInvalidQuery)
+	}
+
+	if model.KlineDirection(parts[1]) != direction {
+		return klineCursor{}, fmt.Errorf("%w: 游標方向 %s 與請求方向 %s 不符", model.ErrInvalidQuery, parts[1], direction)
+	}
+
+	boundary, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return klineCursor{}, fmt.Errorf("%w: 游標時間錯誤: %v", model.ErrInvalidQuery, err)
+	}
+
+	total, err := strconv.Atoi(parts[3])
+	if err != nil || total < 0 {
+		return klineCursor{}, fmt.Errorf("%w: 游標總數錯誤: %s", model.ErrInvalidQuery, parts[3])
+	}
+
+	return klineCursor{Boundary: boundary, Total: total}, nil
+}
+
+// tickPageTokenVersion 原始 tick 分頁標記的格式版本，格式變更時遞增
+const tickPageTokenVersion = "v2"
+
+// tickPageToken 原始 tick 分頁狀態：第一頁解析後的查詢範圍和上一頁最後一筆 tick 的時間
+type tickPageToken struct {
+	StartTime int64 // Unix 毫秒
+	EndTime   int64 // Unix 毫秒
+	Last      time.Time
+}
+
+// encodeTickPageToken 將查詢範圍和最後一筆 tick 的時間編碼為不透明分頁標記
+func encodeTickPageToken(symbol model.Symbol, token tickPageToken) string {
+	raw := fmt.Sprintf("%s:ticks:%s:%d:%d:%d", tickPageTokenVersion, symbol, token.StartTime, token.EndTime, token.Last.UnixNano())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeTickPageToken 解析分頁標記
+func decodeTickPageToken(token string, symbol model.Symbol) (tickPageToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return tickPageToken{}, fmt.Errorf("%w: 分頁標記無法解析: %v", model.ErrInvalidQuery, err)
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 6 || parts[0] != tickPageTokenVersion || parts[1] != "ticks" {
+		return tickPageToken{}, fmt.Errorf("%w: 分頁標記格式錯誤", model.ErrInvalidQuery)
+	}
+
+	if model.Symbol(parts[2]) != symbol {
+		return tickPageToken{}, fmt.Errorf("%w: 分頁標記商品 %s 與請求商品 %s 不符", model.ErrInvalidQuery, parts[2], symbol)
+	}
+
+	var values [3]int64
+	for i := range values {
+		values[i], err = strconv.ParseInt(parts[3+i], 10, 64)
+		if err != nil {
+			return tickPageToken{}, fmt.Errorf("%w: 分頁標記時間錯誤: %v", model.ErrInvalidQuery, err)
+		}
+	}
+
+	return tickPageToken{StartTime: values[0], EndTime: values[1], Last: time.Unix(0, values[2])}, nil
+}
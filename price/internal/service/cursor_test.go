@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+func TestKlineCursor(t *testing.T) {
+	want := klineCursor{Boundary: 1700000000000, Total: 1440}
+	cursor := encodeKlineCursor(model.KlineDirectionLatest, want)
+
+	tests := []struct {
+		name      string
+		cursor    string
+		direction model.KlineDirection
+		want      klineCursor
+		wantErr   bool
+	}{
+		{name: "round trip", cursor: cursor, direction: model.KlineDirectionLatest, want: want},
+		{name: "direction mismatch", cursor: cursor, direction: model.KlineDirectionOldest, wantErr: true},
+		{name: "bad encoding", cursor: "!!", direction: model.KlineDirectionLatest, wantErr: true},
+		{name: "previous version", cursor: base64.RawURLEncoding.EncodeToString([]byte("v1:latest:1")), direction: model.KlineDirectionLatest, wantErr: true},
+		{name: "bad boundary", cursor: base64.RawURLEncoding.EncodeToString([]byte("v2:latest:abc:1")), direction: model.KlineDirectionLatest, wantErr: true},
+		{name: "bad total", cursor: base64.RawURLEncoding.EncodeToString([]byte("v2:latest:1:-1")), direction: model.KlineDirectionLatest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeKlineCursor(tt.cursor, tt.direction)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeKlineCursor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodeKlineCursor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTickPageToken(t *testing.T) {
+	want := tickPageToken{
+		StartTime: 1700000000000,
+		EndTime:   1700000060000,
+		Last:      time.Unix(1700000030, 123456789),
+	}
+	token := encodeTickPageToken(model.SymbolGold, want)
+
+	tests := []struct {
+		name    string
+		token   string
+		symbol  model.Symbol
+		wantErr bool
+	}{
+		{name: "round trip", token: token, symbol: model.SymbolGold},
+		{name: "symbol mismatch", token: token, symbol: model.SymbolSilver, wantErr: true},
+		{name: "previous version", token: base64.RawURLEncoding.EncodeToString([]byte("v1:ticks:GOLD:1700000030123456789")), symbol: model.SymbolGold, wantErr: true},
+		{name: "bad time", token: base64.RawURLEncoding.EncodeToString([]byte("v2:ticks:GOLD:1:2:x")), symbol: model.SymbolGold, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeTickPageToken(tt.token, tt.symbol)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeTickPageToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.StartTime != want.StartTime || got.EndTime != want.EndTime || !got.Last.Equal(want.Last) {
+				t.Errorf("decodeTickPageToken() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
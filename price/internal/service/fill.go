@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+// maxFilledKlines 單次查詢最多產生的 K 線數量（含填補），避免過大的範圍耗盡記憶體
+const maxFilledKlines = 100000
+
+// klineSlots 返回範圍 [start, end) 內每根 K 線的開始時間
+// includeFirst 為 true 時包含 start 所在、開始時間早於 start 的第一根 K 線
+func klineSlots(interval model.Interval, align model.KlineAlignment, start, end time.Time, includeFirst bool) ([]time.Time, error) {
+	first := interval.Truncate(start, align)
+	if first.Before(start) && !includeFirst {
+		first = interval.Next(first, align)
+	}
+
+	if count := int(end.Sub(first) / interval.Duration()); count > maxFilledKlines {
+		return nil, fmt.Errorf("%w: 查詢範圍過大: %d 根 K 線，上限 %d", model.ErrInvalidQuery, count, maxFilledKlines)
+	}
+
+	var slots []time.Time
+	for slot := first; slot.Before(end); slot = interval.Next(slot, align) {
+		slots = append(slots, slot)
+	}
+
+	return slots, nil
+}
+
+// fillKlines 依填補模式補齊 [start, end) 範圍內缺少的 K 線
+// prevClose 為範圍開頭之前的最後價格，hasPrev 為 false 時 previous 模式不填補開頭的缺口
+func fillKlines(klines []*model.Kline, interval model.Interval, align model.KlineAlignment, start, end time.Time, mode model.KlineFillMode, prevClose float64, hasPrev bool) ([]*model.Kline, error) {
+	if mode == model.KlineFillNone {
+		return klines, nil
+	}
+
+	first := interval.Truncate(start, align)
+	if first.Before(start) {
+		first = interval.Next(first, align)
+	}
+
+	if count := int(end.Sub(first) / interval.Duration()); count > maxFilledKlines {
+		return nil, fmt.Errorf("%w: 填補範圍過大: %d 根 K 線，上限 %d", model.ErrInvalidQuery, count, maxFilledKlines)
+	}
+
+	filled := make([]*model.Kline, 0, len(klines))
+	next := 0
+	for bucket := first; bucket.Before(end); bucket = interval.Next(bucket, align) {
+		// 跳過未對齊到此 bucket 之前的 K 線（理論上不應發生）
+		for next < len(klines) && klines[next].Timestamp.Before(bucket) {
+			filled = append(filled, klines[next])
+			prevClose, hasPrev = klines[next].Close, true
+			next++
+		}
+
+		if next < len(klines) && klines[next].Timestamp.Equal(bucket) {
+			filled = append(filled, klines[next])
+			prevClose, hasPrev = klines[next].Close, true
+			next++
+			continue
+		}
+
+		switch mode {
+		case model.KlineFillPrevious:
+			if !hasPrev {
+				continue
+			}
+			filled = append(filled, &model.Kline{
+				Timestamp: bucket,
+				Open:      prevClose,
+				High:      prevClose,
+				Low:       prevClose,
+				Close:     prevClose,
+				Filled:    true,
+			})
+		case model.KlineFillNull:
+			filled = append(filled, &model.Kline{
+				Timestamp: bucket,
+				Filled:    true,
+				Empty:     true,
+			})
+		}
+	}
+
+	return append(filled, klines[next:]...), nil
+}
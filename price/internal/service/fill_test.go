@@ -0,0 +1,195 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+// base 測試 K 線的起始時間
+var base = time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+// at 返回 base 之後第 n 分鐘
+func at(n int) time.Time {
+	return base.Add(time.Duration(n) * time.Minute)
+}
+
+// kline 建立開始於第 n 分鐘、收盤價為 close 的 K 線
+func kline(n int, close float64) *model.Kline {
+	return &model.Kline{Timestamp: at(n), Open: close, High: close, Low: close, Close: close}
+}
+
+// filledKline 描述預期的 K 線
+type filledKline struct {
+	minute int
+	close  float64
+	filled bool
+	empty  bool
+}
+
+func TestFillKlines(t *testing.T) {
+	tests := []struct {
+		name      string
+		klines    []*model.Kline
+		start     time.Time
+		mode      model.KlineFillMode
+		prevClose float64
+		hasPrev   bool
+		want      []filledKline
+	}{
+		{
+			name:   "none keeps gaps",
+			klines: []*model.Kline{kline(0, 10), kline(2, 12)},
+			start:  at(0),
+			mode:   model.KlineFillNone,
+			want:   []filledKline{{minute: 0, close: 10}, {minute: 2, close: 12}},
+		},
+		{
+			name:   "previous fills with last close",
+			klines: []*model.Kline{kline(0, 10), kline(3, 13)},
+			start:  at(0),
+			mode:   model.KlineFillPrevious,
+			want: []filledKline{
+				{minute: 0, close: 10},
+				{minute: 1, close: 10, filled: true},
+				{minute: 2, close: 10, filled: true},
+				{minute: 3, close: 13},
+			},
+		},
+		{
+			name:      "previous fills the head from the prior close",
+			klines:    []*model.Kline{kline(2, 12)},
+			start:     at(0),
+			mode:      model.KlineFillPrevious,
+			prevClose: 9,
+			hasPrev:   true,
+			want: []filledKline{
+				{minute: 0, close: 9, filled: true},
+				{minute: 1, close: 9, filled: true},
+				{minute: 2, close: 12},
+				{minute: 3, close: 12, filled: true},
+			},
+		},
+		{
+			name:   "previous leaves the head without a prior close",
+			klines: []*model.Kline{kline(2, 12)},
+			start:  at(0),
+			mode:   model.KlineFillPrevious,
+			want:   []filledKline{{minute: 2, close: 12}, {minute: 3, close: 12, filled: true}},
+		},
+		{
+			name:   "null inserts empty klines",
+			klines: []*model.Kline{kline(1, 11)},
+			start:  at(0),
+			mode:   model.KlineFillNull,
+			want: []filledKline{
+				{minute: 0, filled: true, empty: true},
+				{minute: 1, close: 11},
+				{minute: 2, filled: true, empty: true},
+				{minute: 3, filled: true, empty: true},
+			},
+		},
+		{
+			name:   "unaligned start begins at the next bucket",
+			klines: nil,
+			start:  at(0).Add(30 * time.Second),
+			mode:   model.KlineFillNull,
+			want: []filledKline{
+				{minute: 1, filled: true, empty: true},
+				{minute: 2, filled: true, empty: true},
+				{minute: 3, filled: true, empty: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fillKlines(tt.klines, model.Interval1m, model.KlineAlignment{}, tt.start, at(4), tt.mode, tt.prevClose, tt.hasPrev)
+			if err != nil {
+				t.Fatalf("fillKlines() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("fillKlines() returned %d klines, want %d", len(got), len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				k := got[i]
+				if !k.Timestamp.Equal(at(want.minute)) || k.Close != want.close || k.Filled != want.filled || k.Empty != want.empty {
+					t.Errorf("kline %d = {%s close=%v filled=%v empty=%v}, want %+v",
+						i, k.Timestamp.Format(time.TimeOnly), k.Close, k.Filled, k.Empty, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFillKlinesRejectsHugeRanges(t *testing.T) {
+	end := base.Add(time.Duration(maxFilledKlines+1) * time.Second)
+	if _, err := fillKlines(nil, model.Interval1s, model.KlineAlignment{}, base, end, model.KlineFillNull, 0, false); err == nil {
+		t.Fatal("fillKlines() error = nil, want range error")
+	}
+}
+
+func TestKlineSlots(t *testing.T) {
+	tests := []struct {
+		name         string
+		interval     model.Interval
+		start        time.Time
+		end          time.Time
+		includeFirst bool
+		want         []time.Time
+	}{
+		{
+			name:     "aligned",
+			interval: model.Interval1m,
+			start:    at(0),
+			end:      at(3),
+			want:     []time.Time{at(0), at(1), at(2)},
+		},
+		{
+			name:     "unaligned start skips the partial bucket",
+			interval: model.Interval1m,
+			start:    at(0).Add(time.Second),
+			end:      at(3),
+			want:     []time.Time{at(1), at(2)},
+		},
+		{
+			name:         "unaligned start keeps the partial bucket",
+			interval:     model.Interval1m,
+			start:        at(0).Add(time.Second),
+			end:          at(3),
+			includeFirst: true,
+			want:         []time.Time{at(0), at(1), at(2)},
+		},
+		{
+			name:         "calendar months",
+			interval:     model.Interval1M,
+			start:        time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+			end:          time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			includeFirst: true,
+			want: []time.Time{
+				time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := klineSlots(tt.interval, model.KlineAlignment{}, tt.start, tt.end, tt.includeFirst)
+			if err != nil {
+				t.Fatalf("klineSlots() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("klineSlots() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("slot %d = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
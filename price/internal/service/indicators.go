@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"golden-buy/price/internal/indicators"
+	"golden-buy/price/internal/model"
+)
+
+// GetIndicators 計算範圍內最新 Limit 根 K 線的技術指標
+// 與 GetKlines 使用相同的 K 線讀取路徑，並往前多讀取暖機所需的 K 線，讓範圍開頭的指標數值穩定
+// specs 必須已經 Normalize
+func (s *PriceService) GetIndicators(ctx context.Context, query model.KlineQuery, specs []indicators.Spec) (*model.IndicatorSet, error) {
+	warmup := 0
+	for _, spec := range specs {
+		if w := spec.Warmup(); w > warmup {
+			warmup = w
+		}
+	}
+
+	start := time.UnixMilli(query.StartTime)
+	loadQuery := query
+	loadQuery.StartTime = start.Add(-time.Duration(warmup) * query.Interval.Duration()).UnixMilli()
+
+	klines, partial, err := s.loadKlines(ctx, loadQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	closes := make([]float64, len(klines))
+	for i, kline := range klines {
+		closes[i] = kline.Close
+	}
+
+	// 只輸出原始範圍內最新的 Limit 根 K 線
+	first := sort.Search(len(klines), func(i int) bool {
+		return !klines[i].Timestamp.Before(start)
+	})
+	if query.Limit > 0 && len(klines)-first > query.Limit {
+		first = len(klines) - query.Limit
+	}
+
+	set := &model.IndicatorSet{Partial: partial}
+	for _, spec := range specs {
+		result, err := indicators.Compute(spec, closes)
+		if err != nil {
+			return nil, err
+		}
+
+		series := &model.IndicatorSeries{Name: spec.Name, Params: spec.Params}
+		for _, line := range result.Lines {
+			points := make([]model.IndicatorPoint, 0, len(klines)-first)
+			for i := first; i < len(klines); i++ {
+				if !math.IsNaN(line.Values[i]) {
+					points = append(points, model.IndicatorPoint{Timestamp: klines[i].Timestamp, Value: line.Values[i]})
+				}
+			}
+			series.Lines = append(series.Lines, model.IndicatorLine{Name: line.Name, Points: points})
+		}
+		set.Indicators = append(set.Indicators, series)
+	}
+
+	return set, nil
+}
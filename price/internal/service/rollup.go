@@ -0,0 +1,66 @@
+package service
+
+import (
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+// rollupBaseIntervals 彙總日線以上 K 線時可用的來源週期，由大到小
+var rollupBaseIntervals = []model.Interval{
+	model.Interval1h,
+	model.Interval30m,
+	model.Interval15m,
+	model.Interval5m,
+	model.Interval1m,
+}
+
+// rollupBaseInterval 選擇彙總日線以上 K 線的來源週期
+// 預設對齊直接使用 1d；其他時區或交易日開始時間則選擇邊界能整除的最大週期
+func rollupBaseInterval(align model.KlineAlignment, at time.Time) model.Interval {
+	if align.IsDefault() {
+		return model.Interval1d
+	}
+
+	boundary := model.Interval1d.Truncate(at, align)
+	for _, base := range rollupBaseIntervals {
+		if boundary.Truncate(base.Duration()).Equal(boundary) {
+			return base
+		}
+	}
+
+	return model.Interval1m
+}
+
+// rollupKlines 將依時間升序的 K 線彙總為較大週期的 K 線
+func rollupKlines(klines []*model.Kline, interval model.Interval, align model.KlineAlignment) []*model.Kline {
+	var result []*model.Kline
+	var current *model.Kline
+
+	for _, kline := range klines {
+		bucket := interval.Truncate(kline.Timestamp, align)
+		if current == nil || !current.Timestamp.Equal(bucket) {
+			current = &model.Kline{
+				Timestamp: bucket,
+				Open:      kline.Open,
+				High:      kline.High,
+				Low:       kline.Low,
+				Close:     kline.Close,
+				Volume:    kline.Volume,
+			}
+			result = append(result, current)
+			continue
+		}
+
+		if kline.High > current.High {
+			current.High = kline.High
+		}
+		if kline.Low < current.Low {
+			current.Low = kline.Low
+		}
+		current.Close = kline.Close
+		current.Volume += kline.Volume
+	}
+
+	return result
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+func TestRollupKlines(t *testing.T) {
+	// base 為 2025-01-06（週一）00:00 UTC
+	day := func(n int, open, high, low, close float64) *model.Kline {
+		return &model.Kline{Timestamp: base.AddDate(0, 0, n), Open: open, High: high, Low: low, Close: close, Volume: 1}
+	}
+
+	tests := []struct {
+		name     string
+		klines   []*model.Kline
+		interval model.Interval
+		want     []model.Kline
+	}{
+		{
+			name:     "empty",
+			interval: model.Interval1w,
+		},
+		{
+			name:     "one week",
+			interval: model.Interval1w,
+			klines:   []*model.Kline{day(0, 10, 12, 9, 11), day(1, 11, 15, 10, 14), day(4, 14, 14, 8, 9)},
+			want: []model.Kline{
+				{Timestamp: base, Open: 10, High: 15, Low: 8, Close: 9, Volume: 3},
+			},
+		},
+		{
+			name:     "splits at monday",
+			interval: model.Interval1w,
+			klines:   []*model.Kline{day(5, 10, 11, 9, 10), day(6, 10, 12, 10, 12), day(7, 12, 13, 11, 11)},
+			want: []model.Kline{
+				{Timestamp: base, Open: 10, High: 12, Low: 9, Close: 12, Volume: 2},
+				{Timestamp: base.AddDate(0, 0, 7), Open: 12, High: 13, Low: 11, Close: 11, Volume: 1},
+			},
+		},
+		{
+			name:     "splits at the first of the month",
+			interval: model.Interval1M,
+			klines:   []*model.Kline{day(24, 10, 11, 9, 10), day(25, 10, 12, 10, 12), day(26, 12, 13, 11, 11)},
+			want: []model.Kline{
+				{Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Open: 10, High: 12, Low: 9, Close: 12, Volume: 2},
+				{Timestamp: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), Open: 12, High: 13, Low: 11, Close: 11, Volume: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rollupKlines(tt.klines, tt.interval, model.KlineAlignment{})
+			if len(got) != len(tt.want) {
+				t.Fatalf("rollupKlines() returned %d klines, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				k := *got[i]
+				if !k.Timestamp.Equal(want.Timestamp) {
+					t.Errorf("kline %d timestamp = %s, want %s", i, k.Timestamp, want.Timestamp)
+				}
+				k.Timestamp = want.Timestamp
+				if k != want {
+					t.Errorf("kline %d = %+v, want %+v", i, k, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRollupBaseInterval(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		align model.KlineAlignment
+		want  model.Interval
+	}{
+		{name: "default", want: model.Interval1d},
+		{name: "whole hour offset", align: model.KlineAlignment{Location: taipei}, want: model.Interval1h},
+		{name: "half hour offset", align: model.KlineAlignment{Location: kolkata}, want: model.Interval30m},
+		{name: "quarter past session", align: model.KlineAlignment{SessionStart: 17*time.Hour + 15*time.Minute}, want: model.Interval15m},
+		{name: "odd minute session", align: model.KlineAlignment{SessionStart: 9*time.Hour + 7*time.Minute}, want: model.Interval1m},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollupBaseInterval(tt.align, base); got != tt.want {
+				t.Errorf("rollupBaseInterval() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,20 +2,33 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
+	"golden-buy/price/internal/bus"
 	"golden-buy/price/internal/model"
 	"golden-buy/price/internal/pubsub"
 	"golden-buy/price/internal/repository"
 	"golden-buy/price/internal/simulator"
 )
 
+// fillLookback previous 填補模式往前尋找最後價格的範圍
+const fillLookback = 7 * 24 * time.Hour
+
+// maxTickRange 單次原始 tick 查詢允許的最大時間範圍
+const maxTickRange = time.Hour
+
+// followPollInterval 跟隨者讀取領導者已發布 tick 的間隔，與模擬器的更新頻率相同
+const followPollInterval = 333 * time.Millisecond
+
 // PriceService 價格服務（業務邏輯層）
 type PriceService struct {
 	simulator  *simulator.PriceSimulator
 	influxRepo *repository.InfluxDBRepository
 	publisher  *pubsub.Publisher
+	bus        bus.Publisher
+	heartbeat  time.Duration // 心跳訊息發布間隔，0 表示不發布
 }
 
 // NewPriceService 創建價格服務
@@ -23,11 +36,15 @@ func NewPriceService(
 	sim *simulator.PriceSimulator,
 	influxRepo *repository.InfluxDBRepository,
 	publisher *pubsub.Publisher,
+	busPublisher bus.Publisher,
+	heartbeatInterval time.Duration,
 ) *PriceService {
 	return &PriceService{
 		simulator:  sim,
 		influxRepo: influxRepo,
 		publisher:  publisher,
+		bus:        busPublisher,
+		heartbeat:  heartbeatInterval,
 	}
 }
 
@@ -51,9 +68,9 @@ func (s *PriceService) Start(ctx context.Context) {
 				log.Printf("寫入 InfluxDB 失敗: %v", err)
 			}
 
-			// 發布到 Redis Pub/Sub
-			if err := s.publisher.Publish(ctx, price); err != nil {
-				log.Printf("發布到 Redis 失敗: %v", err)
+			// 發布到訊息匯流排
+			if err := s.bus.Publish(ctx, price); err != nil {
+				log.Printf("發布價格事件失敗: %v", err)
 			}
 
 			// 設置 Redis 快取
@@ -61,10 +78,107 @@ func (s *PriceService) Start(ctx context.Context) {
 				log.Printf("設置 Redis 快取失敗: %v", err)
 			}
 
-			// 添加每秒價格記錄
-			if err := s.publisher.AddSecondPrice(ctx, price); err != nil {
-				log.Printf("添加每秒價格記錄失敗: %v", err)
+			// 寫入 tick 時間索引
+			if err := s.publisher.AddTick(ctx, price); err != nil {
+				log.Printf("寫入 tick 記錄失敗: %v", err)
+			}
+		}
+	}
+}
+
+// Lead 以領導者身分執行：接續最後發布的價格後啟動模擬器和價格處理，直到 ctx 取消
+func (s *PriceService) Lead(ctx context.Context) {
+	s.resume(ctx)
+
+	go s.simulator.Start(ctx)
+	if s.heartbeat > 0 {
+		go s.publishHeartbeats(ctx)
+	}
+	s.Start(ctx)
+}
+
+// publishHeartbeats 定期為每個商品發布心跳訊息，直到 ctx 取消
+// 讓訂閱者能分辨價格沒有變化和價格來源已停止
+func (s *PriceService) publishHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		for _, symbol := range model.AllSymbols {
+			price := s.simulator.GetCurrentPrice(symbol)
+			if price == nil {
+				continue
+			}
+			price.Timestamp = now
+			price.Heartbeat = true
+
+			if err := s.bus.Publish(ctx, price); err != nil && ctx.Err() == nil {
+				log.Printf("發布 %s 心跳訊息失敗: %v", symbol, err)
+			}
+		}
+	}
+}
+
+// resume 以最後發布的價格恢復模擬器狀態，避免領導者切換或重啟後價格跳回初始值
+// 優先使用 Redis 快取，快取過期時使用 InfluxDB 中的最新價格
+func (s *PriceService) resume(ctx context.Context) {
+	for _, symbol := range model.AllSymbols {
+		price, err := s.publisher.GetCache(ctx, symbol)
+		if err != nil {
+			price, err = s.influxRepo.GetLatestPrice(ctx, symbol)
+		}
+		if err != nil || price == nil {
+			log.Printf("找不到 %s 的最後發布價格，從目前狀態開始: %v", symbol, err)
+			continue
+		}
+
+		s.simulator.Restore(price)
+	}
+}
+
+// Follow 以跟隨者身分執行：定期讀取領導者寫入的 tick 並同步到模擬器，直到 ctx 取消
+// 讓跟隨者的即時價格查詢和價格串流與領導者一致
+func (s *PriceService) Follow(ctx context.Context) {
+	since := make(map[model.Symbol]time.Time, len(model.AllSymbols))
+	for _, symbol := range model.AllSymbols {
+		since[symbol] = time.Now()
+		if price, err := s.publisher.GetCache(ctx, symbol); err == nil {
+			s.simulator.Restore(price)
+			since[symbol] = price.Timestamp
+		}
+	}
+
+	ticker := time.NewTicker(followPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, symbol := range model.AllSymbols {
+			ticks, err := s.publisher.GetTicks(ctx, symbol, since[symbol].Add(time.Millisecond), time.Now().Add(time.Millisecond))
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("同步 %s tick 失敗: %v", symbol, err)
+				}
+				continue
 			}
+			if len(ticks) == 0 {
+				continue
+			}
+
+			s.simulator.Apply(ticks)
+			since[symbol] = ticks[len(ticks)-1].Timestamp
 		}
 	}
 }
@@ -101,9 +215,249 @@ func (s *PriceService) GetCurrentPrices(ctx context.Context, symbols []model.Sym
 	return prices, nil
 }
 
-// GetKlines 獲取 K 線資料
-func (s *PriceService) GetKlines(ctx context.Context, symbol model.Symbol, interval string, startTime, endTime int64, limit int) ([]*model.Kline, error) {
-	return s.influxRepo.GetKlines(ctx, symbol, interval, startTime, endTime, limit)
+// GetKlines 獲取 K 線資料（分頁）
+// Direction 決定從範圍的哪一端取 Limit 根，Cursor 為上一頁返回的 NextCursor，返回的 K 線一律依時間升序
+// Fill 決定缺口的填補方式，填補後的 K 線同樣計入 Total 和分頁
+// 每頁只讀取游標之後的 Limit 根 K 線，不讀取整個範圍；Total 在第一頁計算後由游標帶到後續頁
+func (s *PriceService) GetKlines(ctx context.Context, query model.KlineQuery) (*model.KlinePage, error) {
+	// 游標的邊界為上一頁最外側 K 線的開始時間，排除已返回過的 K 線
+	cursor := klineCursor{Boundary: -1, Total: -1}
+	if query.Cursor != "" {
+		var err error
+		cursor, err = decodeKlineCursor(query.Cursor, query.Direction)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if query.Fill != model.KlineFillNone || !isStoredInterval(query) {
+		return s.getSlotKlines(ctx, query, cursor.Boundary)
+	}
+
+	return s.getStoredKlines(ctx, query, cursor)
+}
+
+// getStoredKlines 直接從存儲層讀取的週期：游標邊界、排序方向和數量都由 Flux 查詢處理
+func (s *PriceService) getStoredKlines(ctx context.Context, query model.KlineQuery, cursor klineCursor) (*model.KlinePage, error) {
+	total := cursor.Total
+	if total < 0 {
+		var err error
+		total, err = s.influxRepo.CountKlines(ctx, query.Symbol, string(query.Interval), query.StartTime, query.EndTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+	page := &model.KlinePage{Total: total}
+
+	boundary := cursor.Boundary
+	start, end := query.StartTime, query.EndTime
+	if boundary >= 0 {
+		if query.Direction == model.KlineDirectionLatest {
+			end = min(end, boundary)
+		} else {
+			start = max(start, boundary+1)
+		}
+	}
+	if start >= end {
+		return page, nil
+	}
+
+	// 多讀取一根判斷是否還有下一頁
+	fetch := 0
+	if query.Limit > 0 {
+		fetch = query.Limit + 1
+	}
+	desc := query.Direction == model.KlineDirectionLatest
+
+	klines, partial, err := s.influxRepo.GetKlines(ctx, query.Symbol, string(query.Interval), start, end, desc, fetch)
+	if err != nil {
+		return nil, err
+	}
+	page.Partial = partial
+
+	if query.Limit <= 0 || len(klines) <= query.Limit {
+		page.Klines = klines
+		return page, nil
+	}
+
+	// 還有更多資料，截取本頁並產生下一頁游標
+	if desc {
+		page.Klines = klines[len(klines)-query.Limit:]
+		page.NextCursor = encodeKlineCursor(query.Direction, klineCursor{Boundary: page.Klines[0].Timestamp.UnixMilli(), Total: total})
+	} else {
+		page.Klines = klines[:query.Limit]
+		page.NextCursor = encodeKlineCursor(query.Direction, klineCursor{Boundary: page.Klines[query.Limit-1].Timestamp.UnixMilli(), Total: total})
+	}
+
+	return page, nil
+}
+
+// getSlotKlines 需要填補或彙總的週期：先依週期切出本頁的 Limit 個區間，只讀取這段範圍
+// Total 為範圍內的區間數；不填補時沒有資料的區間不返回 K 線，一頁可能少於 Limit 根
+func (s *PriceService) getSlotKlines(ctx context.Context, query model.KlineQuery, boundary int64) (*model.KlinePage, error) {
+	end := time.UnixMilli(query.EndTime)
+	if now := time.Now(); query.Fill != model.KlineFillNone && end.After(now) {
+		end = now
+	}
+
+	// 彙總從第一根 K 線的開頭讀取，第一個區間可能早於 StartTime
+	slots, err := klineSlots(query.Interval, query.Alignment, time.UnixMilli(query.StartTime), end, !isStoredInterval(query))
+	if err != nil {
+		return nil, err
+	}
+	page := &model.KlinePage{Total: len(slots)}
+
+	if boundary >= 0 {
+		remaining := make([]time.Time, 0, len(slots))
+		for _, slot := range slots {
+			ts := slot.UnixMilli()
+			if (query.Direction == model.KlineDirectionLatest && ts < boundary) ||
+				(query.Direction == model.KlineDirectionOldest && ts > boundary) {
+				remaining = append(remaining, slot)
+			}
+		}
+		slots = remaining
+	}
+	if len(slots) == 0 {
+		return page, nil
+	}
+
+	if query.Limit > 0 && len(slots) > query.Limit {
+		if query.Direction == model.KlineDirectionLatest {
+			slots = slots[len(slots)-query.Limit:]
+			page.NextCursor = encodeKlineCursor(query.Direction, klineCursor{Boundary: slots[0].UnixMilli(), Total: page.Total})
+		} else {
+			slots = slots[:query.Limit]
+			page.NextCursor = encodeKlineCursor(query.Direction, klineCursor{Boundary: slots[len(slots)-1].UnixMilli(), Total: page.Total})
+		}
+	}
+
+	pageQuery := query
+	pageQuery.StartTime = max(query.StartTime, slots[0].UnixMilli())
+	pageQuery.EndTime = min(query.EndTime, query.Interval.Next(slots[len(slots)-1], query.Alignment).UnixMilli())
+
+	klines, partial, err := s.loadKlines(ctx, pageQuery)
+	if err != nil {
+		return nil, err
+	}
+	page.Partial = partial
+
+	if query.Fill != model.KlineFillNone {
+		klines, err = s.fillKlines(ctx, pageQuery, klines)
+		if err != nil {
+			return nil, err
+		}
+	}
+	page.Klines = klines
+
+	return page, nil
+}
+
+// isStoredInterval 是否可直接從存儲層讀取，週線、月線以及非預設對齊的日線需要彙總
+func isStoredInterval(query model.KlineQuery) bool {
+	return !query.Interval.IsCalendar() || (query.Interval == model.Interval1d && query.Alignment.IsDefault())
+}
+
+// loadKlines 讀取範圍內所有 K 線
+// 週線、月線以及非預設對齊的日線由較小週期的 K 線彙總，其餘直接從存儲層讀取
+func (s *PriceService) loadKlines(ctx context.Context, query model.KlineQuery) ([]*model.Kline, bool, error) {
+	interval := query.Interval
+	if isStoredInterval(query) {
+		return s.influxRepo.GetKlines(ctx, query.Symbol, string(interval), query.StartTime, query.EndTime, false, 0)
+	}
+
+	// 從第一根 K 線的開頭讀取，避免第一根 K 線只彙總到一部分
+	start := interval.Truncate(time.UnixMilli(query.StartTime), query.Alignment)
+	base := rollupBaseInterval(query.Alignment, start)
+
+	klines, partial, err := s.influxRepo.GetKlines(ctx, query.Symbol, string(base), start.UnixMilli(), query.EndTime, false, 0)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return rollupKlines(klines, interval, query.Alignment), partial, nil
+}
+
+// fillKlines 填補 K 線缺口，不填補尚未開始的未來區間
+func (s *PriceService) fillKlines(ctx context.Context, query model.KlineQuery, klines []*model.Kline) ([]*model.Kline, error) {
+	end := time.UnixMilli(query.EndTime)
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+
+	// previous 模式需要範圍開頭之前的最後價格來填補開頭的缺口
+	var prevClose float64
+	var hasPrev bool
+	if query.Fill == model.KlineFillPrevious {
+		var err error
+		prevClose, hasPrev, err = s.influxRepo.GetLastCloseBefore(ctx, query.Symbol, query.StartTime, fillLookback)
+		if err != nil {
+			log.Printf("獲取 %s 前一收盤價失敗，不填補開頭缺口: %v", query.Symbol, err)
+		}
+	}
+
+	return fillKlines(klines, query.Interval, query.Alignment, time.UnixMilli(query.StartTime), end, query.Fill, prevClose, hasPrev)
+}
+
+// GetStatistics 獲取範圍 [start, end) 內的市場統計
+func (s *PriceService) GetStatistics(ctx context.Context, symbol model.Symbol, start, end time.Time) (*model.Statistics, error) {
+	return s.influxRepo.GetStatistics(ctx, symbol, start, end)
+}
+
+// GetTicks 分頁獲取範圍 [StartTime, EndTime) 內的原始 tick，範圍不可超過 maxTickRange
+// 分頁標記帶有第一頁的查詢範圍，後續頁沿用該範圍；StartTime、EndTime 為 0 時沿用，否則必須與標記相同
+func (s *PriceService) GetTicks(ctx context.Context, query model.TickQuery) (*model.TickPage, error) {
+	var token tickPageToken
+	if query.PageToken != "" {
+		var err error
+		token, err = decodeTickPageToken(query.PageToken, query.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if (query.StartTime != 0 && query.StartTime != token.StartTime) ||
+			(query.EndTime != 0 && query.EndTime != token.EndTime) {
+			return nil, fmt.Errorf("%w: 分頁標記的查詢範圍與請求不符", model.ErrInvalidQuery)
+		}
+		query.StartTime, query.EndTime = token.StartTime, token.EndTime
+	}
+
+	start := time.UnixMilli(query.StartTime)
+	end := time.UnixMilli(query.EndTime)
+
+	if !start.Before(end) {
+		return nil, fmt.Errorf("%w: start_time 必須早於 end_time", model.ErrInvalidQuery)
+	}
+	if end.Sub(start) > maxTickRange {
+		return nil, fmt.Errorf("%w: 查詢範圍 %s 超過上限 %s", model.ErrInvalidQuery, end.Sub(start), maxTickRange)
+	}
+
+	// 從上一頁最後一筆 tick 之後繼續
+	if query.PageToken != "" {
+		if next := token.Last.Add(time.Nanosecond); next.After(start) {
+			start = next
+		}
+		if !start.Before(end) {
+			return &model.TickPage{}, nil
+		}
+	}
+
+	// 多讀取一筆判斷是否還有下一頁
+	ticks, err := s.influxRepo.GetTicks(ctx, query.Symbol, start, end, query.PageSize+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.TickPage{Ticks: ticks}
+	if len(ticks) > query.PageSize {
+		page.Ticks = ticks[:query.PageSize]
+		page.NextPageToken = encodeTickPageToken(query.Symbol, tickPageToken{
+			StartTime: query.StartTime,
+			EndTime:   query.EndTime,
+			Last:      page.Ticks[query.PageSize-1].Timestamp,
+		})
+	}
+
+	return page, nil
 }
 
 // SubscribePrices 訂閱價格更新
@@ -122,3 +476,8 @@ func (s *PriceService) UnsubscribePrices(ch chan *model.Price) {
 func (s *PriceService) GetSecondPrices(ctx context.Context, symbol model.Symbol, timestamp time.Time) ([]*model.Price, error) {
 	return s.publisher.GetSecondPrices(ctx, symbol, timestamp)
 }
+
+// GetRecentTicks 從 Redis 獲取最近 window 內的所有 tick，window 不超過 REDIS_TICK_RETENTION
+func (s *PriceService) GetRecentTicks(ctx context.Context, symbol model.Symbol, window time.Duration) ([]*model.Price, error) {
+	return s.publisher.GetRecentTicks(ctx, symbol, window)
+}
@@ -22,11 +22,19 @@ type PriceSimulator struct {
 	subMu       sync.Mutex
 }
 
+// maxTickVolume 每筆 tick 的最大模擬成交量（口）
+const maxTickVolume = 100
+
+// RandomVolume 產生一筆 tick 的模擬成交量，為 1 到 maxTickVolume 的整數口數
+func RandomVolume() float64 {
+	return float64(1 + rand.Intn(maxTickVolume))
+}
+
 // PriceState 價格狀態
 type PriceState struct {
 	CurrentPrice  float64
 	PreviousPrice float64
-	LastUpdate    time.Time
+	LastUpdate    time.Time // 零值表示尚未產生或恢復過價格
 }
 
 // NewPriceSimulator 創建價格模擬器
@@ -40,12 +48,12 @@ func NewPriceSimulator(interval time.Duration, volatility float64) *PriceSimulat
 	}
 
 	// 初始化所有商品的價格
+	// LastUpdate 保持零值，讓 Restore 和 Apply 能接續任何已發布的價格（即使早於本實例啟動）
 	for _, symbol := range model.AllSymbols {
 		initialPrice := model.GetInitialPrice(symbol)
 		sim.prices[symbol] = &PriceState{
 			CurrentPrice:  initialPrice,
 			PreviousPrice: initialPrice,
-			LastUpdate:    time.Now(),
 		}
 	}
 
@@ -129,6 +137,7 @@ func (s *PriceSimulator) generatePrices() {
 			Timestamp:     now,
 			Change:        change,
 			ChangePercent: changePercentValue,
+			Volume:        RandomVolume(),
 		}
 
 		prices = append(prices, price)
@@ -142,13 +151,55 @@ func (s *PriceSimulator) generatePrices() {
 	}
 }
 
-// GetCurrentPrice 獲取指定商品的當前價格
+// Restore 以已發布的價格恢復商品狀態，不通知訂閱者
+// 用於成為領導者前接續上一個領導者最後發布的價格；早於目前狀態的價格會被忽略
+func (s *PriceSimulator) Restore(price *model.Price) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.prices[price.Symbol]
+	if !ok || !price.Timestamp.After(state.LastUpdate) || price.Price <= 0 {
+		return
+	}
+
+	previous := price.Price - price.Change
+	if previous <= 0 {
+		previous = price.Price
+	}
+
+	state.CurrentPrice = price.Price
+	state.PreviousPrice = previous
+	state.LastUpdate = price.Timestamp
+}
+
+// Apply 套用其他實例發布的價格並通知訂閱者
+// 跟隨者以此同步狀態，讓查詢和價格串流與領導者一致；早於目前狀態的價格會被忽略
+func (s *PriceSimulator) Apply(prices []*model.Price) {
+	s.mu.Lock()
+	applied := make([]*model.Price, 0, len(prices))
+	for _, price := range prices {
+		state, ok := s.prices[price.Symbol]
+		if !ok || !price.Timestamp.After(state.LastUpdate) {
+			continue
+		}
+
+		state.PreviousPrice = state.CurrentPrice
+		state.CurrentPrice = price.Price
+		state.LastUpdate = price.Timestamp
+		applied = append(applied, price)
+	}
+	s.mu.Unlock()
+
+	s.notifySubscribers(applied)
+}
+
+// GetCurrentPrice 獲取指定商品的當前價格，尚未產生或恢復過價格時返回 nil
 func (s *PriceSimulator) GetCurrentPrice(symbol model.Symbol) *model.Price {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	state, ok := s.prices[symbol]
-	if !ok {
+	if !ok || state.LastUpdate.IsZero() {
 		return nil
 	}
 
@@ -161,13 +212,16 @@ func (s *PriceSimulator) GetCurrentPrice(symbol model.Symbol) *model.Price {
 	}
 }
 
-// GetAllPrices 獲取所有商品的當前價格
+// GetAllPrices 獲取所有商品的當前價格，略過尚未產生或恢復過價格的商品
 func (s *PriceSimulator) GetAllPrices() []*model.Price {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	prices := make([]*model.Price, 0, len(s.prices))
 	for symbol, state := range s.prices {
+		if state.LastUpdate.IsZero() {
+			continue
+		}
 		prices = append(prices, &model.Price{
 			Symbol:        symbol,
 			Price:         state.CurrentPrice,
@@ -0,0 +1,110 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	"golden-buy/price/internal/model"
+)
+
+func TestRestore(t *testing.T) {
+	// 上一個領導者在本實例啟動前發布的價格
+	published := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		prepare   func(s *PriceSimulator)
+		price     *model.Price
+		wantPrice float64
+		wantTime  time.Time
+	}{
+		{
+			name:      "fresh simulator resumes an earlier price",
+			price:     &model.Price{Symbol: model.SymbolGold, Price: 2100, Timestamp: published, Change: 5},
+			wantPrice: 2100,
+			wantTime:  published,
+		},
+		{
+			name: "older price is ignored",
+			prepare: func(s *PriceSimulator) {
+				s.Restore(&model.Price{Symbol: model.SymbolGold, Price: 2200, Timestamp: published})
+			},
+			price:     &model.Price{Symbol: model.SymbolGold, Price: 2100, Timestamp: published.Add(-time.Second)},
+			wantPrice: 2200,
+			wantTime:  published,
+		},
+		{
+			name:      "invalid price is ignored",
+			price:     &model.Price{Symbol: model.SymbolGold, Price: 0, Timestamp: published},
+			wantPrice: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPriceSimulator(time.Second, 0.001)
+			if tt.prepare != nil {
+				tt.prepare(s)
+			}
+			s.Restore(tt.price)
+
+			got := s.GetCurrentPrice(model.SymbolGold)
+			if tt.wantPrice == 0 {
+				if got != nil {
+					t.Fatalf("GetCurrentPrice() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Price != tt.wantPrice || !got.Timestamp.Equal(tt.wantTime) {
+				t.Fatalf("GetCurrentPrice() = %+v, want %v at %s", got, tt.wantPrice, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestRestoreContinuesGeneration(t *testing.T) {
+	s := NewPriceSimulator(time.Second, 0)
+	ch := s.Subscribe()
+	defer s.Unsubscribe(ch)
+
+	s.Restore(&model.Price{Symbol: model.SymbolGold, Price: 2100, Timestamp: time.Now().Add(-time.Minute), Change: 10})
+	if len(ch) != 0 {
+		t.Fatalf("Restore() notified %d prices, want 0", len(ch))
+	}
+
+	// 波動率為 0 時價格不變，應接續恢復的價格而非初始價格
+	s.generatePrices()
+
+	for range model.AllSymbols {
+		price := <-ch
+		if price.Symbol != model.SymbolGold {
+			continue
+		}
+		if price.Price != 2100 || price.Change != 10 {
+			t.Errorf("generated price = %+v, want 2100 with change 10 from the restored previous price", price)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	s := NewPriceSimulator(time.Second, 0.001)
+	ch := s.Subscribe()
+	defer s.Unsubscribe(ch)
+
+	base := time.Now().Add(-time.Minute)
+	s.Apply([]*model.Price{
+		{Symbol: model.SymbolGold, Price: 2100, Timestamp: base},
+		{Symbol: model.SymbolGold, Price: 2090, Timestamp: base.Add(-time.Second)}, // 較舊，略過
+		{Symbol: model.SymbolGold, Price: 2105, Timestamp: base.Add(time.Second)},
+	})
+
+	if len(ch) != 2 {
+		t.Fatalf("notified %d prices, want 2", len(ch))
+	}
+	if got := s.GetCurrentPrice(model.SymbolGold); got.Price != 2105 || got.Change != 5 {
+		t.Errorf("GetCurrentPrice() = %+v, want 2105 with change 5", got)
+	}
+	if got := s.GetCurrentPrice(model.SymbolSilver); got != nil {
+		t.Errorf("GetCurrentPrice(SILVER) = %+v, want nil before any price", got)
+	}
+}
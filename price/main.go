@@ -10,7 +10,11 @@ import (
 	"syscall"
 	"time"
 
+	"golden-buy/price/internal/bus"
+	"golden-buy/price/internal/codec"
 	"golden-buy/price/internal/config"
+	"golden-buy/price/internal/downsampler"
+	"golden-buy/price/internal/election"
 	grpcServer "golden-buy/price/internal/grpc"
 	"golden-buy/price/internal/pubsub"
 	"golden-buy/price/internal/repository"
@@ -35,6 +39,9 @@ func main() {
 
 	// 1. 載入配置
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("配置無效: %v", err)
+	}
 	log.Printf("配置載入完成: %+v", cfg)
 
 	// 2. 連接 InfluxDB
@@ -43,6 +50,12 @@ func main() {
 		cfg.InfluxDB.Token,
 		cfg.InfluxDB.Org,
 		cfg.InfluxDB.Bucket,
+		repository.QueryOptions{
+			Timeout:      cfg.InfluxDB.QueryTimeout,
+			MaxPoints:    cfg.InfluxDB.MaxPoints,
+			MaxRawRange:  cfg.InfluxDB.MaxRawRange,
+			ServerParams: cfg.InfluxDB.ServerParams,
+		},
 	)
 	if err != nil {
 		log.Fatalf("連接 InfluxDB 失敗: %v", err)
@@ -51,10 +64,19 @@ func main() {
 	log.Println("InfluxDB 連接成功")
 
 	// 3. 連接 Redis
+	priceCodec, err := codec.New(cfg.Bus.Encoding, cfg.Bus.SourceID)
+	if err != nil {
+		log.Fatalf("創建價格編碼器失敗: %v", err)
+	}
+
 	redisPublisher, err := pubsub.NewPublisher(
 		cfg.Redis.Addr,
 		cfg.Redis.Password,
 		cfg.Redis.DB,
+		pubsub.PublisherOptions{
+			TickRetention: cfg.Redis.TickRetention,
+			Codec:         priceCodec,
+		},
 	)
 	if err != nil {
 		log.Fatalf("連接 Redis 失敗: %v", err)
@@ -62,26 +84,85 @@ func main() {
 	defer redisPublisher.Close()
 	log.Println("Redis 連接成功")
 
-	// 4. 創建價格模擬器
+	// 4. 連接訊息匯流排
+	busPublisher, err := bus.NewPublisher(cfg.Bus.Kind, bus.Options{
+		Codec: priceCodec,
+		Redis: bus.RedisOptions{
+			Addr:         cfg.Redis.Addr,
+			Password:     cfg.Redis.Password,
+			DB:           cfg.Redis.DB,
+			Transport:    cfg.Redis.Transport,
+			StreamMaxLen: cfg.Redis.StreamMaxLen,
+
+			AggregateChannel: cfg.Redis.Aggregate,
+		},
+		NATS: bus.NATSOptions{
+			URL:       cfg.NATS.URL,
+			Subject:   cfg.NATS.Subject,
+			JetStream: cfg.NATS.JetStream,
+			Stream:    cfg.NATS.Stream,
+		},
+	})
+	if err != nil {
+		log.Fatalf("連接訊息匯流排失敗: %v", err)
+	}
+	defer busPublisher.Close()
+	log.Printf("訊息匯流排連接成功: %s（編碼: %s，來源: %s）", cfg.Bus.Kind, cfg.Bus.Encoding, cfg.Bus.SourceID)
+
+	// 5. 創建價格模擬器
 	simulator := simulator.NewPriceSimulator(cfg.Simulator.Interval, cfg.Simulator.Volatility)
 	log.Println("價格模擬器創建成功")
 
-	// 5. 創建業務邏輯服務
-	priceService := service.NewPriceService(simulator, influxRepo, redisPublisher)
+	// 6. 創建業務邏輯服務
+	priceService := service.NewPriceService(simulator, influxRepo, redisPublisher, busPublisher, cfg.Bus.HeartbeatInterval)
 	log.Println("業務邏輯服務創建成功")
 
-	// 6. 啟動價格模擬器（goroutine）
+	// 7. 啟動價格模擬器、價格處理和 K 線降採樣（啟用選舉時只有領導者執行）
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go simulator.Start(ctx)
-	log.Println("價格模擬器已啟動")
+	var klineDownsampler *downsampler.KlineDownsampler
+	if cfg.Downsample.Enabled {
+		klineDownsampler = downsampler.NewKlineDownsampler(influxRepo, cfg.Downsample.Period, cfg.Downsample.Backfill)
+	}
+
+	lead := func(ctx context.Context) {
+		if klineDownsampler != nil {
+			go klineDownsampler.Start(ctx)
+		}
+		priceService.Lead(ctx)
+	}
 
-	// 啟動價格處理服務
-	go priceService.Start(ctx)
-	log.Println("價格處理服務已啟動")
+	if cfg.Election.Enabled {
+		elector, err := election.NewElector(
+			cfg.Redis.Addr,
+			cfg.Redis.Password,
+			cfg.Redis.DB,
+			election.Options{
+				ID:            fmt.Sprintf("%s-%d", cfg.Bus.SourceID, os.Getpid()),
+				LeaseTTL:      cfg.Election.LeaseTTL,
+				RenewInterval: cfg.Election.RenewInterval,
+			},
+		)
+		if err != nil {
+			log.Fatalf("創建領導者選舉失敗: %v", err)
+		}
+		defer elector.Close()
+
+		electionDone := make(chan struct{})
+		go func() {
+			defer close(electionDone)
+			elector.Run(ctx, lead, priceService.Follow)
+		}()
+		// 關閉時等待卸任並釋放租約，讓其他實例立即接手
+		defer func() { <-electionDone }()
+		log.Println("領導者選舉已啟動")
+	} else {
+		go lead(ctx)
+		log.Println("價格模擬器已啟動")
+	}
 
-	// 7. 啟動 gRPC 服務器
+	// 8. 啟動 gRPC 服務器
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPC.Port))
 	if err != nil {
 		log.Fatalf("啟動 gRPC 監聽失敗: %v", err)
@@ -100,14 +181,14 @@ func main() {
 		}
 	}()
 
-	// 8. 等待關閉信號
+	// 9. 等待關閉信號
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	<-sigChan
 	log.Println("收到關閉信號，優雅關閉中...")
 
-	// 9. 清理資源
+	// 10. 清理資源
 	cancel() // 停止所有 goroutine
 	server.GracefulStop()
 	log.Println("gRPC 服務器已停止")
@@ -1,6 +1,6 @@
 import axios from 'axios'
 import type { AxiosInstance } from 'axios'
-import type { ApiResponse, Price, PriceMap, KlineQuery, KlineResponse, User } from '../types'
+import type { ApiResponse, Price, PriceMap, KlineQuery, KlineResponse, IndicatorQuery, IndicatorResponse, StatisticsQuery, Statistics, TickQuery, TickResponse, User } from '../types'
 
 // 創建 Axios 實例
 const api: AxiosInstance = axios.create({
@@ -48,6 +48,21 @@ export const priceApi = {
   // 獲取 K 線資料
   getKlines: (params: KlineQuery) => {
     return api.get<any, ApiResponse<KlineResponse>>('/api/prices/history', { params })
+  },
+
+  // 獲取技術指標
+  getIndicators: (params: IndicatorQuery) => {
+    return api.get<any, ApiResponse<IndicatorResponse>>('/api/prices/indicators', { params })
+  },
+
+  // 獲取原始 tick
+  getTicks: (params: TickQuery) => {
+    return api.get<any, ApiResponse<TickResponse>>('/api/prices/ticks', { params })
+  },
+
+  // 獲取市場統計
+  getStatistics: (params: StatisticsQuery) => {
+    return api.get<any, ApiResponse<Statistics>>('/api/prices/statistics', { params })
   }
 }
 
@@ -1,6 +1,6 @@
 import { defineStore } from 'pinia'
 import { ref, computed } from 'vue'
-import type { MetalSymbol, Price, PriceMap, Kline } from '../types'
+import type { MetalSymbol, Price, PriceMap, Kline, WSFeedStatus, WSHaltStatus } from '../types'
 import { priceApi } from '../api'
 import { wsService } from '../api/websocket'
 
@@ -74,11 +74,33 @@ export const usePriceStore = defineStore('price', () => {
           price: data.price,
           change_percent: data.change_percent,
           timestamp: data.timestamp,
-          updated_at: new Date(data.timestamp).toISOString()
+          updated_at: new Date(data.timestamp).toISOString(),
+          stale: data.stale
         }
         console.log('✅ 價格已更新:', data.symbol, '=', data.price)
       })
 
+      // 價格來源中斷或恢復時標記價格
+      wsService.onMessage((message) => {
+        if (message.type !== 'feed_status' || !message.data) return
+        const status = message.data as WSFeedStatus
+        const price = prices.value[status.symbol]
+        if (price) {
+          price.stale = status.status === 'stale'
+          price.last_update_age_ms = status.age_ms
+        }
+      })
+
+      // 商品暫停或恢復時標記價格
+      wsService.onMessage((message) => {
+        if (message.type !== 'halt_status' || !message.data) return
+        const status = message.data as WSHaltStatus
+        const price = prices.value[status.symbol]
+        if (price) {
+          price.halted = status.halted
+        }
+      })
+
       // 訂閱所有商品
       console.log('📡 訂閱所有商品...')
       wsService.subscribe(['GOLD', 'SILVER', 'PLATINUM', 'PALLADIUM'])
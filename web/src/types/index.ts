@@ -16,6 +16,9 @@ export interface Price {
   change_percent: number
   timestamp: number
   updated_at: string
+  stale?: boolean // 價格來源超過門檻時間沒有更新
+  last_update_age_ms?: number
+  halted?: boolean // 商品暫停中，價格為暫停前最後推送的價格
 }
 
 // 價格列表
@@ -29,15 +32,21 @@ export interface Kline {
   low: number
   close: number
   volume: number
+  filled?: boolean
 }
 
 // K 線查詢參數
 export interface KlineQuery {
   symbol: MetalSymbol
-  interval: '1m' | '5m' | '15m' | '30m' | '1h' | '4h' | '1d'
+  interval: '1s' | '1m' | '3m' | '5m' | '15m' | '30m' | '1h' | '2h' | '4h' | '1d' | '1w' | '1M'
   start?: number
   end?: number
   limit?: number
+  direction?: 'latest' | 'oldest'
+  cursor?: string
+  fill?: 'none' | 'previous' | 'null'
+  timezone?: string
+  session_start?: string
 }
 
 // K 線回應
@@ -45,9 +54,100 @@ export interface KlineResponse {
   symbol: MetalSymbol
   interval: string
   count: number
+  total: number
+  next_cursor?: string
+  partial?: boolean
   klines: Kline[]
 }
 
+// 原始 tick 查詢參數
+export interface TickQuery {
+  symbol: MetalSymbol
+  start?: number
+  end?: number
+  at?: number // 查詢該秒內的所有 tick
+  page_size?: number
+  page_token?: string
+}
+
+// 原始 tick
+export interface Tick {
+  symbol: MetalSymbol
+  price: number
+  timestamp: number
+  change: number
+  change_percent: number
+}
+
+// 原始 tick 回應
+export interface TickResponse {
+  symbol: MetalSymbol
+  count: number
+  next_page_token?: string
+  ticks: Tick[]
+}
+
+// 技術指標查詢參數
+export interface IndicatorQuery {
+  symbol: MetalSymbol
+  interval?: KlineQuery['interval']
+  indicators: string // 例如 sma:20,bollinger:20:2,rsi:14,macd:12:26:9
+  start?: number
+  end?: number
+  limit?: number
+  timezone?: string
+  session_start?: string
+}
+
+// 技術指標的一條線
+export interface IndicatorLine {
+  name: string
+  points: { timestamp: number; value: number }[]
+}
+
+// 技術指標計算結果
+export interface IndicatorSeries {
+  name: 'sma' | 'ema' | 'bollinger' | 'rsi' | 'macd'
+  params: number[]
+  lines: IndicatorLine[]
+}
+
+// 技術指標回應
+export interface IndicatorResponse {
+  symbol: MetalSymbol
+  interval: string
+  indicators: IndicatorSeries[]
+  partial?: boolean
+}
+
+// 市場統計查詢參數
+export interface StatisticsQuery {
+  symbol: MetalSymbol
+  range?: string // 30m、24h（預設）、7d、4w 或 ytd
+  start?: number
+  end?: number
+  timezone?: string
+}
+
+// 市場統計
+export interface Statistics {
+  symbol: MetalSymbol
+  start_time: number
+  end_time: number
+  open: number
+  high: number
+  low: number
+  close: number
+  mean: number
+  vwap: number // 成交量加權平均價，範圍內沒有成交量時為 0
+  twap: number
+  change: number
+  change_percent: number
+  tick_count: number
+  volatility: number // 1 分鐘對數報酬的已實現波動率（未年化）
+  volume: number
+}
+
 // 用戶資料
 export interface User {
   id: string
@@ -58,7 +158,7 @@ export interface User {
 }
 
 // WebSocket 消息類型
-export type WSMessageType = 'connected' | 'subscribed' | 'unsubscribed' | 'price_update' | 'error' | 'pong'
+export type WSMessageType = 'connected' | 'subscribed' | 'unsubscribed' | 'price_update' | 'feed_status' | 'halt_status' | 'error' | 'pong'
 
 // WebSocket 消息
 export interface WSMessage {
@@ -82,6 +182,29 @@ export interface WSPriceUpdate {
   price: number
   change_percent: number
   timestamp: number
+  strategy?: string // 選出此價格的策略
+  window_start?: number // 聚合窗口開始時間（Unix 毫秒），K 線依此分桶
+  window?: number // 聚合窗口長度（毫秒）
+  stale: boolean // 窗口結束後超過門檻時間才推送的延遲價格
+}
+
+// 價格來源狀態消息（live <-> stale 變化時及訂閱時發送）
+export interface WSFeedStatus {
+  symbol: MetalSymbol
+  status: 'live' | 'stale'
+  last_update: number // 最後收到價格或心跳的時間（Unix 毫秒）
+  age_ms: number
+}
+
+// 商品暫停狀態消息（暫停或恢復時及訂閱時發送）
+export interface WSHaltStatus {
+  symbol: MetalSymbol
+  halted: boolean
+  reason?: string
+  halted_at?: number // 暫停時間（Unix 毫秒）
+  resume_at?: number // 自動恢復時間（Unix 毫秒），沒有表示需手動恢復
+  rejected: number // 累計被拒絕的價格數
+  last_rejection?: string
 }
 
 // 貴金屬資訊